@@ -11,8 +11,10 @@ import (
 
 	"github.com/dushixiang/pika/internal/config"
 	"github.com/dushixiang/pika/internal/handler"
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/scheduler"
+	"github.com/dushixiang/pika/internal/service"
 	"github.com/dushixiang/pika/pkg/replace"
 	"github.com/dushixiang/pika/pkg/version"
 	"github.com/dushixiang/pika/web"
@@ -85,7 +87,7 @@ func setup(app *orz.App) error {
 	go startMetricsMonitoring(ctx, components, app.Logger())
 
 	// 启动服务监控任务调度器
-	monitorScheduler := scheduler.NewMonitorScheduler(components.MonitorService, app.Logger())
+	monitorScheduler := scheduler.NewMonitorScheduler(components.MonitorService, components.MaintenanceService, app.Logger())
 	// 将调度器注入到 MonitorService（避免循环依赖）
 	components.MonitorService.SetScheduler(monitorScheduler)
 	monitorScheduler.Start(ctx)
@@ -96,6 +98,9 @@ func setup(app *orz.App) error {
 	// 启动 DDNS 定时任务
 	go components.DDNSService.Run(ctx)
 
+	// 启动通知投递重试任务
+	go components.NotificationDeliveryService.Run(ctx)
+
 	// 设置API
 	setupApi(app, components)
 
@@ -173,6 +178,9 @@ func setupApi(app *orz.App, components *AppComponents) {
 		publicApi.GET("/agent/version", components.AgentHandler.GetAgentVersion)
 		publicApi.GET("/agent/downloads/:filename", components.AgentHandler.DownloadAgent)
 		publicApi.GET("/agent/install.sh", components.AgentHandler.GetInstallScript)
+
+		// 外部告警接入（使用用途为 alert 的 API 密钥认证，中间件只作用于该路由）
+		publicApi.POST("/alerts/alertmanager", components.AlertHandler.ReceiveAlertmanagerAlerts, APIKeyAuthMiddleware(components.ApiKeyService, models.ApiKeyScopeAlert))
 	}
 
 	// 公开接口（支持可选认证）- 已登录返回全部数据，未登录只返回公开数据
@@ -255,10 +263,57 @@ func setupApi(app *orz.App, components *AppComponents) {
 
 		// 通知渠道测试（从数据库读取配置测试）
 		adminApi.POST("/notification-channels/:type/test", components.PropertyHandler.TestNotificationChannel)
+		adminApi.POST("/notification-routes/dry-run", components.PropertyHandler.DryRunNotificationRoutes)
+		adminApi.POST("/notification-templates/preview", components.PropertyHandler.PreviewNotificationTemplate)
 
 		// 告警记录查询
 		adminApi.GET("/alert-records", components.AlertHandler.ListAlertRecords)
 		adminApi.DELETE("/alert-records", components.AlertHandler.ClearAlertRecords)
+		adminApi.GET("/alert-records/:id", components.AlertHandler.GetAlertRecord)
+		adminApi.POST("/alert-records/:id/acknowledge", components.AlertHandler.AcknowledgeAlertRecord)
+		adminApi.PUT("/alert-records/:id/assignee", components.AlertHandler.AssignAlertRecord)
+		adminApi.GET("/alert-records/:id/notes", components.AlertHandler.ListAlertNotes)
+		adminApi.POST("/alert-records/:id/notes", components.AlertHandler.AddAlertNote)
+
+		// 通知投递记录
+		adminApi.GET("/notification-deliveries", components.NotificationDeliveryHandler.Paging)
+		adminApi.POST("/notification-deliveries/:id/resend", components.NotificationDeliveryHandler.Resend)
+
+		// 告警规则集
+		adminApi.GET("/alert-rule-sets", components.AlertRuleSetHandler.Paging)
+		adminApi.POST("/alert-rule-sets", components.AlertRuleSetHandler.Create)
+		adminApi.GET("/alert-rule-sets/:id", components.AlertRuleSetHandler.Get)
+		adminApi.PUT("/alert-rule-sets/:id", components.AlertRuleSetHandler.Update)
+		adminApi.DELETE("/alert-rule-sets/:id", components.AlertRuleSetHandler.Delete)
+		adminApi.POST("/alert-rule-sets/:id/enable", components.AlertRuleSetHandler.Enable)
+		adminApi.POST("/alert-rule-sets/:id/disable", components.AlertRuleSetHandler.Disable)
+
+		// 表达式告警规则
+		adminApi.GET("/alert-expression-rules", components.AlertExpressionRuleHandler.Paging)
+		adminApi.POST("/alert-expression-rules", components.AlertExpressionRuleHandler.Create)
+		adminApi.POST("/alert-expression-rules/preview", components.AlertExpressionRuleHandler.Preview)
+		adminApi.GET("/alert-expression-rules/:id", components.AlertExpressionRuleHandler.Get)
+		adminApi.PUT("/alert-expression-rules/:id", components.AlertExpressionRuleHandler.Update)
+		adminApi.DELETE("/alert-expression-rules/:id", components.AlertExpressionRuleHandler.Delete)
+		adminApi.POST("/alert-expression-rules/:id/enable", components.AlertExpressionRuleHandler.Enable)
+		adminApi.POST("/alert-expression-rules/:id/disable", components.AlertExpressionRuleHandler.Disable)
+
+		// 告警静默
+		adminApi.GET("/alert-silences", components.AlertSilenceHandler.Paging)
+		adminApi.POST("/alert-silences", components.AlertSilenceHandler.Create)
+		adminApi.GET("/alert-silences/:id", components.AlertSilenceHandler.Get)
+		adminApi.PUT("/alert-silences/:id", components.AlertSilenceHandler.Update)
+		adminApi.DELETE("/alert-silences/:id", components.AlertSilenceHandler.Delete)
+		adminApi.POST("/alert-silences/:id/expire", components.AlertSilenceHandler.Expire)
+
+		// 维护窗口
+		adminApi.GET("/maintenance-windows", components.MaintenanceWindowHandler.Paging)
+		adminApi.POST("/maintenance-windows", components.MaintenanceWindowHandler.Create)
+		adminApi.GET("/maintenance-windows/:id", components.MaintenanceWindowHandler.Get)
+		adminApi.PUT("/maintenance-windows/:id", components.MaintenanceWindowHandler.Update)
+		adminApi.DELETE("/maintenance-windows/:id", components.MaintenanceWindowHandler.Delete)
+		adminApi.POST("/maintenance-windows/:id/enable", components.MaintenanceWindowHandler.Enable)
+		adminApi.POST("/maintenance-windows/:id/disable", components.MaintenanceWindowHandler.Disable)
 
 		// 服务监控配置
 		adminApi.GET("/monitors", components.MonitorHandler.List)
@@ -293,19 +348,25 @@ func setupApi(app *orz.App, components *AppComponents) {
 func autoMigrate(database *gorm.DB) error {
 	// 自动迁移数据库表
 	return database.AutoMigrate(
-		&models.Agent{},               // 探针
-		&models.ApiKey{},              // ApiKey
-		&models.HostMetric{},          // 保留主机静态信息表
-		&models.AuditResult{},         // 审计历史
-		&models.Property{},            // 系统属性
-		&models.AlertRecord{},         // 告警记录
-		&models.AlertState{},          // 告警状态
-		&models.MonitorTask{},         // 服务监控
-		&models.TamperProtectConfig{}, // 防篡改配置
-		&models.TamperEvent{},         // 防篡改事件
-		&models.TamperAlert{},         // 防篡改告警
-		&models.DDNSConfig{},          // DDNS 配置
-		&models.DDNSRecord{},          // DDNS 记录
+		&models.Agent{},                // 探针
+		&models.ApiKey{},               // ApiKey
+		&models.HostMetric{},           // 保留主机静态信息表
+		&models.AuditResult{},          // 审计历史
+		&models.Property{},             // 系统属性
+		&models.AlertRecord{},          // 告警记录
+		&models.AlertState{},           // 告警状态
+		&models.AlertNote{},            // 告警备注
+		&models.NotificationDelivery{}, // 通知投递记录
+		&models.AlertRuleSet{},         // 告警规则集
+		&models.AlertExpressionRule{},  // 表达式告警规则
+		&models.AlertSilence{},         // 告警静默
+		&models.MaintenanceWindow{},    // 维护窗口
+		&models.MonitorTask{},          // 服务监控
+		&models.TamperProtectConfig{},  // 防篡改配置
+		&models.TamperEvent{},          // 防篡改事件
+		&models.TamperAlert{},          // 防篡改告警
+		&models.DDNSConfig{},           // DDNS 配置
+		&models.DDNSRecord{},           // DDNS 记录
 	)
 }
 
@@ -321,6 +382,11 @@ func ErrorHandler(logger *zap.Logger) func(next echo.HandlerFunc) echo.HandlerFu
 			if err := next(c); err != nil {
 				var he *echo.HTTPError
 				if errors.As(err, &he) {
+					if msg, ok := he.Message.(string); ok {
+						// 复制一份再翻译，避免修改 echo 预定义的错误实例
+						he = &echo.HTTPError{Code: he.Code, Message: i18n.Error(msg), Internal: he.Internal}
+						err = he
+					}
 					return c.JSON(he.Code, orz.Map{
 						"code":    he.Code,
 						"message": err.Error(),
@@ -331,7 +397,7 @@ func ErrorHandler(logger *zap.Logger) func(next echo.HandlerFunc) echo.HandlerFu
 				if errors.As(err, &oe) {
 					return c.JSON(400, orz.Map{
 						"code":    oe.Code,
-						"message": err.Error(),
+						"message": i18n.Error(err.Error()),
 					})
 				}
 
@@ -380,28 +446,8 @@ func startMetricsMonitoring(ctx context.Context, components *AppComponents, logg
 					continue
 				}
 
-				// 提取 CPU、内存、磁盘使用率、网速
-				var cpuUsage, memoryUsage, diskUsage, networkSpeed float64
-
-				if latest.CPU != nil {
-					cpuUsage = latest.CPU.UsagePercent
-				}
-
-				if latest.Memory != nil {
-					memoryUsage = latest.Memory.UsagePercent
-				}
-
-				if latest.Disk != nil {
-					diskUsage = latest.Disk.UsagePercent
-				}
-
-				if latest.Network != nil {
-					// 网速 = (发送速率 + 接收速率) / 1024 / 1024 (转换为 MB/s)
-					networkSpeed = float64(latest.Network.TotalBytesSentRate+latest.Network.TotalBytesRecvRate) / 1024 / 1024
-				}
-
 				// 检查告警规则
-				if err := components.AlertService.CheckMetrics(ctx, agent.ID, cpuUsage, memoryUsage, diskUsage, networkSpeed); err != nil {
+				if err := components.AlertService.CheckMetrics(ctx, agent.ID, latest); err != nil {
 					logger.Error("检查告警规则失败", zap.String("agentId", agent.ID), zap.Error(err))
 				}
 			}
@@ -410,6 +456,26 @@ func startMetricsMonitoring(ctx context.Context, components *AppComponents, logg
 			if err := components.AlertService.CheckMonitorAlerts(ctx); err != nil {
 				logger.Error("检查监控告警失败", zap.Error(err))
 			}
+
+			// 检查 PromQL 表达式告警
+			if err := components.AlertService.CheckExpressionRules(ctx); err != nil {
+				logger.Error("检查表达式告警失败", zap.Error(err))
+			}
+
+			// 结束已停止变化的告警抖动
+			if err := components.AlertService.CheckFlapping(ctx); err != nil {
+				logger.Error("检查告警抖动失败", zap.Error(err))
+			}
+
+			// 未确认告警的重复通知与升级
+			if err := components.AlertService.CheckEscalations(ctx); err != nil {
+				logger.Error("检查告警升级失败", zap.Error(err))
+			}
+
+			// 延长 Alertmanager 中触发中告警的有效期
+			if err := components.AlertService.RefreshAlertmanagerAlerts(ctx); err != nil {
+				logger.Error("重新推送 Alertmanager 告警失败", zap.Error(err))
+			}
 		}
 	}
 }
@@ -497,4 +563,29 @@ func OptionalJWTAuthMiddleware(accountHandler *handler.AccountHandler) echo.Midd
 	}
 }
 
-// APIKeyAuthMiddleware 使用 API Key 进行认证
+// APIKeyAuthMiddleware 使用指定用途的 API Key 进行认证，支持 Authorization: Bearer <key> 和 X-API-Key 请求头
+func APIKeyAuthMiddleware(apiKeyService *service.ApiKeyService, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get("X-API-Key")
+			if authHeader := c.Request().Header.Get("Authorization"); key == "" && authHeader != "" {
+				const bearerPrefix = "Bearer "
+				if !strings.HasPrefix(authHeader, bearerPrefix) {
+					return echo.NewHTTPError(http.StatusUnauthorized, "认证令牌格式错误")
+				}
+				key = authHeader[len(bearerPrefix):]
+			}
+			if key == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "未提供 API 密钥")
+			}
+
+			apiKey, err := apiKeyService.ValidateApiKey(c.Request().Context(), key, scope)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "API 密钥无效")
+			}
+
+			c.Set("apiKeyID", apiKey.ID)
+			return next(c)
+		}
+	}
+}
@@ -2,12 +2,13 @@ package config
 
 // AppConfig 应用配置
 type AppConfig struct {
-	JWT             JWTConfig          `json:"JWT"`
-	Users           map[string]string  `json:"Users"`           // 用户名 -> bcrypt加密的密码
-	OIDC            *OIDCConfig        `json:"OIDC"`            // OIDC配置（可选）
-	GitHub          *GitHubOAuthConfig `json:"GitHub"`          // GitHub OAuth配置（可选）
-	GeoIP           *GeoIPConfig       `json:"GeoIP"`           // GeoIP配置（可选）
-	VictoriaMetrics *VMConfig          `json:"VictoriaMetrics"` // VictoriaMetrics配置（可选）
+	JWT             JWTConfig           `json:"JWT"`
+	Users           map[string]string   `json:"Users"`           // 用户名 -> bcrypt加密的密码
+	OIDC            *OIDCConfig         `json:"OIDC"`            // OIDC配置（可选）
+	GitHub          *GitHubOAuthConfig  `json:"GitHub"`          // GitHub OAuth配置（可选）
+	GeoIP           *GeoIPConfig        `json:"GeoIP"`           // GeoIP配置（可选）
+	VictoriaMetrics *VMConfig           `json:"VictoriaMetrics"` // VictoriaMetrics配置（可选）
+	Notification    *NotificationConfig `json:"Notification"`    // 通知配置（可选）
 }
 
 // JWTConfig JWT配置
@@ -49,3 +50,8 @@ type VMConfig struct {
 	WriteTimeout  int    `json:"WriteTimeout"`  // 写入超时（秒）
 	QueryTimeout  int    `json:"QueryTimeout"`  // 查询超时（秒）
 }
+
+// NotificationConfig 通知配置
+type NotificationConfig struct {
+	ExecScriptDir string `json:"ExecScriptDir"` // exec 通知渠道允许执行的脚本目录，为空时禁用 exec 渠道
+}
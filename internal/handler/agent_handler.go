@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/dushixiang/pika"
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/service"
@@ -179,7 +180,7 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 			h.logger.Error("failed to unmarshal tamper event", zap.Error(err))
 			return err
 		}
-		return h.tamperService.CreateEvent(agentID, eventData.Path, eventData.Operation, eventData.Details, eventData.Timestamp)
+		return h.tamperService.CreateEvent(ctx, agentID, eventData.Path, eventData.Operation, eventData.Details, eventData.Timestamp)
 
 	case protocol.MessageTypeTamperAlert:
 		// 防篡改告警
@@ -188,7 +189,7 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 			h.logger.Error("failed to unmarshal tamper alert", zap.Error(err))
 			return err
 		}
-		return h.tamperService.CreateAlert(agentID, alertData.Path, alertData.Details, alertData.Restored, alertData.Timestamp)
+		return h.tamperService.CreateAlert(ctx, agentID, alertData.Path, alertData.Details, alertData.Restored, alertData.Timestamp)
 
 	case protocol.MessageTypeDDNSIPReport:
 		// DDNS IP 上报 - 异步处理，避免阻塞 WebSocket 消息循环
@@ -437,7 +438,7 @@ func (h *AgentHandler) GetMetrics(c echo.Context) error {
 	// 验证指标类型
 	validTypes := map[string]bool{
 		"cpu": true, "memory": true, "disk": true, "network": true, "network_connection": true,
-		"disk_io": true, "gpu": true, "temperature": true, "monitor": true,
+		"disk_io": true, "gpu": true, "temperature": true, "monitor": true, "load": true,
 	}
 	if metricType == "" {
 		return orz.NewError(400, "指标类型不能为空")
@@ -824,7 +825,7 @@ detect_platform() {
             ARCH="loong64"
             ;;
         *)
-            echo_error "不支持的架构: $ARCH"
+            echo_error "` + i18n.T("install.unsupported_arch") + `: $ARCH"
             exit 1
             ;;
     esac
@@ -839,12 +840,12 @@ detect_platform() {
             AGENT_NAME="pika-agent"
             ;;
         *)
-            echo_error "不支持的操作系统: $OS"
+            echo_error "` + i18n.T("install.unsupported_os") + `: $OS"
             exit 1
             ;;
     esac
 
-    echo_info "检测到平台: $PLATFORM"
+    echo_info "` + i18n.T("install.platform") + `: $PLATFORM"
 }
 
 # 下载探针
@@ -852,19 +853,19 @@ download_agent() {
     local download_url="` + serverUrl + `/api/agent/downloads/agent-$PLATFORM"
     local temp_file="/tmp/pika-agent-download"
 
-    echo_info "正在下载探针..."
+    echo_info "` + i18n.T("install.downloading") + `"
 
     if command -v wget &> /dev/null; then
         wget -q --show-progress "$download_url" -O "$temp_file"
     elif command -v curl &> /dev/null; then
         curl -# -L "$download_url" -o "$temp_file"
     else
-        echo_error "未找到 wget 或 curl 命令，请先安装其中之一"
+        echo_error "` + i18n.T("install.no_downloader") + `"
         exit 1
     fi
 
     if [ ! -f "$temp_file" ]; then
-        echo_error "下载失败"
+        echo_error "` + i18n.T("install.download_failed") + `"
         exit 1
     fi
 
@@ -872,7 +873,7 @@ download_agent() {
     mv "$temp_file" "/usr/local/bin/$AGENT_NAME"
     chmod +x "/usr/local/bin/$AGENT_NAME"
 
-    echo_info "探针下载完成: /usr/local/bin/$AGENT_NAME"
+    echo_info "` + i18n.T("install.downloaded") + `: /usr/local/bin/$AGENT_NAME"
 }
 
 # 注册并启动服务
@@ -880,13 +881,13 @@ register_agent() {
     local endpoint="` + serverUrl + `"
     local token="` + token + `"
 
-    echo_info "正在注册探针..."
+    echo_info "` + i18n.T("install.registering") + `"
     /usr/local/bin/$AGENT_NAME register --endpoint "$endpoint" --token "$token" --yes
 }
 
 # 主流程
 main() {
-    echo_info "开始安装 Pika Agent..."
+    echo_info "` + i18n.T("install.start") + `"
     echo ""
 
     detect_platform
@@ -895,15 +896,15 @@ main() {
 
     echo ""
     echo_info "=========================================="
-    echo_info "安装完成！"
+    echo_info "` + i18n.T("install.done") + `"
     echo_info "=========================================="
     echo ""
-    echo_info "常用命令："
-    echo "  查看状态: pika-agent status"
-    echo "  启动服务: pika-agent start"
-    echo "  停止服务: pika-agent stop"
-    echo "  重启服务: pika-agent restart"
-    echo "  卸载服务: pika-agent uninstall"
+    echo_info "` + i18n.T("install.commands") + `"
+    echo "  ` + i18n.T("install.cmd_status") + `: pika-agent status"
+    echo "  ` + i18n.T("install.cmd_start") + `: pika-agent start"
+    echo "  ` + i18n.T("install.cmd_stop") + `: pika-agent stop"
+    echo "  ` + i18n.T("install.cmd_restart") + `: pika-agent restart"
+    echo "  ` + i18n.T("install.cmd_uninstall") + `: pika-agent uninstall"
     echo ""
 }
 
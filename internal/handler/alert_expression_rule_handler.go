@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+type AlertExpressionRuleHandler struct {
+	logger          *zap.Logger
+	exprRuleService *service.AlertExpressionRuleService
+	alertService    *service.AlertService
+}
+
+func NewAlertExpressionRuleHandler(logger *zap.Logger, exprRuleService *service.AlertExpressionRuleService, alertService *service.AlertService) *AlertExpressionRuleHandler {
+	return &AlertExpressionRuleHandler{
+		logger:          logger,
+		exprRuleService: exprRuleService,
+		alertService:    alertService,
+	}
+}
+
+// AlertExpressionRuleRequest 创建/更新表达式告警规则请求
+type AlertExpressionRuleRequest struct {
+	Name        string            `json:"name" validate:"required"`
+	Enabled     bool              `json:"enabled"`
+	Expr        string            `json:"expr" validate:"required"`
+	For         int               `json:"for" validate:"min=0"`
+	Level       string            `json:"level" validate:"omitempty,oneof=info warning critical"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// PreviewExpressionRequest 表达式预览请求
+type PreviewExpressionRequest struct {
+	Expr string `json:"expr" validate:"required"`
+}
+
+// Paging 表达式告警规则分页查询
+func (h *AlertExpressionRuleHandler) Paging(c echo.Context) error {
+	name := c.QueryParam("name")
+
+	pr := orz.GetPageRequest(c, "created_at", "name")
+
+	builder := orz.NewPageBuilder(h.exprRuleService.AlertExpressionRuleRepo).
+		PageRequest(pr).
+		Contains("name", name)
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": page.Items,
+		"total": page.Total,
+	})
+}
+
+// Create 创建表达式告警规则
+func (h *AlertExpressionRuleHandler) Create(c echo.Context) error {
+	var req AlertExpressionRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	rule := &models.AlertExpressionRule{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Enabled:     req.Enabled,
+		Expr:        req.Expr,
+		For:         req.For,
+		Level:       req.Level,
+		Labels:      datatypes.NewJSONType(req.Labels),
+		Annotations: datatypes.NewJSONType(req.Annotations),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	ctx := c.Request().Context()
+	if err := h.exprRuleService.Create(ctx, rule); err != nil {
+		h.logger.Error("创建表达式告警规则失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, rule)
+}
+
+// Get 获取表达式告警规则详情
+func (h *AlertExpressionRuleHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	rule, err := h.exprRuleService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, rule)
+}
+
+// Update 更新表达式告警规则
+func (h *AlertExpressionRuleHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+
+	var req AlertExpressionRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	existing, err := h.exprRuleService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	previous := existing
+	existing.Name = req.Name
+	existing.Enabled = req.Enabled
+	existing.Expr = req.Expr
+	existing.For = req.For
+	existing.Level = req.Level
+	existing.Labels = datatypes.NewJSONType(req.Labels)
+	existing.Annotations = datatypes.NewJSONType(req.Annotations)
+	existing.UpdatedAt = time.Now().UnixMilli()
+
+	if err := h.exprRuleService.Save(ctx, &existing); err != nil {
+		h.logger.Error("更新表达式告警规则失败", zap.Error(err))
+		return err
+	}
+	if service.ExpressionRuleNeedsResolve(&previous, &existing) {
+		h.resolveRuleAlerts(ctx, &existing)
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "表达式告警规则更新成功",
+	})
+}
+
+// Delete 删除表达式告警规则
+func (h *AlertExpressionRuleHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	rule, err := h.exprRuleService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := h.exprRuleService.DeleteById(ctx, id); err != nil {
+		h.logger.Error("删除表达式告警规则失败", zap.Error(err))
+		return err
+	}
+	h.resolveRuleAlerts(ctx, &rule)
+
+	return orz.Ok(c, orz.Map{
+		"message": "表达式告警规则删除成功",
+	})
+}
+
+// Enable 启用表达式告警规则
+func (h *AlertExpressionRuleHandler) Enable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.exprRuleService.UpdateEnabled(ctx, id, true); err != nil {
+		h.logger.Error("启用表达式告警规则失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "表达式告警规则启用成功",
+	})
+}
+
+// Disable 禁用表达式告警规则
+func (h *AlertExpressionRuleHandler) Disable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.exprRuleService.UpdateEnabled(ctx, id, false); err != nil {
+		h.logger.Error("禁用表达式告警规则失败", zap.Error(err))
+		return err
+	}
+	if rule, err := h.exprRuleService.FindById(ctx, id); err == nil {
+		h.resolveRuleAlerts(ctx, &rule)
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "表达式告警规则禁用成功",
+	})
+}
+
+// resolveRuleAlerts 恢复规则触发的告警，失败时由下一次规则检查兜底
+func (h *AlertExpressionRuleHandler) resolveRuleAlerts(ctx context.Context, rule *models.AlertExpressionRule) {
+	if err := h.alertService.ResolveExpressionRule(ctx, rule); err != nil {
+		h.logger.Error("恢复表达式告警失败", zap.String("ruleId", rule.ID), zap.Error(err))
+	}
+}
+
+// Preview 预览表达式当前的查询结果
+func (h *AlertExpressionRuleHandler) Preview(c echo.Context) error {
+	var req PreviewExpressionRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	points, err := h.exprRuleService.Evaluate(c.Request().Context(), req.Expr)
+	if err != nil {
+		return orz.NewError(400, err.Error())
+	}
+
+	items := make([]orz.Map, 0, len(points))
+	for _, point := range points {
+		items = append(items, orz.Map{
+			"labels":    point.Labels,
+			"value":     point.Value,
+			"timestamp": point.Timestamp,
+		})
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": items,
+		"total": len(items),
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/service"
 	"github.com/go-orz/orz"
 	"github.com/labstack/echo/v4"
@@ -33,6 +35,19 @@ func (h *AlertHandler) ListAlertRecords(c echo.Context) error {
 	if agentID != "" {
 		builder.Equal("agent_id", agentID)
 	}
+	if status := c.QueryParam("status"); status != "" {
+		builder.Equal("status", status)
+	}
+	if assignee := c.QueryParam("assignee"); assignee != "" {
+		builder.Equal("assignee", assignee)
+	}
+	// 按是否被其他告警抑制过滤
+	switch c.QueryParam("inhibited") {
+	case "true":
+		builder.NotEqual("inhibited_by", 0)
+	case "false":
+		builder.Equal("inhibited_by", 0)
+	}
 
 	ctx := c.Request().Context()
 	page, err := builder.Execute(ctx)
@@ -49,7 +64,7 @@ func (h *AlertHandler) ClearAlertRecords(c echo.Context) error {
 	if err := h.alertService.Clear(c.Request().Context()); err != nil {
 		h.logger.Error("清空告警记录失败", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "清空告警记录失败",
+			"error": i18n.Error("清空告警记录失败"),
 		})
 	}
 
@@ -57,3 +72,144 @@ func (h *AlertHandler) ClearAlertRecords(c echo.Context) error {
 		"message": "清空成功",
 	})
 }
+
+// AcknowledgeAlertRequest 确认告警请求
+type AcknowledgeAlertRequest struct {
+	Comment string `json:"comment"`
+}
+
+// AssignAlertRequest 指派告警请求
+type AssignAlertRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// AddAlertNoteRequest 添加告警备注请求
+type AddAlertNoteRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+func alertRecordID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, orz.NewError(400, "无效的告警记录ID")
+	}
+	return id, nil
+}
+
+// GetAlertRecord 获取告警记录详情
+func (h *AlertHandler) GetAlertRecord(c echo.Context) error {
+	id, err := alertRecordID(c)
+	if err != nil {
+		return err
+	}
+
+	record, err := h.alertService.AlertRecordRepo.GetAlertRecordByID(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, record)
+}
+
+// AcknowledgeAlertRecord 确认告警
+func (h *AlertHandler) AcknowledgeAlertRecord(c echo.Context) error {
+	id, err := alertRecordID(c)
+	if err != nil {
+		return err
+	}
+
+	var req AcknowledgeAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	username, _ := c.Get("username").(string)
+	record, err := h.alertService.Acknowledge(c.Request().Context(), id, username, req.Comment)
+	if err != nil {
+		h.logger.Error("确认告警失败", zap.Int64("recordId", id), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, record)
+}
+
+// AssignAlertRecord 指派告警处理人
+func (h *AlertHandler) AssignAlertRecord(c echo.Context) error {
+	id, err := alertRecordID(c)
+	if err != nil {
+		return err
+	}
+
+	var req AssignAlertRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := h.alertService.Assign(c.Request().Context(), id, req.Assignee); err != nil {
+		h.logger.Error("指派告警失败", zap.Int64("recordId", id), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "指派成功",
+	})
+}
+
+// ListAlertNotes 获取告警备注
+func (h *AlertHandler) ListAlertNotes(c echo.Context) error {
+	id, err := alertRecordID(c)
+	if err != nil {
+		return err
+	}
+
+	notes, err := h.alertService.ListNotes(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": notes,
+		"total": len(notes),
+	})
+}
+
+// AddAlertNote 添加告警备注
+func (h *AlertHandler) AddAlertNote(c echo.Context) error {
+	id, err := alertRecordID(c)
+	if err != nil {
+		return err
+	}
+
+	var req AddAlertNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	username, _ := c.Get("username").(string)
+	note, err := h.alertService.AddNote(c.Request().Context(), id, username, req.Content)
+	if err != nil {
+		h.logger.Error("添加告警备注失败", zap.Int64("recordId", id), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, note)
+}
+
+// ReceiveAlertmanagerAlerts 接收 Alertmanager Webhook 推送的外部告警
+func (h *AlertHandler) ReceiveAlertmanagerAlerts(c echo.Context) error {
+	var msg service.AlertmanagerMessage
+	if err := c.Bind(&msg); err != nil {
+		return orz.NewError(400, "请求参数错误")
+	}
+
+	result, err := h.alertService.IngestAlertmanager(c.Request().Context(), &msg)
+	if err != nil {
+		h.logger.Error("接收外部告警失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, result)
+}
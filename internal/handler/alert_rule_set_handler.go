@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+type AlertRuleSetHandler struct {
+	logger         *zap.Logger
+	ruleSetService *service.AlertRuleSetService
+}
+
+func NewAlertRuleSetHandler(logger *zap.Logger, ruleSetService *service.AlertRuleSetService) *AlertRuleSetHandler {
+	return &AlertRuleSetHandler{
+		logger:         logger,
+		ruleSetService: ruleSetService,
+	}
+}
+
+// AlertRuleSetRequest 创建/更新告警规则集请求
+type AlertRuleSetRequest struct {
+	Name        string            `json:"name" validate:"required"`
+	Description string            `json:"description"`
+	Enabled     bool              `json:"enabled"`
+	Priority    int               `json:"priority"`
+	AgentIds    []string          `json:"agentIds"`
+	Tags        []string          `json:"tags"`
+	Rules       models.AlertRules `json:"rules"`
+}
+
+// Paging 告警规则集分页查询
+func (h *AlertRuleSetHandler) Paging(c echo.Context) error {
+	name := c.QueryParam("name")
+
+	pr := orz.GetPageRequest(c, "priority", "created_at", "name")
+
+	builder := orz.NewPageBuilder(h.ruleSetService.AlertRuleSetRepo).
+		PageRequest(pr).
+		Contains("name", name)
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": page.Items,
+		"total": page.Total,
+	})
+}
+
+// Create 创建告警规则集
+func (h *AlertRuleSetHandler) Create(c echo.Context) error {
+	var req AlertRuleSetRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	ruleSet := &models.AlertRuleSet{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		Enabled:     req.Enabled,
+		Priority:    req.Priority,
+		AgentIds:    req.AgentIds,
+		Tags:        req.Tags,
+		Rules:       datatypes.NewJSONType(req.Rules),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	ctx := c.Request().Context()
+	if err := h.ruleSetService.Create(ctx, ruleSet); err != nil {
+		h.logger.Error("创建告警规则集失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, ruleSet)
+}
+
+// Get 获取告警规则集详情
+func (h *AlertRuleSetHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	ruleSet, err := h.ruleSetService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, ruleSet)
+}
+
+// Update 更新告警规则集
+func (h *AlertRuleSetHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+
+	var req AlertRuleSetRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	existing, err := h.ruleSetService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	existing.Name = req.Name
+	existing.Description = req.Description
+	existing.Enabled = req.Enabled
+	existing.Priority = req.Priority
+	existing.AgentIds = req.AgentIds
+	existing.Tags = req.Tags
+	existing.Rules = datatypes.NewJSONType(req.Rules)
+	existing.UpdatedAt = time.Now().UnixMilli()
+
+	if err := h.ruleSetService.Update(ctx, &existing); err != nil {
+		h.logger.Error("更新告警规则集失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警规则集更新成功",
+	})
+}
+
+// Delete 删除告警规则集
+func (h *AlertRuleSetHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.ruleSetService.Delete(ctx, id); err != nil {
+		h.logger.Error("删除告警规则集失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警规则集删除成功",
+	})
+}
+
+// Enable 启用告警规则集
+func (h *AlertRuleSetHandler) Enable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.ruleSetService.UpdateEnabled(ctx, id, true); err != nil {
+		h.logger.Error("启用告警规则集失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警规则集启用成功",
+	})
+}
+
+// Disable 禁用告警规则集
+func (h *AlertRuleSetHandler) Disable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.ruleSetService.UpdateEnabled(ctx, id, false); err != nil {
+		h.logger.Error("禁用告警规则集失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警规则集禁用成功",
+	})
+}
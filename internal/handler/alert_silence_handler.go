@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AlertSilenceHandler struct {
+	logger         *zap.Logger
+	silenceService *service.AlertSilenceService
+}
+
+func NewAlertSilenceHandler(logger *zap.Logger, silenceService *service.AlertSilenceService) *AlertSilenceHandler {
+	return &AlertSilenceHandler{
+		logger:         logger,
+		silenceService: silenceService,
+	}
+}
+
+// AlertSilenceRequest 创建/更新告警静默请求
+type AlertSilenceRequest struct {
+	AgentID   string `json:"agentId"`
+	Tag       string `json:"tag"`
+	AlertType string `json:"alertType"`
+	MonitorID string `json:"monitorId"`
+	StartsAt  int64  `json:"startsAt"`
+	EndsAt    int64  `json:"endsAt" validate:"required"`
+	Comment   string `json:"comment"`
+}
+
+// alertSilenceView 告警静默（附带当前状态）
+type alertSilenceView struct {
+	models.AlertSilence
+	Status string `json:"status"`
+}
+
+func (req *AlertSilenceRequest) check() error {
+	if req.AgentID == "" && req.Tag == "" && req.AlertType == "" && req.MonitorID == "" {
+		return orz.NewError(400, "至少需要设置一个匹配条件")
+	}
+	if req.StartsAt == 0 {
+		req.StartsAt = time.Now().UnixMilli()
+	}
+	if req.EndsAt <= req.StartsAt {
+		return orz.NewError(400, "结束时间必须晚于开始时间")
+	}
+	return nil
+}
+
+// Paging 告警静默分页查询
+func (h *AlertSilenceHandler) Paging(c echo.Context) error {
+	agentID := c.QueryParam("agentId")
+	alertType := c.QueryParam("alertType")
+
+	pr := orz.GetPageRequest(c, "created_at", "starts_at", "ends_at")
+
+	builder := orz.NewPageBuilder(h.silenceService.AlertSilenceRepo).
+		PageRequest(pr)
+
+	if agentID != "" {
+		builder = builder.Equal("agent_id", agentID)
+	}
+	if alertType != "" {
+		builder = builder.Equal("alert_type", alertType)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	items := make([]alertSilenceView, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, alertSilenceView{
+			AlertSilence: item,
+			Status:       service.SilenceStatus(&item, now),
+		})
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": items,
+		"total": page.Total,
+	})
+}
+
+// Create 创建告警静默
+func (h *AlertSilenceHandler) Create(c echo.Context) error {
+	var req AlertSilenceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	if err := req.check(); err != nil {
+		return err
+	}
+
+	createdBy, _ := c.Get("username").(string)
+
+	now := time.Now().UnixMilli()
+	silence := &models.AlertSilence{
+		ID:        uuid.New().String(),
+		AgentID:   req.AgentID,
+		Tag:       req.Tag,
+		AlertType: req.AlertType,
+		MonitorID: req.MonitorID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: createdBy,
+		Comment:   req.Comment,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	ctx := c.Request().Context()
+	if err := h.silenceService.Create(ctx, silence); err != nil {
+		h.logger.Error("创建告警静默失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, silence)
+}
+
+// Get 获取告警静默详情
+func (h *AlertSilenceHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	silence, err := h.silenceService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, alertSilenceView{
+		AlertSilence: silence,
+		Status:       service.SilenceStatus(&silence, time.Now().UnixMilli()),
+	})
+}
+
+// Update 更新告警静默
+func (h *AlertSilenceHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+
+	var req AlertSilenceRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	if err := req.check(); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	existing, err := h.silenceService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	existing.AgentID = req.AgentID
+	existing.Tag = req.Tag
+	existing.AlertType = req.AlertType
+	existing.MonitorID = req.MonitorID
+	existing.StartsAt = req.StartsAt
+	existing.EndsAt = req.EndsAt
+	existing.Comment = req.Comment
+	existing.UpdatedAt = time.Now().UnixMilli()
+
+	if err := h.silenceService.Save(ctx, &existing); err != nil {
+		h.logger.Error("更新告警静默失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警静默更新成功",
+	})
+}
+
+// Expire 立即结束告警静默
+func (h *AlertSilenceHandler) Expire(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.silenceService.Expire(ctx, id); err != nil {
+		h.logger.Error("结束告警静默失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警静默已结束",
+	})
+}
+
+// Delete 删除告警静默
+func (h *AlertSilenceHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.silenceService.DeleteById(ctx, id); err != nil {
+		h.logger.Error("删除告警静默失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "告警静默删除成功",
+	})
+}
@@ -21,7 +21,8 @@ func NewApiKeyHandler(logger *zap.Logger, apiKeyService *service.ApiKeyService)
 
 // GenerateApiKeyRequest 生成API密钥请求
 type GenerateApiKeyRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name  string `json:"name" validate:"required"`
+	Scope string `json:"scope" validate:"omitempty,oneof=agent alert"` // 用途，默认 agent
 }
 
 // UpdateApiKeyNameRequest 更新API密钥名称请求
@@ -38,6 +39,9 @@ func (r ApiKeyHandler) Paging(c echo.Context) error {
 	builder := orz.NewPageBuilder(r.apiKeyService.ApiKeyRepo).
 		PageRequest(pr).
 		Contains("name", name)
+	if scope := c.QueryParam("scope"); scope != "" {
+		builder.Equal("scope", scope)
+	}
 
 	ctx := c.Request().Context()
 	page, err := builder.Execute(ctx)
@@ -66,7 +70,7 @@ func (r ApiKeyHandler) Create(c echo.Context) error {
 	userID := c.Get("userID").(string)
 
 	ctx := c.Request().Context()
-	apiKey, err := r.apiKeyService.GenerateApiKey(ctx, req.Name, userID)
+	apiKey, err := r.apiKeyService.GenerateApiKey(ctx, req.Name, req.Scope, userID)
 	if err != nil {
 		r.logger.Error("failed to generate api key", zap.Error(err))
 		return err
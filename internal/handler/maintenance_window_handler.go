@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type MaintenanceWindowHandler struct {
+	logger             *zap.Logger
+	maintenanceService *service.MaintenanceService
+}
+
+func NewMaintenanceWindowHandler(logger *zap.Logger, maintenanceService *service.MaintenanceService) *MaintenanceWindowHandler {
+	return &MaintenanceWindowHandler{
+		logger:             logger,
+		maintenanceService: maintenanceService,
+	}
+}
+
+// MaintenanceWindowRequest 创建/更新维护窗口请求
+type MaintenanceWindowRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	Recurrence  string   `json:"recurrence" validate:"required,oneof=once weekly cron"`
+	StartsAt    int64    `json:"startsAt"`
+	EndsAt      int64    `json:"endsAt"`
+	Weekdays    []int    `json:"weekdays"`
+	StartTime   string   `json:"startTime"`
+	Cron        string   `json:"cron"`
+	Duration    int      `json:"duration"`
+	Timezone    string   `json:"timezone"`
+	AgentIds    []string `json:"agentIds"`
+	Tags        []string `json:"tags"`
+	MonitorIds  []string `json:"monitorIds"`
+}
+
+// maintenanceWindowView 维护窗口（附带当前是否处于维护期）
+type maintenanceWindowView struct {
+	models.MaintenanceWindow
+	Active bool `json:"active"`
+}
+
+func (req *MaintenanceWindowRequest) apply(window *models.MaintenanceWindow) error {
+	window.Name = req.Name
+	window.Description = req.Description
+	window.Enabled = req.Enabled
+	window.Recurrence = req.Recurrence
+	window.StartsAt = req.StartsAt
+	window.EndsAt = req.EndsAt
+	window.Weekdays = req.Weekdays
+	window.StartTime = req.StartTime
+	window.Cron = req.Cron
+	window.Duration = req.Duration
+	window.Timezone = req.Timezone
+	window.AgentIds = req.AgentIds
+	window.Tags = req.Tags
+	window.MonitorIds = req.MonitorIds
+
+	if err := service.ValidateMaintenanceWindow(window); err != nil {
+		return orz.NewError(400, err.Error())
+	}
+	return nil
+}
+
+// Paging 维护窗口分页查询
+func (h *MaintenanceWindowHandler) Paging(c echo.Context) error {
+	name := c.QueryParam("name")
+
+	pr := orz.GetPageRequest(c, "created_at", "name")
+
+	builder := orz.NewPageBuilder(h.maintenanceService.MaintenanceWindowRepo).
+		PageRequest(pr).
+		Contains("name", name)
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	items := make([]maintenanceWindowView, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, maintenanceWindowView{
+			MaintenanceWindow: item,
+			Active:            service.IsMaintenanceWindowActive(&item, now),
+		})
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": items,
+		"total": page.Total,
+	})
+}
+
+// Create 创建维护窗口
+func (h *MaintenanceWindowHandler) Create(c echo.Context) error {
+	var req MaintenanceWindowRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	window := &models.MaintenanceWindow{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := req.apply(window); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.maintenanceService.Create(ctx, window); err != nil {
+		h.logger.Error("创建维护窗口失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, window)
+}
+
+// Get 获取维护窗口详情
+func (h *MaintenanceWindowHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	window, err := h.maintenanceService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, maintenanceWindowView{
+		MaintenanceWindow: window,
+		Active:            service.IsMaintenanceWindowActive(&window, time.Now()),
+	})
+}
+
+// Update 更新维护窗口
+func (h *MaintenanceWindowHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+
+	var req MaintenanceWindowRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	existing, err := h.maintenanceService.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := req.apply(&existing); err != nil {
+		return err
+	}
+	existing.UpdatedAt = time.Now().UnixMilli()
+
+	if err := h.maintenanceService.Update(ctx, &existing); err != nil {
+		h.logger.Error("更新维护窗口失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "维护窗口更新成功",
+	})
+}
+
+// Delete 删除维护窗口
+func (h *MaintenanceWindowHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.maintenanceService.Delete(ctx, id); err != nil {
+		h.logger.Error("删除维护窗口失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "维护窗口删除成功",
+	})
+}
+
+// Enable 启用维护窗口
+func (h *MaintenanceWindowHandler) Enable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.maintenanceService.UpdateEnabled(ctx, id, true); err != nil {
+		h.logger.Error("启用维护窗口失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "维护窗口启用成功",
+	})
+}
+
+// Disable 禁用维护窗口
+func (h *MaintenanceWindowHandler) Disable(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := h.maintenanceService.UpdateEnabled(ctx, id, false); err != nil {
+		h.logger.Error("禁用维护窗口失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "维护窗口禁用成功",
+	})
+}
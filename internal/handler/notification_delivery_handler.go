@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type NotificationDeliveryHandler struct {
+	logger          *zap.Logger
+	deliveryService *service.NotificationDeliveryService
+}
+
+func NewNotificationDeliveryHandler(logger *zap.Logger, deliveryService *service.NotificationDeliveryService) *NotificationDeliveryHandler {
+	return &NotificationDeliveryHandler{
+		logger:          logger,
+		deliveryService: deliveryService,
+	}
+}
+
+// Paging 通知投递记录分页查询
+func (h *NotificationDeliveryHandler) Paging(c echo.Context) error {
+	pr := orz.GetPageRequest(c, "createdAt", "updatedAt", "deliveredAt", "nextRetryAt")
+
+	builder := orz.NewPageBuilder(h.deliveryService.DeliveryRepo.Repository).
+		PageRequest(pr)
+
+	// 按告警记录过滤时包含该记录参与的聚合通知
+	if recordID := c.QueryParam("recordId"); recordID != "" {
+		id, err := strconv.ParseInt(recordID, 10, 64)
+		if err != nil {
+			return orz.NewError(400, "无效的告警记录ID")
+		}
+		builder.Contains("record_ids", service.RecordIDPattern(id))
+	}
+	if status := c.QueryParam("status"); status != "" {
+		builder.Equal("status", status)
+	}
+	if channelType := c.QueryParam("channelType"); channelType != "" {
+		builder.Equal("channel_type", channelType)
+	}
+	if level := c.QueryParam("level"); level != "" {
+		builder.Equal("level", level)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		h.logger.Error("获取通知投递记录失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"items": page.Items,
+		"total": page.Total,
+	})
+}
+
+// Resend 立即重新发送通知
+func (h *NotificationDeliveryHandler) Resend(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "无效的投递记录ID")
+	}
+
+	delivery, err := h.deliveryService.Resend(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, delivery)
+}
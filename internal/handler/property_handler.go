@@ -5,23 +5,27 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
 type PropertyHandler struct {
-	logger   *zap.Logger
-	service  *service.PropertyService
-	notifier *service.Notifier
+	logger       *zap.Logger
+	service      *service.PropertyService
+	agentService *service.AgentService
+	notifier     *service.Notifier
 }
 
-func NewPropertyHandler(logger *zap.Logger, service *service.PropertyService, notifier *service.Notifier) *PropertyHandler {
+func NewPropertyHandler(logger *zap.Logger, service *service.PropertyService, agentService *service.AgentService, notifier *service.Notifier) *PropertyHandler {
 	return &PropertyHandler{
-		logger:   logger,
-		service:  service,
-		notifier: notifier,
+		logger:       logger,
+		service:      service,
+		agentService: agentService,
+		notifier:     notifier,
 	}
 }
 
@@ -33,7 +37,7 @@ func (h *PropertyHandler) GetProperty(c echo.Context) error {
 	if err != nil {
 		h.logger.Error("获取属性失败", zap.String("id", id), zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "获取属性失败",
+			"error": i18n.Error("获取属性失败"),
 		})
 	}
 
@@ -43,7 +47,7 @@ func (h *PropertyHandler) GetProperty(c echo.Context) error {
 		if err := json.Unmarshal([]byte(property.Value), &value); err != nil {
 			h.logger.Error("解析属性值失败", zap.String("id", id), zap.Error(err))
 			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "解析属性值失败",
+				"error": i18n.Error("解析属性值失败"),
 			})
 		}
 	}
@@ -66,14 +70,22 @@ func (h *PropertyHandler) SetProperty(c echo.Context) error {
 
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "无效的请求参数",
+			"error": i18n.Error("无效的请求参数"),
 		})
 	}
 
+	if id == service.PropertyIDNotificationChannels {
+		if err := validateNotificationChannels(req.Value); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": i18n.Error(err.Error()),
+			})
+		}
+	}
+
 	if err := h.service.Set(c.Request().Context(), id, req.Name, req.Value); err != nil {
 		h.logger.Error("设置属性失败", zap.String("id", id), zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "设置属性失败",
+			"error": i18n.Error("设置属性失败"),
 		})
 	}
 
@@ -82,6 +94,19 @@ func (h *PropertyHandler) SetProperty(c echo.Context) error {
 	})
 }
 
+// validateNotificationChannels 校验通知渠道配置，避免保存无法发送的渠道
+func validateNotificationChannels(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var channels []models.NotificationChannelConfig
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return err
+	}
+	return service.ValidateChannelConfigs(channels)
+}
+
 // GetLogo 获取系统 Logo（公开访问，返回图片文件流）
 func (h *PropertyHandler) GetLogo(c echo.Context) error {
 	sysConfig, err := h.service.GetSystemConfig(c.Request().Context())
@@ -155,7 +180,7 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 	channelType := c.Param("type")
 	if channelType == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "缺少渠道类型参数",
+			"error": i18n.Error("缺少渠道类型参数"),
 		})
 	}
 
@@ -165,7 +190,7 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 	if err != nil {
 		h.logger.Error("获取通知渠道配置失败", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "获取通知渠道配置失败",
+			"error": i18n.Error("获取通知渠道配置失败"),
 		})
 	}
 
@@ -180,24 +205,24 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 
 	if targetChannel == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "通知渠道不存在，请先配置",
+			"error": i18n.Error("通知渠道不存在，请先配置"),
 		})
 	}
 
 	if !targetChannel.Enabled {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "通知渠道未启用",
+			"error": i18n.Error("通知渠道未启用"),
 		})
 	}
 
 	// 发送测试消息（动态匹配通知渠道类型）
-	message := "这是一条测试通知消息"
+	message := i18n.T("notify.test_message")
 	sendErr := h.notifier.SendTestNotification(ctx, targetChannel.Type, targetChannel.Config, message)
 
 	if sendErr != nil {
 		h.logger.Error("发送测试通知失败", zap.String("type", channelType), zap.Error(sendErr))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "发送测试通知失败: " + sendErr.Error(),
+			"error": i18n.Error("发送测试通知失败") + ": " + sendErr.Error(),
 		})
 	}
 
@@ -205,3 +230,116 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 		"message": "测试通知已发送",
 	})
 }
+
+// NotificationRouteDryRunRequest 通知路由试运行请求
+type NotificationRouteDryRunRequest struct {
+	AgentID   string                     `json:"agentId"`                       // 探针ID，指定后使用探针的标签
+	Tags      []string                   `json:"tags"`                          // 未指定探针时使用的标签
+	AlertType string                     `json:"alertType" validate:"required"` // 告警类型
+	Level     string                     `json:"level" validate:"required"`     // 告警级别
+	Routes    []models.NotificationRoute `json:"routes"`                        // 待测试的路由规则，为空时使用已保存的规则
+}
+
+// NotificationRouteChannel 试运行命中的通知渠道
+type NotificationRouteChannel struct {
+	Type       string `json:"type"`
+	Configured bool   `json:"configured"` // 是否已配置
+	Enabled    bool   `json:"enabled"`    // 是否已启用
+}
+
+// DryRunNotificationRoutes 试运行通知路由，返回样例告警会发送到的渠道
+func (h *PropertyHandler) DryRunNotificationRoutes(c echo.Context) error {
+	var req NotificationRouteDryRunRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	agent := &models.Agent{ID: req.AgentID, Tags: req.Tags}
+	if req.AgentID != "" {
+		found, err := h.agentService.AgentRepo.FindById(ctx, req.AgentID)
+		if err != nil {
+			return orz.NewError(400, "探针不存在")
+		}
+		agent = &found
+	}
+
+	routes := req.Routes
+	if routes == nil {
+		var err error
+		routes, err = h.service.GetNotificationRoutes(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	channels, err := h.service.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		AlertType: req.AlertType,
+		Level:     req.Level,
+	}
+	result := service.RouteNotification(routes, record, agent)
+
+	// 未命中任何规则时发送到所有已启用渠道
+	targets := result.Channels
+	if result.Fallback {
+		for _, channel := range channels {
+			if channel.Enabled {
+				targets = append(targets, channel.Type)
+			}
+		}
+	}
+
+	items := make([]NotificationRouteChannel, 0, len(targets))
+	for _, channelType := range targets {
+		item := NotificationRouteChannel{Type: channelType}
+		for _, channel := range channels {
+			if channel.Type == channelType {
+				item.Configured = true
+				item.Enabled = channel.Enabled
+				break
+			}
+		}
+		items = append(items, item)
+	}
+
+	return orz.Ok(c, orz.Map{
+		"matchedRoutes": result.MatchedRoutes,
+		"fallback":      result.Fallback,
+		"channels":      items,
+	})
+}
+
+// NotificationTemplatePreviewRequest 通知模板预览请求
+type NotificationTemplatePreviewRequest struct {
+	Template  string `json:"template"`  // 模板内容，为空时预览内置消息
+	AlertType string `json:"alertType"` // 样例告警类型，默认 cpu
+	Status    string `json:"status"`    // 样例告警状态: firing, acknowledged, resolved，默认 firing
+}
+
+// PreviewNotificationTemplate 使用样例告警渲染通知模板
+func (h *PropertyHandler) PreviewNotificationTemplate(c echo.Context) error {
+	var req NotificationTemplatePreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	switch req.Status {
+	case "", "firing", "acknowledged", "resolved":
+	default:
+		return orz.NewError(400, "status 只支持 firing、acknowledged 或 resolved")
+	}
+
+	return orz.Ok(c, orz.Map{
+		"content": h.notifier.PreviewTemplate(req.Template, req.AlertType, req.Status),
+	})
+}
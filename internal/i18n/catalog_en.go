@@ -0,0 +1,168 @@
+package i18n
+
+// catalogEn 英文消息目录，key 与 catalogZh 一致
+var catalogEn = map[string]string{
+	// 持续时间
+	"duration.seconds": "{0}s",
+	"duration.minutes": "{0}m{1}s",
+	"duration.hours":   "{0}h{1}m{2}s",
+
+	// 告警类型
+	"alert_type.unknown":        "Unknown alert",
+	"alert_type.cpu":            "CPU alert",
+	"alert_type.memory":         "Memory alert",
+	"alert_type.disk":           "Disk alert",
+	"alert_type.load":           "Load alert",
+	"alert_type.swap":           "Swap alert",
+	"alert_type.steal":          "CPU steal alert",
+	"alert_type.inode":          "Inode alert",
+	"alert_type.network":        "Network alert",
+	"alert_type.cert":           "Certificate alert",
+	"alert_type.service":        "Service alert",
+	"alert_type.agent_offline":  "Agent offline alert",
+	"alert_type.tamper_event":   "Tamper event alert",
+	"alert_type.tamper_attr":    "Tamper attribute alert",
+	"alert_type.ddns_failure":   "DDNS update failure alert",
+	"alert_type.ddns_ip_change": "Public IP change",
+	"alert_type.anomaly":        "Anomaly alert",
+	"alert_type.disk_forecast":  "Disk full forecast alert",
+	"alert_type.expression":     "Expression alert",
+	"alert_type.traffic":        "Traffic alert",
+	"alert_type.external":       "External alert",
+
+	// 单位
+	"unit.天":  " days",
+	"unit.秒":  "s",
+	"unit.小时": "h",
+	"unit.次":  " times",
+
+	// 告警消息
+	"alert.metric.cpu":          "CPU usage",
+	"alert.metric.memory":       "Memory usage",
+	"alert.metric.disk":         "Disk usage",
+	"alert.metric.inode":        "Inode usage",
+	"alert.metric.swap":         "Swap usage",
+	"alert.metric.steal":        "CPU steal",
+	"alert.msg.metric":          "{0} stayed above threshold for {1}s: threshold {2}%, current {3}%",
+	"alert.msg.mount":           "Mount point {0} {1}",
+	"alert.msg.load":            "Load per core stayed above threshold for {0}s: threshold {1}, current {2}",
+	"alert.msg.network":         "Network speed stayed above threshold for {0}s: threshold {1}MB/s, current {2}MB/s",
+	"alert.msg.cert":            "HTTPS certificate expires in {0} days, below threshold of {1} days",
+	"alert.msg.cert_monitor":    "HTTPS certificate of monitor {0} expires in {1} days, below threshold of {2} days",
+	"alert.msg.service":         "Service down for {0}s",
+	"alert.msg.service_monitor": "Monitor {0} down for {1}s",
+	"alert.msg.agent_offline":   "Agent {0} offline for {1}s, above threshold of {2}s",
+	"alert.msg.disk_forecast":   "Mount point {0} is expected to fill up in {1} (at {2}), within the {3}h forecast horizon",
+	"alert.msg.anomaly":         "{0} current value {1} deviates from {2} by {3} standard deviations, expected range {4} ~ {5}",
+	"alert.anomaly.rolling":     "the recent baseline",
+	"alert.anomaly.same_hour":   "the same-hour historical baseline",
+	"alert.msg.expression":      "Rule {0} matched for {1}s: {2}, current {3}",
+	"alert.msg.tamper_attr":     "Immutable attribute removed from protected path {0}, {1}",
+	"alert.msg.tamper_event":    "File change in protected directory {0}: {1} {2}",
+	"alert.tamper.restored":     "restored automatically",
+	"alert.tamper.not_restored": "automatic restore failed",
+	"alert.msg.details":         " ({0})",
+	"alert.msg.count":           ", {0} times in total",
+	"alert.msg.ddns_failure":    "DDNS config {0} ({1}) failed to update {2} times in a row: {3}",
+	"alert.msg.ip_change":       "Public IP changed: {0}",
+	"alert.msg.ip_change_item":  "{0} record IP changed from {1} to {2}",
+	"alert.msg.traffic":         "Traffic usage reached {0}%, currently {1}% ({2}/{3})",
+	"alert.msg.separator":       "; ",
+	"alert.msg.comma":           ", ",
+
+	// 通知消息
+	"notify.firing": "{0} {1}\n\n" +
+		"Agent: {2} ({3})\n" +
+		"Host: {4}\n" +
+		"IP: {5}\n" +
+		"Alert type: {6}\n" +
+		"Message: {7}\n" +
+		"Threshold: {8}\n" +
+		"Current value: {9}\n" +
+		"Fired at: {10}",
+	"notify.notify_count": "\nReminder: #{0}",
+	"notify.acknowledged": "👀 {0} acknowledged\n\n" +
+		"Agent: {1} ({2})\n" +
+		"Host: {3}\n" +
+		"IP: {4}\n" +
+		"Alert type: {5}\n" +
+		"Message: {6}\n" +
+		"Acknowledged by: {7}\n" +
+		"Assignee: {8}\n" +
+		"Fired at: {9}\n" +
+		"Acknowledged at: {10}",
+	"notify.flapping": "🔁 {0} is flapping\n\n" +
+		"Agent: {1} ({2})\n" +
+		"Host: {3}\n" +
+		"IP: {4}\n" +
+		"Alert type: {5}\n" +
+		"Message: {6}\n" +
+		"Current value: {7}\n" +
+		"Detected at: {8}\n\n" +
+		"The alert keeps firing and resolving; firing and resolved notifications are suppressed while it is flapping",
+	"notify.info": "ℹ️ {0}\n\n" +
+		"Agent: {1} ({2})\n" +
+		"Host: {3}\n" +
+		"IP: {4}\n" +
+		"Message: {5}\n" +
+		"Time: {6}",
+	"notify.resolved": "✅ {0} resolved\n\n" +
+		"Agent: {1} ({2})\n" +
+		"Host: {3}\n" +
+		"IP: {4}\n" +
+		"Alert type: {5}\n" +
+		"Current value: {6}\n" +
+		"Duration: {7}\n" +
+		"Resolved at: {8}",
+	"notify.acknowledged_by":      "\nAcknowledged by: {0}",
+	"notify.unknown_status":       "⚠️ Unknown alert status: {0}\nAgent: {1} ({2})",
+	"notify.group.default_title":  "Alerts",
+	"notify.group.firing_title":   "{0} {1} summary",
+	"notify.group.resolved_title": "✅ {0} resolved summary",
+	"notify.group.count":          "Alerts: {0}",
+	"notify.group.agents":         "Affected agents: {0}",
+	"notify.group.tags":           "Tags: {0}",
+	"notify.group.more":           "... {0} alerts in total",
+	"notify.group.fired_at":       "Fired at: {0}",
+	"notify.group.resolved_at":    "Resolved at: {0}",
+	"notify.email_subject":        "Pika alert notification",
+	"notify.email.subject":        "[{0}] {1}: {2}",
+	"notify.email.digest_subject": "{0} ({1} alerts)",
+	"notify.email.footer":         "This email was sent automatically by Pika, please do not reply",
+	"notify.test_message":         "This is a test notification",
+	"notify.test_agent":           "Test agent",
+	"notify.sample_agent":         "Sample agent",
+
+	// 聊天类渠道消息卡片
+	"notify.chat.resolved":         "✅ {0} resolved",
+	"notify.chat.acknowledged":     "👀 {0} acknowledged",
+	"notify.chat.flapping":         "🔁 {0} is flapping",
+	"notify.field.agent":           "Agent",
+	"notify.field.host":            "Host",
+	"notify.field.level":           "Level",
+	"notify.field.value":           "Current value",
+	"notify.field.threshold":       "Threshold",
+	"notify.field.fired_at":        "Fired at",
+	"notify.field.resolved_at":     "Resolved at",
+	"notify.field.duration":        "Duration",
+	"notify.field.acknowledged_by": "Acknowledged by",
+	"notify.field.assignee":        "Assignee",
+
+	// 安装脚本
+	"install.unsupported_arch": "Unsupported architecture",
+	"install.unsupported_os":   "Unsupported operating system",
+	"install.platform":         "Detected platform",
+	"install.downloading":      "Downloading agent...",
+	"install.no_downloader":    "Neither wget nor curl was found, please install one of them first",
+	"install.download_failed":  "Download failed",
+	"install.downloaded":       "Agent downloaded",
+	"install.registering":      "Registering agent...",
+	"install.start":            "Installing Pika Agent...",
+	"install.done":             "Installation complete!",
+	"install.commands":         "Common commands:",
+	"install.cmd_status":       "Show status",
+	"install.cmd_start":        "Start service",
+	"install.cmd_stop":         "Stop service",
+	"install.cmd_restart":      "Restart service",
+	"install.cmd_uninstall":    "Uninstall service",
+}
@@ -0,0 +1,142 @@
+package i18n
+
+// catalogZh 中文消息目录，参数使用 {0}、{1}… 占位
+var catalogZh = map[string]string{
+	// 持续时间
+	"duration.seconds": "{0}秒",
+	"duration.minutes": "{0}分{1}秒",
+	"duration.hours":   "{0}时{1}分{2}秒",
+
+	// 告警类型
+	"alert_type.unknown": "未知告警",
+
+	// 告警消息
+	"alert.metric.cpu":          "CPU使用率",
+	"alert.metric.memory":       "内存使用率",
+	"alert.metric.disk":         "磁盘使用率",
+	"alert.metric.inode":        "inode使用率",
+	"alert.metric.swap":         "Swap使用率",
+	"alert.metric.steal":        "CPU Steal占比",
+	"alert.msg.metric":          "{0}持续{1}秒超过{2}%，当前值{3}%",
+	"alert.msg.mount":           "挂载点 {0} {1}",
+	"alert.msg.load":            "每核负载持续{0}秒超过{1}，当前值{2}",
+	"alert.msg.network":         "网速持续{0}秒超过{1}MB/s，当前值{2}MB/s",
+	"alert.msg.cert":            "HTTPS证书剩余天数{0}天，低于阈值{1}天",
+	"alert.msg.cert_monitor":    "监控项 {0} 的HTTPS证书剩余天数{1}天，低于阈值{2}天",
+	"alert.msg.service":         "服务持续离线{0}秒",
+	"alert.msg.service_monitor": "监控项 {0} 持续离线{1}秒",
+	"alert.msg.agent_offline":   "探针 {0} 已离线{1}秒，超过阈值{2}秒",
+	"alert.msg.disk_forecast":   "挂载点 {0} 预计 {1} 后写满（预计写满时间 {2}），低于预测范围{3}小时",
+	"alert.msg.anomaly":         "{0} 当前值{1}偏离{2} {3} 倍标准差，期望范围 {4} ~ {5}",
+	"alert.anomaly.rolling":     "最近基线",
+	"alert.anomaly.same_hour":   "历史同时段基线",
+	"alert.msg.expression":      "规则 {0} 持续{1}秒满足表达式 {2}，当前值{3}",
+	"alert.msg.tamper_attr":     "受保护路径 {0} 的不可变属性被移除，{1}",
+	"alert.msg.tamper_event":    "受保护目录 {0} 发生文件变更: {1} {2}",
+	"alert.tamper.restored":     "已自动恢复",
+	"alert.tamper.not_restored": "未能自动恢复",
+	"alert.msg.details":         "（{0}）",
+	"alert.msg.count":           "，共 {0} 次",
+	"alert.msg.ddns_failure":    "DDNS 配置 {0}（{1}）连续 {2} 次更新失败: {3}",
+	"alert.msg.ip_change":       "公网 IP 变化: {0}",
+	"alert.msg.ip_change_item":  "{0} 记录 IP 由 {1} 变为 {2}",
+	"alert.msg.traffic":         "流量使用已达到{0}%，当前使用{1}%（{2}/{3}）",
+	"alert.msg.separator":       "；",
+	"alert.msg.comma":           "，",
+
+	// 通知消息
+	"notify.firing": "{0} {1}\n\n" +
+		"探针: {2} ({3})\n" +
+		"主机: {4}\n" +
+		"IP: {5}\n" +
+		"告警类型: {6}\n" +
+		"告警消息: {7}\n" +
+		"阈值: {8}\n" +
+		"当前值: {9}\n" +
+		"触发时间: {10}",
+	"notify.notify_count": "\n提醒次数: 第{0}次",
+	"notify.acknowledged": "👀 {0}已确认\n\n" +
+		"探针: {1} ({2})\n" +
+		"主机: {3}\n" +
+		"IP: {4}\n" +
+		"告警类型: {5}\n" +
+		"告警消息: {6}\n" +
+		"确认人: {7}\n" +
+		"处理人: {8}\n" +
+		"触发时间: {9}\n" +
+		"确认时间: {10}",
+	"notify.flapping": "🔁 {0}频繁抖动\n\n" +
+		"探针: {1} ({2})\n" +
+		"主机: {3}\n" +
+		"IP: {4}\n" +
+		"告警类型: {5}\n" +
+		"告警消息: {6}\n" +
+		"当前值: {7}\n" +
+		"检测时间: {8}\n\n" +
+		"告警在短时间内反复触发和恢复，抖动期间将不再发送该告警的触发和恢复通知",
+	"notify.info": "ℹ️ {0}\n\n" +
+		"探针: {1} ({2})\n" +
+		"主机: {3}\n" +
+		"IP: {4}\n" +
+		"消息: {5}\n" +
+		"时间: {6}",
+	"notify.resolved": "✅ {0}已恢复\n\n" +
+		"探针: {1} ({2})\n" +
+		"主机: {3}\n" +
+		"IP: {4}\n" +
+		"告警类型: {5}\n" +
+		"当前值: {6}\n" +
+		"持续时间: {7}\n" +
+		"恢复时间: {8}",
+	"notify.acknowledged_by":      "\n确认人: {0}",
+	"notify.unknown_status":       "⚠️ 未知告警状态: {0}\n探针: {1} ({2})",
+	"notify.group.default_title":  "告警",
+	"notify.group.firing_title":   "{0} {1}聚合通知",
+	"notify.group.resolved_title": "✅ {0}恢复汇总",
+	"notify.group.count":          "告警数量: {0}",
+	"notify.group.agents":         "受影响探针: {0} 台",
+	"notify.group.tags":           "标签: {0}",
+	"notify.group.more":           "... 等共 {0} 条告警",
+	"notify.group.fired_at":       "触发时间: {0}",
+	"notify.group.resolved_at":    "恢复时间: {0}",
+	"notify.email_subject":        "Pika 告警通知",
+	"notify.email.subject":        "[{0}] {1}: {2}",
+	"notify.email.digest_subject": "{0}（{1} 条告警）",
+	"notify.email.footer":         "此邮件由 Pika 自动发送，请勿直接回复",
+	"notify.test_message":         "这是一条测试通知消息",
+	"notify.test_agent":           "测试探针",
+	"notify.sample_agent":         "示例探针",
+
+	// 聊天类渠道消息卡片
+	"notify.chat.resolved":         "✅ {0}已恢复",
+	"notify.chat.acknowledged":     "👀 {0}已确认",
+	"notify.chat.flapping":         "🔁 {0}频繁抖动",
+	"notify.field.agent":           "探针",
+	"notify.field.host":            "主机",
+	"notify.field.level":           "级别",
+	"notify.field.value":           "当前值",
+	"notify.field.threshold":       "阈值",
+	"notify.field.fired_at":        "触发时间",
+	"notify.field.resolved_at":     "恢复时间",
+	"notify.field.duration":        "持续时间",
+	"notify.field.acknowledged_by": "确认人",
+	"notify.field.assignee":        "处理人",
+
+	// 安装脚本
+	"install.unsupported_arch": "不支持的架构",
+	"install.unsupported_os":   "不支持的操作系统",
+	"install.platform":         "检测到平台",
+	"install.downloading":      "正在下载探针...",
+	"install.no_downloader":    "未找到 wget 或 curl 命令，请先安装其中之一",
+	"install.download_failed":  "下载失败",
+	"install.downloaded":       "探针下载完成",
+	"install.registering":      "正在注册探针...",
+	"install.start":            "开始安装 Pika Agent...",
+	"install.done":             "安装完成！",
+	"install.commands":         "常用命令：",
+	"install.cmd_status":       "查看状态",
+	"install.cmd_start":        "启动服务",
+	"install.cmd_stop":         "停止服务",
+	"install.cmd_restart":      "重启服务",
+	"install.cmd_uninstall":    "卸载服务",
+}
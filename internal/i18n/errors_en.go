@@ -0,0 +1,61 @@
+package i18n
+
+// errorsEn 接口错误信息的英文翻译，key 为中文原文
+var errorsEn = map[string]string{
+	"请求参数错误":                                    "Invalid request parameters",
+	"无效的请求参数":                                   "Invalid request parameters",
+	"未登录":                                       "Not logged in",
+	"未提供认证令牌":                                   "Authentication token not provided",
+	"认证令牌格式错误":                                  "Malformed authentication token",
+	"未提供 API 密钥":                                "API key not provided",
+	"API 密钥无效":                                  "Invalid API key",
+	"认证令牌无效":                                    "Invalid authentication token",
+	"用户名或密码错误":                                  "Incorrect username or password",
+	"OIDC 认证失败":                                 "OIDC authentication failed",
+	"GitHub 认证失败":                               "GitHub authentication failed",
+	"首条消息必须是注册消息":                               "The first message must be a registration message",
+	"token不能为空":                                 "Token is required",
+	"探针不存在":                                     "Agent not found",
+	"探针未连接":                                     "Agent is not connected",
+	"探针ID列表不能为空":                                "Agent ID list is required",
+	"探针最新指标不存在":                                 "No recent metrics for this agent",
+	"指标类型不能为空":                                  "Metric type is required",
+	"无效的指标类型":                                   "Invalid metric type",
+	"指令类型不能为空":                                  "Command type is required",
+	"发送指令失败":                                    "Failed to send command",
+	"不支持的操作类型":                                  "Unsupported operation",
+	"未找到对应平台的 Agent 二进制文件":                      "No agent binary found for this platform",
+	"无效的告警记录ID":                                 "Invalid alert record ID",
+	"只能确认告警中的记录":                                "Only firing alerts can be acknowledged",
+	"清空告警记录失败":                                  "Failed to clear alert records",
+	"无效的投递记录ID":                                 "Invalid delivery ID",
+	"通知正在投递中，请稍后再试":                             "Notification is being delivered, please try again later",
+	"结束时间必须晚于开始时间":                              "End time must be after start time",
+	"至少需要设置一个匹配条件":                              "At least one matcher is required",
+	"status 只支持 firing、acknowledged 或 resolved": "status must be firing, acknowledged or resolved",
+	"不支持的 DNS 服务商":                              "Unsupported DNS provider",
+	"不支持的 DNS 服务商类型":                            "Unsupported DNS provider type",
+	"IPv4 获取方式不能为空":                             "IPv4 detection method is required",
+	"IPv6 获取方式不能为空":                             "IPv6 detection method is required",
+	"IPv4 获取方式只能是 api 或 interface":              "IPv4 detection method must be api or interface",
+	"IPv6 获取方式只能是 api 或 interface":              "IPv6 detection method must be api or interface",
+	"provider 参数不能为空":                           "provider is required",
+	"获取配置失败":                                    "Failed to load configuration",
+	"保存配置失败":                                    "Failed to save configuration",
+	"删除配置失败":                                    "Failed to delete configuration",
+	"获取属性失败":                                    "Failed to load property",
+	"解析属性值失败":                                   "Failed to parse property value",
+	"设置属性失败":                                    "Failed to save property",
+	"Logo 不存在":                                  "Logo not found",
+	"无效的图片数据格式":                                 "Invalid image data format",
+	"解码图片数据失败":                                  "Failed to decode image data",
+	"缺少渠道类型参数":                                  "Channel type is required",
+	"获取通知渠道配置失败":                                "Failed to load notification channels",
+	"通知渠道不存在，请先配置":                              "Notification channel not found, please configure it first",
+	"通知渠道未启用":                                   "Notification channel is disabled",
+	"发送测试通知失败":                                  "Failed to send test notification",
+	"不支持的通知渠道类型":                                "Unsupported notification channel type",
+	"通知渠道配置格式错误":                                "Invalid notification channel configuration",
+	"未配置脚本目录，exec 渠道已禁用":                        "Script directory is not configured, the exec channel is disabled",
+	"脚本必须位于脚本目录内":                               "Script must be located in the script directory",
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+)
+
+const (
+	// LocaleZh 简体中文（默认）
+	LocaleZh = "zh"
+	// LocaleEn 英文
+	LocaleEn = "en"
+)
+
+var (
+	uni     = ut.New(zh.New(), en.New())
+	current atomic.Value // 当前语言
+)
+
+func init() {
+	current.Store(LocaleZh)
+
+	catalogs := map[string]map[string]string{
+		LocaleZh: catalogZh,
+		LocaleEn: catalogEn,
+	}
+	for locale, catalog := range catalogs {
+		trans, _ := uni.GetTranslator(locale)
+		for key, text := range catalog {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// Normalize 规范化语言标识，如 en-US -> en，不支持的语言返回默认语言
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	switch {
+	case strings.HasPrefix(locale, LocaleEn):
+		return LocaleEn
+	default:
+		return LocaleZh
+	}
+}
+
+// SetLocale 设置服务端消息使用的语言
+func SetLocale(locale string) {
+	current.Store(Normalize(locale))
+}
+
+// Locale 当前语言
+func Locale() string {
+	return current.Load().(string)
+}
+
+// Translator 当前语言的翻译器
+func Translator() ut.Translator {
+	trans, _ := uni.GetTranslator(Locale())
+	return trans
+}
+
+// UniversalTranslator 返回全局翻译器，用于注册校验器等第三方翻译
+func UniversalTranslator() *ut.UniversalTranslator {
+	return uni
+}
+
+// T 按当前语言翻译消息，参数替换模板中的 {0}、{1}…，当前语言缺失时回退到中文，仍缺失时返回 key
+func T(key string, params ...string) string {
+	if text, err := Translator().T(key, params...); err == nil {
+		return text
+	}
+	trans, _ := uni.GetTranslator(LocaleZh)
+	if text, err := trans.T(key, params...); err == nil {
+		return text
+	}
+	return key
+}
+
+// Lookup 查找当前语言中的翻译，不存在时返回 false
+func Lookup(key string) (string, bool) {
+	text, err := Translator().T(key)
+	if err != nil {
+		return "", false
+	}
+	return text, true
+}
+
+// Error 翻译接口错误信息，错误信息以中文原文作为 key，没有对应翻译时原样返回
+func Error(message string) string {
+	if Locale() == LocaleZh {
+		return message
+	}
+	if text, ok := errorsEn[message]; ok {
+		return text
+	}
+	// 带有错误详情的信息，如 "认证令牌无效: xxx"，只翻译前缀
+	if prefix, detail, found := strings.Cut(message, ": "); found {
+		if text, ok := errorsEn[prefix]; ok {
+			return text + ": " + detail
+		}
+	}
+	return message
+}
@@ -12,6 +12,8 @@ type DiskSummary struct {
 	Total        uint64  `json:"total"`        // 总容量(字节)
 	Used         uint64  `json:"used"`         // 已使用(字节)
 	Free         uint64  `json:"free"`         // 空闲(字节)
+
+	Mounts []protocol.DiskData `json:"mounts,omitempty"` // 各挂载点数据
 }
 
 // NetworkSummary 网络汇总数据
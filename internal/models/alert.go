@@ -1,42 +1,147 @@
 package models
 
+import "gorm.io/datatypes"
+
+// AlertConfigIDGlobal 使用全局告警规则时的规则来源ID
+const AlertConfigIDGlobal = "global"
+
+// AlertTypeExpression PromQL 表达式告警类型
+const AlertTypeExpression = "expression"
+
 // AlertRecord 告警记录
 type AlertRecord struct {
-	ID          int64   `gorm:"primaryKey;autoIncrement" json:"id"`    // 记录ID
-	AgentID     string  `gorm:"index" json:"agentId"`                  // 探针ID
-	AgentName   string  `json:"agentName"`                             // 探针名称
-	AlertType   string  `json:"alertType"`                             // 告警类型: cpu, memory, disk, network
-	Message     string  `json:"message"`                               // 告警消息
-	Threshold   float64 `json:"threshold"`                             // 告警阈值
-	ActualValue float64 `json:"actualValue"`                           // 实际值
-	Level       string  `json:"level"`                                 // 告警级别: info, warning, critical
-	Status      string  `json:"status"`                                // 状态: firing（告警中）, resolved（已恢复）
-	FiredAt     int64   `gorm:"index" json:"firedAt"`                  // 触发时间（时间戳毫秒）
-	ResolvedAt  int64   `json:"resolvedAt,omitempty"`                  // 恢复时间（时间戳毫秒）
-	CreatedAt   int64   `json:"createdAt"`                             // 创建时间（时间戳毫秒）
-	UpdatedAt   int64   `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+	ID              int64                                 `gorm:"primaryKey;autoIncrement" json:"id"`    // 记录ID
+	AgentID         string                                `gorm:"index" json:"agentId"`                  // 探针ID
+	AgentName       string                                `json:"agentName"`                             // 探针名称
+	ConfigID        string                                `gorm:"index" json:"configId"`                 // 规则来源: global 或告警规则集ID
+	StateID         string                                `gorm:"index" json:"stateId,omitempty"`        // 产生该记录的告警状态ID，同一告警状态的多次触发共用
+	AlertType       string                                `json:"alertType"`                             // 告警类型: cpu, memory, disk, network
+	Message         string                                `json:"message"`                               // 告警消息
+	Threshold       float64                               `json:"threshold"`                             // 告警阈值
+	ActualValue     float64                               `json:"actualValue"`                           // 实际值
+	Labels          datatypes.JSONType[map[string]string] `json:"labels"`                                // 告警实例标签: 来自告警状态（表达式告警的时间序列标签、磁盘类告警的 mount_point 等），防篡改告警为 path 和 root，外部告警为原始标签
+	MonitorID       string                                `gorm:"index" json:"monitorId,omitempty"`      // 监控项ID（证书、服务告警）
+	SilenceID       string                                `json:"silenceId,omitempty"`                   // 静默该告警通知的静默规则ID
+	InhibitedBy     int64                                 `gorm:"index" json:"inhibitedBy,omitempty"`    // 抑制该告警通知的源告警记录ID
+	Flapping        bool                                  `json:"flapping,omitempty"`                    // 触发时是否处于抖动状态
+	Level           string                                `json:"level"`                                 // 告警级别: info, warning, critical
+	Status          string                                `json:"status"`                                // 状态: firing（告警中）, acknowledged（已确认）, resolved（已恢复）
+	AcknowledgedBy  string                                `json:"acknowledgedBy,omitempty"`              // 确认人
+	AcknowledgedAt  int64                                 `json:"acknowledgedAt,omitempty"`              // 确认时间（时间戳毫秒）
+	Assignee        string                                `json:"assignee,omitempty"`                    // 处理人
+	NotifyCount     int                                   `json:"notifyCount"`                           // 已发送的告警通知次数
+	LastNotifiedAt  int64                                 `json:"lastNotifiedAt,omitempty"`              // 最后一次发送告警通知的时间（时间戳毫秒）
+	EscalationLevel int                                   `json:"escalationLevel"`                       // 已升级到的步骤数
+	FiredAt         int64                                 `gorm:"index" json:"firedAt"`                  // 触发时间（时间戳毫秒）
+	ResolvedAt      int64                                 `json:"resolvedAt,omitempty"`                  // 恢复时间（时间戳毫秒）
+	CreatedAt       int64                                 `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt       int64                                 `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
 }
 
 func (AlertRecord) TableName() string {
 	return "alert_records"
 }
 
+// IsActive 告警是否仍未恢复（告警中或已确认）
+func (r *AlertRecord) IsActive() bool {
+	return r.Status == "firing" || r.Status == "acknowledged"
+}
+
+// AlertNote 告警备注
+type AlertNote struct {
+	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 备注ID
+	RecordID  int64  `gorm:"index" json:"recordId"`              // 告警记录ID
+	Author    string `json:"author"`                             // 作者
+	Content   string `json:"content"`                            // 内容
+	CreatedAt int64  `json:"createdAt"`                          // 创建时间（时间戳毫秒）
+}
+
+func (AlertNote) TableName() string {
+	return "alert_notes"
+}
+
 // AlertState 告警状态（持久化到数据库，用于判断是否持续超过阈值）
 type AlertState struct {
-	ID            string  `gorm:"primaryKey" json:"id"`                  // 状态ID（格式：agentId:configId:alertType）
-	AgentID       string  `gorm:"index" json:"agentId"`                  // 探针ID
-	AlertType     string  `gorm:"index" json:"alertType"`                // 告警类型
-	Value         float64 `json:"value"`                                 // 当前值
-	Threshold     float64 `json:"threshold"`                             // 阈值
-	StartTime     int64   `json:"startTime"`                             // 开始超过阈值的时间
-	Duration      int     `json:"duration"`                              // 需要持续的时间（秒）
-	LastCheckTime int64   `json:"lastCheckTime"`                         // 上次检查时间
-	IsFiring      bool    `json:"isFiring"`                              // 是否正在告警
-	LastRecordID  int64   `json:"lastRecordId"`                          // 最后一条告警记录ID
-	CreatedAt     int64   `json:"createdAt"`                             // 创建时间（时间戳毫秒）
-	UpdatedAt     int64   `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+	ID               string                                `gorm:"primaryKey" json:"id"`                  // 状态ID（格式：agentId:configId:alertType，按挂载点等区分实例时追加实例标识）
+	AgentID          string                                `gorm:"index" json:"agentId"`                  // 探针ID
+	ConfigID         string                                `gorm:"index" json:"configId"`                 // 规则来源: global 或告警规则集ID
+	AlertType        string                                `gorm:"index" json:"alertType"`                // 告警类型
+	Value            float64                               `json:"value"`                                 // 当前值
+	Threshold        float64                               `json:"threshold"`                             // 阈值
+	StartTime        int64                                 `json:"startTime"`                             // 开始超过阈值的时间
+	Duration         int                                   `json:"duration"`                              // 需要持续的时间（秒）
+	LastCheckTime    int64                                 `json:"lastCheckTime"`                         // 上次检查时间
+	IsFiring         bool                                  `json:"isFiring"`                              // 是否正在告警
+	ResolveStartTime int64                                 `json:"resolveStartTime"`                      // 开始满足恢复条件的时间
+	Transitions      datatypes.JSONSlice[int64]            `json:"transitions"`                           // 统计窗口内的状态变化时间
+	IsFlapping       bool                                  `json:"isFlapping"`                            // 是否处于抖动状态
+	FlapNotified     bool                                  `json:"flapNotified"`                          // 本次抖动是否已发送抖动通知
+	LastRecordID     int64                                 `json:"lastRecordId"`                          // 最后一条告警记录ID
+	Labels           datatypes.JSONType[map[string]string] `json:"labels"`                                // 告警实例标签: 表达式告警的时间序列标签，磁盘、inode 和磁盘写满预测告警的挂载点 mount_point
+	CreatedAt        int64                                 `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt        int64                                 `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
 }
 
 func (AlertState) TableName() string {
 	return "alert_states"
 }
+
+// AlertRuleSet 告警规则集（按探针ID或标签覆盖全局告警规则）
+type AlertRuleSet struct {
+	ID          string                         `gorm:"primaryKey" json:"id"`                  // 规则集ID (UUID)
+	Name        string                         `gorm:"uniqueIndex" json:"name"`               // 规则集名称
+	Description string                         `json:"description"`                           // 描述
+	Enabled     bool                           `json:"enabled"`                               // 是否启用
+	Priority    int                            `json:"priority"`                              // 优先级，数值越大越优先
+	AgentIds    datatypes.JSONSlice[string]    `json:"agentIds"`                              // 作用的探针ID列表
+	Tags        datatypes.JSONSlice[string]    `json:"tags"`                                  // 作用的探针标签列表，拥有任一标签的探针都会匹配
+	Rules       datatypes.JSONType[AlertRules] `json:"rules"`                                 // 告警规则，命中后整体覆盖全局规则
+	CreatedAt   int64                          `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt   int64                          `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (AlertRuleSet) TableName() string {
+	return "alert_rule_sets"
+}
+
+// AlertExpressionRule PromQL 表达式告警规则
+//
+// 表达式查询结果中的每个时间序列都视为一个处于异常中的告警实例，
+// 持续 For 秒后触发告警，序列从结果中消失后恢复。
+type AlertExpressionRule struct {
+	ID          string                                `gorm:"primaryKey" json:"id"`                  // 规则ID (UUID)
+	Name        string                                `gorm:"uniqueIndex" json:"name"`               // 规则名称
+	Enabled     bool                                  `json:"enabled"`                               // 是否启用
+	Expr        string                                `json:"expr"`                                  // PromQL 表达式，如 pika_network_conn_time_wait > 5000
+	For         int                                   `json:"for"`                                   // 持续时间（秒）
+	Level       string                                `json:"level"`                                 // 告警级别: info, warning, critical
+	Labels      datatypes.JSONType[map[string]string] `json:"labels"`                                // 附加标签，会合并到时间序列标签中
+	Annotations datatypes.JSONType[map[string]string] `json:"annotations"`                           // 注释，summary/description 支持 {{value}} 和 {{labels.xxx}} 变量
+	CreatedAt   int64                                 `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt   int64                                 `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (AlertExpressionRule) TableName() string {
+	return "alert_expression_rules"
+}
+
+// AlertSilence 告警静默规则
+//
+// 所有非空匹配条件同时满足时命中，命中期间仍会记录告警，但不发送通知。
+type AlertSilence struct {
+	ID        string `gorm:"primaryKey" json:"id"`                  // 静默ID (UUID)
+	AgentID   string `json:"agentId"`                               // 匹配探针ID
+	Tag       string `json:"tag"`                                   // 匹配探针标签
+	AlertType string `json:"alertType"`                             // 匹配告警类型
+	MonitorID string `json:"monitorId"`                             // 匹配监控项ID
+	StartsAt  int64  `gorm:"index" json:"startsAt"`                 // 开始时间（时间戳毫秒）
+	EndsAt    int64  `gorm:"index" json:"endsAt"`                   // 结束时间（时间戳毫秒）
+	CreatedBy string `json:"createdBy"`                             // 创建人
+	Comment   string `json:"comment"`                               // 备注
+	CreatedAt int64  `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (AlertSilence) TableName() string {
+	return "alert_silences"
+}
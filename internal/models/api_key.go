@@ -1,10 +1,17 @@
 package models
 
+// API 密钥用途，不同用途的密钥不能互相替代，避免探针注册密钥泄露后被用于伪造告警
+const (
+	ApiKeyScopeAgent = "agent" // 探针注册
+	ApiKeyScopeAlert = "alert" // 外部告警接入
+)
+
 // ApiKey API密钥信息
 type ApiKey struct {
 	ID        string `gorm:"primaryKey" json:"id"`                  // 密钥ID (UUID)
 	Name      string `gorm:"index" json:"name"`                     // 密钥名称/备注
 	Key       string `gorm:"uniqueIndex" json:"key"`                // API密钥
+	Scope     string `gorm:"index;default:agent" json:"scope"`      // 用途: agent（探针注册）, alert（外部告警接入）
 	Enabled   bool   `gorm:"index;default:true" json:"enabled"`     // 是否启用
 	CreatedBy string `gorm:"index" json:"createdBy"`                // 创建人ID
 	CreatedAt int64  `json:"createdAt"`                             // 创建时间（时间戳毫秒）
@@ -0,0 +1,30 @@
+package models
+
+import "gorm.io/datatypes"
+
+// MaintenanceWindow 维护窗口
+//
+// 维护期间暂停作用范围内的服务监控检测，并且不触发相关告警。
+type MaintenanceWindow struct {
+	ID          string                      `gorm:"primaryKey" json:"id"`                  // 维护窗口ID (UUID)
+	Name        string                      `gorm:"uniqueIndex" json:"name"`               // 名称
+	Description string                      `json:"description"`                           // 描述
+	Enabled     bool                        `json:"enabled"`                               // 是否启用
+	Recurrence  string                      `json:"recurrence"`                            // 重复方式: once（单次）, weekly（每周）, cron（cron 表达式）
+	StartsAt    int64                       `json:"startsAt"`                              // 单次维护开始时间（时间戳毫秒）
+	EndsAt      int64                       `json:"endsAt"`                                // 单次维护结束时间（时间戳毫秒）
+	Weekdays    datatypes.JSONSlice[int]    `json:"weekdays"`                              // 每周维护的星期（0 表示周日）
+	StartTime   string                      `json:"startTime"`                             // 每周维护的开始时间，格式 HH:MM
+	Cron        string                      `json:"cron"`                                  // cron 表达式（分 时 日 月 周），表示每次维护的开始时间
+	Duration    int                         `json:"duration"`                              // 每次维护的持续时间（分钟），weekly/cron 生效
+	Timezone    string                      `json:"timezone"`                              // 时区，如 Asia/Shanghai，为空使用服务器时区
+	AgentIds    datatypes.JSONSlice[string] `json:"agentIds"`                              // 作用的探针ID列表
+	Tags        datatypes.JSONSlice[string] `json:"tags"`                                  // 作用的探针标签列表
+	MonitorIds  datatypes.JSONSlice[string] `json:"monitorIds"`                            // 作用的监控项ID列表
+	CreatedAt   int64                       `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt   int64                       `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_windows"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "gorm.io/datatypes"
+
+// 通知投递状态
+const (
+	DeliveryStatusPending = "pending" // 待发送或等待重试
+	DeliveryStatusSent    = "sent"    // 已送达
+	DeliveryStatusFailed  = "failed"  // 重试次数用尽后仍失败
+	// DeliveryStatusSuperseded 告警已有更新的通知或状态已变化，不再重试旧的通知
+	DeliveryStatusSuperseded = "superseded"
+)
+
+// NotificationDelivery 通知投递记录（通知发件箱），每条通知在每个渠道的投递对应一条记录
+type NotificationDelivery struct {
+	ID          int64          `gorm:"primaryKey;autoIncrement" json:"id"`    // 投递ID
+	RecordID    int64          `gorm:"index" json:"recordId"`                 // 告警记录ID，聚合通知为第一条告警的记录ID
+	RecordIDs   string         `json:"recordIds"`                             // 通知包含的全部告警记录ID，格式: ,1,2,3,
+	GroupKey    string         `json:"groupKey,omitempty"`                    // 聚合通知的分组键
+	ChannelType string         `gorm:"index" json:"channelType"`              // 通知渠道类型
+	AlertStatus string         `json:"alertStatus"`                           // 通知对应的告警状态: firing, acknowledged, resolved, flapping
+	Level       string         `json:"level"`                                 // 告警级别
+	Summary     string         `json:"summary"`                               // 通知摘要
+	Payload     datatypes.JSON `json:"-"`                                     // 通知内容快照，重试时按快照重新发送
+	Status      string         `gorm:"index" json:"status"`                   // 投递状态: pending, sent, failed, superseded
+	Attempts    int            `json:"attempts"`                              // 已尝试发送次数
+	LastError   string         `json:"lastError,omitempty"`                   // 最后一次发送失败的原因
+	NextRetryAt int64          `gorm:"index" json:"nextRetryAt,omitempty"`    // 下次重试时间（时间戳毫秒）
+	DeliveredAt int64          `json:"deliveredAt,omitempty"`                 // 送达时间（时间戳毫秒）
+	CreatedAt   int64          `gorm:"index" json:"createdAt"`                // 创建时间（时间戳毫秒）
+	UpdatedAt   int64          `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
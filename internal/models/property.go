@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 // Property 通用属性配置表
 type Property struct {
 	ID        string `gorm:"primaryKey" json:"id"`                  // 属性ID (如: notification_channels)
@@ -15,15 +17,45 @@ func (Property) TableName() string {
 
 // NotificationChannelConfig 通知渠道配置（存储在 Property 中）
 type NotificationChannelConfig struct {
-	Type    string                 `json:"type"`    // 类型: dingtalk, wecom, feishu, webhook
-	Enabled bool                   `json:"enabled"` // 是否启用
-	Config  map[string]interface{} `json:"config"`  // 配置对象
+	Type      string                 `json:"type"`                // 类型: dingtalk, wecom, feishu, slack, discord, teams, ntfy, gotify, bark, pushover, matrix, pagerduty, opsgenie, alertmanager, webhook, exec
+	Enabled   bool                   `json:"enabled"`             // 是否启用
+	Config    map[string]interface{} `json:"config"`              // 配置对象
+	Templates []NotificationTemplate `json:"templates,omitempty"` // 自定义消息模板，未配置时使用内置消息
+}
+
+// NotificationTemplate 通知消息模板
+//
+// 模板使用 {{变量}} 语法，变量与自定义 Webhook 的 customBody 相同，{{message}} 为内置消息。
+// 按告警类型精确匹配优先，其次使用 AlertType 为空的通用模板；模板为空时使用内置消息。
+type NotificationTemplate struct {
+	AlertType string `json:"alertType"` // 告警类型，为空匹配所有类型
+	Firing    string `json:"firing"`    // 告警触发消息模板
+	Resolved  string `json:"resolved"`  // 告警恢复消息模板
 }
 
 // 配置格式说明：
 // dingtalk: { "secretKey": "xxx", "signSecret": "xxx" }
 // wecom:    { "secretKey": "xxx" }
 // feishu:   { "secretKey": "xxx", "signSecret": "xxx" }
+// email:    {
+//   "smtpHost": "smtp.example.com", "smtpPort": 465, "fromEmail": "xxx", "password": "xxx",
+//   "security": "ssl",  // 可选：ssl, starttls, none，默认 465 端口使用 ssl，其他端口在服务器支持时使用 STARTTLS
+//   "toEmail": "a@example.com, b@example.com",  // 多个地址用逗号或分号分隔，也可以是数组；cc、bcc 格式相同
+//   "subject": "[{{alert.level}}] {{agent.name}}",  // 可选：主题模板，聚合通知额外支持 {{count}}
+//   "format": "html",  // 可选：html(默认，附带纯文本), text
+//   "htmlTemplate": ""  // 可选：自定义 HTML 模板（html/template 语法）
+// }
+// slack:    { "webhookUrl": "https://hooks.slack.com/..." } 或 { "botToken": "xoxb-...", "channel": "C123" }
+// discord:  { "webhookUrl": "https://discord.com/api/webhooks/..." }
+// teams:    { "webhookUrl": "https://...logic.azure.com/workflows/..." }
+// ntfy:     { "server": "https://ntfy.sh", "topic": "xxx", "token": "可选" }
+// gotify:   { "server": "https://gotify.example.com", "token": "应用 token" }
+// bark:     { "server": "https://api.day.app", "deviceKey": "xxx", "sound": "可选" }
+// pushover: { "token": "应用 token", "user": "用户 key", "device": "可选" }
+// matrix:   { "homeserver": "https://matrix.org", "accessToken": "xxx", "roomId": "!room:matrix.org" }
+// pagerduty: { "routingKey": "Events API v2 集成 key", "apiUrl": "可选" }
+// opsgenie: { "apiKey": "xxx", "apiUrl": "可选，EU 区域为 https://api.eu.opsgenie.com" }
+// alertmanager: { "url": "Webhook 地址，api 模式为 Alertmanager 地址", "mode": "webhook(默认) | api", "bearerToken": "可选" }
 // webhook:  {
 //   "url": "https://...",
 //   "method": "POST",  // 可选：GET, POST, PUT, PATCH, DELETE，默认 POST
@@ -31,6 +63,22 @@ type NotificationChannelConfig struct {
 //   "bodyTemplate": "json"  // 可选：json(默认), form, custom
 //   "customBody": ""  // 当 bodyTemplate 为 custom 时使用，支持变量替换
 // }
+// exec:     { "script": "脚本目录下的脚本路径", "args": ["可选"], "timeout": 30 }  // 需要在服务端配置 Notification.ExecScriptDir
+
+// NotificationRoute 通知路由规则（存储在 Property 中）
+//
+// 路由按顺序匹配，所有非空条件同时满足时命中；命中后除非设置 Continue，否则停止匹配后续规则。
+// 没有任何规则命中时，告警发送到所有已启用的渠道。
+type NotificationRoute struct {
+	Name       string   `json:"name"`       // 规则名称
+	Enabled    bool     `json:"enabled"`    // 是否启用
+	AlertTypes []string `json:"alertTypes"` // 匹配的告警类型，为空匹配所有
+	Levels     []string `json:"levels"`     // 匹配的告警级别，为空匹配所有
+	Tags       []string `json:"tags"`       // 匹配的探针标签，拥有任一标签即命中，为空匹配所有
+	AgentIds   []string `json:"agentIds"`   // 匹配的探针ID，为空匹配所有
+	Channels   []string `json:"channels"`   // 通知渠道类型
+	Continue   bool     `json:"continue"`   // 命中后是否继续匹配后续规则
+}
 
 // DNSProviderConfig DNS 服务商配置（存储在 Property 中）
 type DNSProviderConfig struct {
@@ -60,40 +108,205 @@ type SystemConfig struct {
 	LogoBase64   string `json:"logoBase64"`   // 系统logo（base64编码）
 	ICPCode      string `json:"icpCode"`      // ICP备案号
 	DefaultView  string `json:"defaultView"`  // 默认视图 grid | list
+	Locale       string `json:"locale"`       // 通知消息与接口错误信息的语言 zh | en
 }
 
 // AlertConfig 全局告警配置
 type AlertConfig struct {
-	Enabled bool       `json:"enabled"` // 是否启用全局告警
-	MaskIP  bool       `json:"maskIP"`  // 是否在通知中打码 IP 地址
-	Rules   AlertRules `json:"rules"`   // 告警规则
+	Enabled    bool             `json:"enabled"`    // 是否启用全局告警
+	MaskIP     bool             `json:"maskIP"`     // 是否在通知中打码 IP 地址
+	Rules      AlertRules       `json:"rules"`      // 告警规则
+	Escalation EscalationPolicy `json:"escalation"` // 重复通知与升级策略
+	Grouping   GroupingPolicy   `json:"grouping"`   // 告警分组聚合策略
+	Inhibition InhibitionPolicy `json:"inhibition"` // 告警抑制策略
+	Flapping   FlappingPolicy   `json:"flapping"`   // 告警抖动检测策略
+}
+
+// FlappingPolicy 告警抖动检测策略：时间窗口内状态变化次数达到阈值时视为抖动，
+// 抖动期间只发送一次抖动通知，不再发送每次的触发和恢复通知（PagerDuty、Opsgenie、Alertmanager 等事件管理渠道除外），
+// 抖动结束后补发最终状态的通知
+type FlappingPolicy struct {
+	Enabled   bool `json:"enabled"`   // 是否启用
+	Threshold int  `json:"threshold"` // 状态变化次数阈值
+	Window    int  `json:"window"`    // 统计窗口（秒）
+}
+
+// InhibitionPolicy 告警抑制策略
+type InhibitionPolicy struct {
+	DisableDefault bool          `json:"disableDefault"` // 是否禁用内置规则（探针离线时抑制该探针的其他告警）
+	Rules          []InhibitRule `json:"rules"`          // 自定义抑制规则
+}
+
+// 告警抑制规则的相等维度
+const (
+	InhibitEqualAgent   = "agent"   // 同一探针
+	InhibitEqualMonitor = "monitor" // 同一监控项
+)
+
+// InhibitRule 告警抑制规则：存在匹配源条件的未恢复告警时，抑制匹配目标条件且相等维度取值相同的告警通知
+type InhibitRule struct {
+	Name         string   `json:"name"`         // 规则名称
+	Enabled      bool     `json:"enabled"`      // 是否启用
+	SourceTypes  []string `json:"sourceTypes"`  // 源告警类型，为空匹配所有
+	SourceLevels []string `json:"sourceLevels"` // 源告警级别，为空匹配所有
+	TargetTypes  []string `json:"targetTypes"`  // 目标告警类型，为空匹配所有
+	TargetLevels []string `json:"targetLevels"` // 目标告警级别，为空匹配所有
+	Equal        []string `json:"equal"`        // 源告警与目标告警取值必须相同的维度: agent, monitor
+}
+
+// 告警分组维度
+const (
+	AlertGroupByAlertType = "alertType" // 按告警类型
+	AlertGroupByLevel     = "level"     // 按告警级别
+	AlertGroupByTag       = "tag"       // 按探针标签
+	AlertGroupByMonitor   = "monitor"   // 按监控项
+	AlertGroupByAgent     = "agent"     // 按探针
+)
+
+// GroupingPolicy 告警分组聚合策略，同一分组内的触发和恢复通知合并为一条发送
+type GroupingPolicy struct {
+	Enabled       bool     `json:"enabled"`       // 是否启用
+	GroupBy       []string `json:"groupBy"`       // 分组维度，为空时按告警类型分组
+	GroupWait     int      `json:"groupWait"`     // 新分组首次发送前的等待时间（秒）
+	GroupInterval int      `json:"groupInterval"` // 同一分组两次发送之间的最小间隔（秒）
+}
+
+// EscalationPolicy 告警重复通知与升级策略，仅对未确认的告警生效
+type EscalationPolicy struct {
+	Enabled        bool             `json:"enabled"`        // 是否启用
+	Channels       []string         `json:"channels"`       // 未命中通知路由规则时首次通知的渠道类型，为空表示所有已启用渠道
+	RepeatInterval int              `json:"repeatInterval"` // 重复通知间隔（分钟），0 表示不重复
+	Steps          []EscalationStep `json:"steps"`          // 升级步骤，按延迟时间升序
+}
+
+// EscalationStep 告警升级步骤
+type EscalationStep struct {
+	Delay    int      `json:"delay"`    // 告警触发后多久升级（分钟）
+	Channels []string `json:"channels"` // 升级后追加通知的渠道类型
+}
+
+// NotifyChannels 计算指定升级级别下的通知渠道类型，返回 nil 表示所有已启用渠道
+func (p EscalationPolicy) NotifyChannels(level int) []string {
+	if !p.Enabled || len(p.Channels) == 0 {
+		return nil
+	}
+	return p.AppendStepChannels(append([]string{}, p.Channels...), level)
+}
+
+// AppendStepChannels 将指定升级级别已到达的升级步骤渠道追加到 channels 中（去重）
+func (p EscalationPolicy) AppendStepChannels(channels []string, level int) []string {
+	if !p.Enabled {
+		return channels
+	}
+	for i := 0; i < level && i < len(p.Steps); i++ {
+		for _, channel := range p.Steps[i].Channels {
+			if !slices.Contains(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+	return channels
+}
+
+// 异常检测基线模式
+const (
+	AnomalyModeRolling  = "rolling"   // 最近一段时间的均值和标准差
+	AnomalyModeSameHour = "same_hour" // 过去 N 天同一时段的均值和标准差
+)
+
+// 异常检测方向
+const (
+	AnomalyDirectionUp   = "up"   // 只检测高于基线
+	AnomalyDirectionDown = "down" // 只检测低于基线
+	AnomalyDirectionBoth = "both" // 双向检测
+)
+
+// AnomalyRule 异常检测规则：当前值偏离历史基线超过 Sigma 倍标准差时告警
+type AnomalyRule struct {
+	Enabled   bool    `json:"enabled"`   // 是否启用
+	Metric    string  `json:"metric"`    // 指标: cpu, memory, load, network_in, network_out, connections
+	Mode      string  `json:"mode"`      // 基线模式: rolling, same_hour，默认 rolling
+	Window    int     `json:"window"`    // rolling 模式的基线时长（小时），默认 24
+	Days      int     `json:"days"`      // same_hour 模式回溯的天数，默认 7
+	Sigma     float64 `json:"sigma"`     // 标准差倍数，默认 3
+	Direction string  `json:"direction"` // 检测方向: up, down, both，默认 up
+	Duration  int     `json:"duration"`  // 持续时间（秒）
+}
+
+// DiskMountThreshold 挂载点磁盘告警阈值覆盖
+type DiskMountThreshold struct {
+	Pattern          string  `json:"pattern"`          // 挂载点模式（glob）
+	Threshold        float64 `json:"threshold"`        // 磁盘使用率阈值(0-100)
+	ResolveThreshold float64 `json:"resolveThreshold"` // 恢复阈值，0 表示与告警阈值相同
 }
 
 // AlertRules 告警规则
 type AlertRules struct {
 	// CPU 告警配置
-	CPUEnabled   bool    `json:"cpuEnabled"`   // 是否启用CPU告警
-	CPUThreshold float64 `json:"cpuThreshold"` // CPU使用率阈值(0-100)
-	CPUDuration  int     `json:"cpuDuration"`  // 持续时间（秒）
+	CPUEnabled          bool    `json:"cpuEnabled"`          // 是否启用CPU告警
+	CPUThreshold        float64 `json:"cpuThreshold"`        // CPU使用率阈值(0-100)
+	CPUDuration         int     `json:"cpuDuration"`         // 持续时间（秒）
+	CPUResolveThreshold float64 `json:"cpuResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
 
 	// 内存告警配置
-	MemoryEnabled   bool    `json:"memoryEnabled"`   // 是否启用内存告警
-	MemoryThreshold float64 `json:"memoryThreshold"` // 内存使用率阈值(0-100)
-	MemoryDuration  int     `json:"memoryDuration"`  // 持续时间（秒）
+	MemoryEnabled          bool    `json:"memoryEnabled"`          // 是否启用内存告警
+	MemoryThreshold        float64 `json:"memoryThreshold"`        // 内存使用率阈值(0-100)
+	MemoryDuration         int     `json:"memoryDuration"`         // 持续时间（秒）
+	MemoryResolveThreshold float64 `json:"memoryResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
+
+	// 负载告警配置
+	LoadEnabled          bool    `json:"loadEnabled"`          // 是否启用负载告警
+	LoadThreshold        float64 `json:"loadThreshold"`        // 每核 1 分钟平均负载阈值
+	LoadDuration         int     `json:"loadDuration"`         // 持续时间（秒）
+	LoadResolveThreshold float64 `json:"loadResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
+
+	// Swap 告警配置
+	SwapEnabled          bool    `json:"swapEnabled"`          // 是否启用 Swap 告警
+	SwapThreshold        float64 `json:"swapThreshold"`        // Swap 使用率阈值(0-100)
+	SwapDuration         int     `json:"swapDuration"`         // 持续时间（秒）
+	SwapResolveThreshold float64 `json:"swapResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
+
+	// CPU Steal 告警配置
+	StealEnabled          bool    `json:"stealEnabled"`          // 是否启用 CPU Steal 告警
+	StealThreshold        float64 `json:"stealThreshold"`        // CPU Steal 占比阈值(0-100)
+	StealDuration         int     `json:"stealDuration"`         // 持续时间（秒）
+	StealResolveThreshold float64 `json:"stealResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
 
 	// 磁盘告警配置
-	DiskEnabled   bool    `json:"diskEnabled"`   // 是否启用磁盘告警
-	DiskThreshold float64 `json:"diskThreshold"` // 磁盘使用率阈值(0-100)
-	DiskDuration  int     `json:"diskDuration"`  // 持续时间（秒）
+	DiskEnabled          bool    `json:"diskEnabled"`          // 是否启用磁盘告警
+	DiskThreshold        float64 `json:"diskThreshold"`        // 磁盘使用率阈值(0-100)
+	DiskDuration         int     `json:"diskDuration"`         // 持续时间（秒）
+	DiskResolveThreshold float64 `json:"diskResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
+
+	// 按挂载点的磁盘告警配置，挂载点模式使用 glob 语法，如 /mnt/*
+	DiskInclude         []string             `json:"diskInclude"`         // 参与告警的挂载点模式，为空包含所有挂载点
+	DiskExclude         []string             `json:"diskExclude"`         // 排除的挂载点模式
+	DiskMountThresholds []DiskMountThreshold `json:"diskMountThresholds"` // 按挂载点覆盖阈值，按顺序匹配第一条
+
+	// Inode 告警配置
+	InodeEnabled          bool    `json:"inodeEnabled"`          // 是否启用 inode 告警
+	InodeThreshold        float64 `json:"inodeThreshold"`        // inode 使用率阈值(0-100)
+	InodeDuration         int     `json:"inodeDuration"`         // 持续时间（秒）
+	InodeResolveThreshold float64 `json:"inodeResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
+
+	// 异常检测告警配置
+	AnomalyRules []AnomalyRule `json:"anomalyRules"` // 基于历史基线的异常检测规则
+
+	// 磁盘写满预测告警配置
+	DiskForecastEnabled  bool `json:"diskForecastEnabled"`  // 是否启用磁盘写满预测告警
+	DiskForecastHorizon  int  `json:"diskForecastHorizon"`  // 预测范围（小时），预计在该时间内写满时告警
+	DiskForecastLookback int  `json:"diskForecastLookback"` // 用于线性回归的历史数据时长（小时）
 
 	// 网络告警配置
-	NetworkEnabled   bool    `json:"networkEnabled"`   // 是否启用网络告警
-	NetworkThreshold float64 `json:"networkThreshold"` // 网速阈值(MB/s)
-	NetworkDuration  int     `json:"networkDuration"`  // 持续时间（秒）
+	NetworkEnabled          bool    `json:"networkEnabled"`          // 是否启用网络告警
+	NetworkThreshold        float64 `json:"networkThreshold"`        // 网速阈值(MB/s)
+	NetworkDuration         int     `json:"networkDuration"`         // 持续时间（秒）
+	NetworkResolveThreshold float64 `json:"networkResolveThreshold"` // 恢复阈值，低于该值才恢复，0 表示与告警阈值相同
 
 	// HTTPS 证书告警配置
-	CertEnabled   bool    `json:"certEnabled"`   // 是否启用证书告警
-	CertThreshold float64 `json:"certThreshold"` // 证书剩余天数阈值
+	CertEnabled          bool    `json:"certEnabled"`          // 是否启用证书告警
+	CertThreshold        float64 `json:"certThreshold"`        // 证书剩余天数阈值
+	CertResolveThreshold float64 `json:"certResolveThreshold"` // 恢复阈值，剩余天数高于该值才恢复，0 表示与告警阈值相同
 
 	// 服务下线告警配置
 	ServiceEnabled  bool `json:"serviceEnabled"`  // 是否启用服务下线告警
@@ -102,4 +315,17 @@ type AlertRules struct {
 	// 探针离线告警配置
 	AgentOfflineEnabled  bool `json:"agentOfflineEnabled"`  // 是否启用探针离线告警
 	AgentOfflineDuration int  `json:"agentOfflineDuration"` // 持续时间（秒）
+
+	// 防篡改告警配置
+	TamperEventEnabled bool `json:"tamperEventEnabled"` // 是否启用受保护目录文件变更告警
+	TamperAttrEnabled  bool `json:"tamperAttrEnabled"`  // 是否启用不可变属性被移除告警
+	TamperRateLimit    int  `json:"tamperRateLimit"`    // 同一受保护目录的通知间隔（秒），间隔内的后续事件合并到同一告警记录
+
+	// DDNS 告警配置
+	DDNSFailureEnabled   bool `json:"ddnsFailureEnabled"`   // 是否启用 DDNS 更新失败告警
+	DDNSFailureThreshold int  `json:"ddnsFailureThreshold"` // 连续更新失败次数阈值
+	DDNSIPChangeEnabled  bool `json:"ddnsIpChangeEnabled"`  // 是否在公网 IP 变化时发送通知
+
+	// 告警恢复配置
+	ResolveDuration int `json:"resolveDuration"` // 恢复条件需要持续满足的时间（秒），作用于指标、证书和服务下线告警
 }
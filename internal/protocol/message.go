@@ -87,8 +87,11 @@ type CPUData struct {
 	PhysicalCores int    `json:"physicalCores"`
 	ModelName     string `json:"modelName"`
 	// 动态信息
-	UsagePercent float64   `json:"usagePercent"`
-	PerCore      []float64 `json:"perCore,omitempty"`
+	UsagePercent  float64   `json:"usagePercent"`
+	PerCore       []float64 `json:"perCore,omitempty"`
+	StealPercent  float64   `json:"stealPercent"`   // 被宿主机挤占的 CPU 时间占比
+	IOWaitPercent float64   `json:"iowaitPercent"`  // 等待 IO 的 CPU 时间占比
+	Load          *LoadData `json:"load,omitempty"` // 系统负载（Windows 不支持）
 }
 
 // MemoryData 内存数据
@@ -103,6 +106,8 @@ type MemoryData struct {
 	SwapTotal    uint64  `json:"swapTotal,omitempty"`
 	SwapUsed     uint64  `json:"swapUsed,omitempty"`
 	SwapFree     uint64  `json:"swapFree,omitempty"`
+
+	SwapUsagePercent float64 `json:"swapUsagePercent,omitempty"`
 }
 
 // DiskData 磁盘数据
@@ -114,6 +119,11 @@ type DiskData struct {
 	Used         uint64  `json:"used"`
 	Free         uint64  `json:"free"`
 	UsagePercent float64 `json:"usagePercent"`
+
+	InodesTotal        uint64  `json:"inodesTotal,omitempty"`
+	InodesUsed         uint64  `json:"inodesUsed,omitempty"`
+	InodesFree         uint64  `json:"inodesFree,omitempty"`
+	InodesUsagePercent float64 `json:"inodesUsagePercent,omitempty"`
 }
 
 // DiskIOData 磁盘IO数据
@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type AlertExpressionRuleRepo struct {
+	orz.Repository[models.AlertExpressionRule, string]
+	db *gorm.DB
+}
+
+func NewAlertExpressionRuleRepo(db *gorm.DB) *AlertExpressionRuleRepo {
+	return &AlertExpressionRuleRepo{
+		Repository: orz.NewRepository[models.AlertExpressionRule, string](db),
+		db:         db,
+	}
+}
+
+// FindAllEnabled 查找所有已启用的表达式告警规则
+func (r *AlertExpressionRuleRepo) FindAllEnabled(ctx context.Context) ([]models.AlertExpressionRule, error) {
+	var rules []models.AlertExpressionRule
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// UpdateEnabled 更新启用状态
+func (r *AlertExpressionRuleRepo) UpdateEnabled(ctx context.Context, id string, enabled bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AlertExpressionRule{}).
+		Where("id = ?", id).
+		Update("enabled", enabled).Error
+}
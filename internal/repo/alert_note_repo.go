@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type AlertNoteRepo struct {
+	orz.Repository[models.AlertNote, int64]
+	db *gorm.DB
+}
+
+func NewAlertNoteRepo(db *gorm.DB) *AlertNoteRepo {
+	return &AlertNoteRepo{
+		Repository: orz.NewRepository[models.AlertNote, int64](db),
+		db:         db,
+	}
+}
+
+// ListByRecordID 获取告警记录的备注（按时间正序）
+func (r *AlertNoteRepo) ListByRecordID(ctx context.Context, recordID int64) ([]models.AlertNote, error) {
+	var notes []models.AlertNote
+	err := r.db.WithContext(ctx).
+		Where("record_id = ?", recordID).
+		Order("created_at ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+func (r *AlertNoteRepo) Clear(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("1=1").Delete(&models.AlertNote{}).Error
+}
@@ -46,7 +46,7 @@ func (r *AlertRecordRepo) GetAlertRecordByID(ctx context.Context, id int64) (*mo
 func (r *AlertRecordRepo) GetLatestAlertRecord(ctx context.Context, configID string, alertType string) (*models.AlertRecord, error) {
 	var record models.AlertRecord
 	err := r.db.WithContext(ctx).
-		Where("config_id = ? AND alert_type = ? AND status = ?", configID, alertType, "firing").
+		Where("config_id = ? AND alert_type = ? AND status IN ?", configID, alertType, []string{"firing", "acknowledged"}).
 		Order("fired_at DESC").
 		First(&record).Error
 	if err != nil {
@@ -55,6 +55,74 @@ func (r *AlertRecordRepo) GetLatestAlertRecord(ctx context.Context, configID str
 	return &record, nil
 }
 
+// GetActiveByStateID 获取告警状态对应的未恢复告警记录
+func (r *AlertRecordRepo) GetActiveByStateID(ctx context.Context, stateID string) (*models.AlertRecord, error) {
+	var record models.AlertRecord
+	err := r.db.WithContext(ctx).
+		Where("state_id = ? AND status IN ?", stateID, []string{"firing", "acknowledged"}).
+		Order("fired_at DESC").
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetLatestByStateID 获取告警状态对应的最新一条告警记录
+func (r *AlertRecordRepo) GetLatestByStateID(ctx context.Context, stateID string) (*models.AlertRecord, error) {
+	var record models.AlertRecord
+	err := r.db.WithContext(ctx).
+		Where("state_id = ?", stateID).
+		Order("fired_at DESC").
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindActiveByTypes 获取指定类型中未恢复的告警记录，types 为空时返回所有类型
+func (r *AlertRecordRepo) FindActiveByTypes(ctx context.Context, types []string) ([]models.AlertRecord, error) {
+	var records []models.AlertRecord
+	db := r.db.WithContext(ctx).Where("status IN ?", []string{"firing", "acknowledged"})
+	if len(types) > 0 {
+		db = db.Where("alert_type IN ?", types)
+	}
+	err := db.Order("fired_at ASC").Find(&records).Error
+	return records, err
+}
+
+// Acknowledge 确认告警中的记录，未指派处理人时指派给确认人，记录已不在告警中时返回 false
+//
+// 只更新确认相关的字段，并以 status = firing 作为更新条件，避免覆盖同时发生的恢复
+func (r *AlertRecordRepo) Acknowledge(ctx context.Context, id int64, username string, acknowledgedAt int64) (bool, error) {
+	result := r.GetDB(ctx).
+		Model(&models.AlertRecord{}).
+		Where("id = ? AND status = ?", id, "firing").
+		UpdateColumns(map[string]interface{}{
+			"status":          "acknowledged",
+			"acknowledged_by": username,
+			"acknowledged_at": acknowledgedAt,
+			"assignee":        gorm.Expr("COALESCE(NULLIF(assignee, ''), ?)", username),
+			"updated_at":      acknowledgedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkNotified 记录一次告警通知发送
+func (r *AlertRecordRepo) MarkNotified(ctx context.Context, id int64, notifiedAt int64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AlertRecord{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"notify_count":     gorm.Expr("notify_count + 1"),
+			"last_notified_at": notifiedAt,
+		}).Error
+}
+
 func (r *AlertRecordRepo) Clear(ctx context.Context) error {
 	return r.db.WithContext(ctx).Where("1=1").Delete(&models.AlertRecord{}).Error
 }
@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type AlertRuleSetRepo struct {
+	orz.Repository[models.AlertRuleSet, string]
+	db *gorm.DB
+}
+
+func NewAlertRuleSetRepo(db *gorm.DB) *AlertRuleSetRepo {
+	return &AlertRuleSetRepo{
+		Repository: orz.NewRepository[models.AlertRuleSet, string](db),
+		db:         db,
+	}
+}
+
+// FindAllEnabled 查找所有已启用的告警规则集（按优先级降序、创建时间升序）
+func (r *AlertRuleSetRepo) FindAllEnabled(ctx context.Context) ([]models.AlertRuleSet, error) {
+	var ruleSets []models.AlertRuleSet
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("priority DESC, created_at ASC").
+		Find(&ruleSets).Error
+	return ruleSets, err
+}
+
+// UpdateEnabled 更新启用状态
+func (r *AlertRuleSetRepo) UpdateEnabled(ctx context.Context, id string, enabled bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AlertRuleSet{}).
+		Where("id = ?", id).
+		Update("enabled", enabled).Error
+}
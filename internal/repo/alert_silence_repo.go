@@ -0,0 +1,31 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type AlertSilenceRepo struct {
+	orz.Repository[models.AlertSilence, string]
+	db *gorm.DB
+}
+
+func NewAlertSilenceRepo(db *gorm.DB) *AlertSilenceRepo {
+	return &AlertSilenceRepo{
+		Repository: orz.NewRepository[models.AlertSilence, string](db),
+		db:         db,
+	}
+}
+
+// FindActive 查找在指定时间生效的静默规则
+func (r *AlertSilenceRepo) FindActive(ctx context.Context, now int64) ([]models.AlertSilence, error) {
+	var silences []models.AlertSilence
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND ends_at > ?", now, now).
+		Order("created_at ASC").
+		Find(&silences).Error
+	return silences, err
+}
@@ -46,6 +46,45 @@ func (r *AlertStateRepo) DeleteAlertStatesByConfigID(ctx context.Context, config
 	return r.db.WithContext(ctx).Where("config_id = ?", configID).Delete(&models.AlertState{}).Error
 }
 
+// FindByAgentID 查找探针的所有告警状态
+func (r *AlertStateRepo) FindByAgentID(ctx context.Context, agentID string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).Find(&states).Error
+	return states, err
+}
+
+// FindByConfigID 查找配置相关的所有告警状态
+func (r *AlertStateRepo) FindByConfigID(ctx context.Context, configID string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).Where("config_id = ?", configID).Find(&states).Error
+	return states, err
+}
+
+// FindByAlertType 查找指定告警类型的所有告警状态
+func (r *AlertStateRepo) FindByAlertType(ctx context.Context, alertType string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).Where("alert_type = ?", alertType).Find(&states).Error
+	return states, err
+}
+
+// FindFiring 查找所有正在告警的状态
+func (r *AlertStateRepo) FindFiring(ctx context.Context) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).
+		Where("is_firing = ? AND last_record_id > 0", true).
+		Find(&states).Error
+	return states, err
+}
+
+// FindFlapping 查找所有处于抖动状态的告警状态
+func (r *AlertStateRepo) FindFlapping(ctx context.Context) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).
+		Where("is_flapping = ?", true).
+		Find(&states).Error
+	return states, err
+}
+
 // LoadAllStates 加载所有告警状态
 func (r *AlertStateRepo) LoadAllStates(ctx context.Context) ([]models.AlertState, error) {
 	var states []models.AlertState
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type MaintenanceWindowRepo struct {
+	orz.Repository[models.MaintenanceWindow, string]
+	db *gorm.DB
+}
+
+func NewMaintenanceWindowRepo(db *gorm.DB) *MaintenanceWindowRepo {
+	return &MaintenanceWindowRepo{
+		Repository: orz.NewRepository[models.MaintenanceWindow, string](db),
+		db:         db,
+	}
+}
+
+// FindAllEnabled 查找所有已启用的维护窗口
+func (r *MaintenanceWindowRepo) FindAllEnabled(ctx context.Context) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Find(&windows).Error
+	return windows, err
+}
+
+// UpdateEnabled 更新启用状态
+func (r *MaintenanceWindowRepo) UpdateEnabled(ctx context.Context, id string, enabled bool) error {
+	return r.db.WithContext(ctx).
+		Model(&models.MaintenanceWindow{}).
+		Where("id = ?", id).
+		Update("enabled", enabled).Error
+}
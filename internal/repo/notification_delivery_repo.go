@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type NotificationDeliveryRepo struct {
+	orz.Repository[models.NotificationDelivery, int64]
+	db *gorm.DB
+}
+
+func NewNotificationDeliveryRepo(db *gorm.DB) *NotificationDeliveryRepo {
+	return &NotificationDeliveryRepo{
+		Repository: orz.NewRepository[models.NotificationDelivery, int64](db),
+		db:         db,
+	}
+}
+
+// CreateDelivery 创建投递记录
+func (r *NotificationDeliveryRepo) CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// UpdateDelivery 更新投递记录
+func (r *NotificationDeliveryRepo) UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
+
+// FindDue 获取到达重试时间的待发送记录（按重试时间正序）
+func (r *NotificationDeliveryRepo) FindDue(ctx context.Context, now int64, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_retry_at <= ?", models.DeliveryStatusPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// Claim 领取投递记录，只有重试时间未被其他发送方修改时才能领取成功，领取后在 leaseUntil 之前不会被再次重试
+func (r *NotificationDeliveryRepo) Claim(ctx context.Context, id, nextRetryAt, leaseUntil int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("id = ? AND next_retry_at = ?", id, nextRetryAt).
+		Updates(map[string]interface{}{
+			"status":        models.DeliveryStatusPending,
+			"next_retry_at": leaseUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ExistsNewer 判断同一告警记录在同一渠道是否有更新的投递记录
+func (r *NotificationDeliveryRepo) ExistsNewer(ctx context.Context, recordID int64, channelType string, id int64) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.NotificationDelivery{}).
+		Where("record_id = ? AND channel_type = ? AND id > ?", recordID, channelType, id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *NotificationDeliveryRepo) Clear(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("1=1").Delete(&models.NotificationDelivery{}).Error
+}
@@ -23,17 +23,19 @@ type MonitorScheduler struct {
 	cron           *cron.Cron
 	tasks          map[string]*MonitorTask // taskID -> MonitorTask
 	monitorService *service.MonitorService
+	maintenance    *service.MaintenanceService
 	logger         *zap.Logger
 	ctx            context.Context
 	cancel         context.CancelFunc
 }
 
 // NewMonitorScheduler 创建监控任务调度器
-func NewMonitorScheduler(monitorService *service.MonitorService, logger *zap.Logger) *MonitorScheduler {
+func NewMonitorScheduler(monitorService *service.MonitorService, maintenance *service.MaintenanceService, logger *zap.Logger) *MonitorScheduler {
 	return &MonitorScheduler{
 		cron:           cron.New(cron.WithSeconds()), // 支持秒级调度
 		tasks:          make(map[string]*MonitorTask),
 		monitorService: monitorService,
+		maintenance:    maintenance,
 		logger:         logger,
 	}
 }
@@ -186,6 +188,14 @@ func (s *MonitorScheduler) executeTask(monitorID string) {
 		return
 	}
 
+	// 维护期内跳过检测，不产生监控数据，可用率统计自然排除该时间段
+	if s.maintenance.IsMonitorInMaintenance(s.ctx, monitorID) {
+		s.logger.Debug("监控任务处于维护期，跳过执行",
+			zap.String("taskID", monitorID),
+			zap.String("taskName", monitor.Name))
+		return
+	}
+
 	//s.logger.Debug("执行监控任务",
 	//	zap.String("taskID", monitorID),
 	//	zap.String("taskName", monitor.Name),
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// RefreshAlertmanagerAlerts 定期向 api 模式的 Alertmanager 渠道重新推送已通知过的触发中告警，
+// 使 Alertmanager 中的告警与 Pika 保持一致，Pika 停止推送后告警在有效期结束时自动恢复
+func (s *AlertService) RefreshAlertmanagerAlerts(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	channelConfigs, err := s.propertyService.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	var channels []models.NotificationChannelConfig
+	for _, channel := range channelConfigs {
+		if channel.Enabled && channel.Type == "alertmanager" {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+
+	records, err := s.AlertRecordRepo.FindActiveByTypes(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	agents := make(map[string]*models.Agent)
+	items := make(map[string][]AlertGroupItem)
+	for i := range records {
+		record := &records[i]
+		// 只推送 Alertmanager 已经收到的告警，未发送过通知、被静默或被抑制的告警跳过
+		if record.NotifyCount == 0 || record.SilenceID != "" || record.InhibitedBy != 0 {
+			continue
+		}
+
+		agent, ok := agents[record.AgentID]
+		if !ok {
+			found, err := s.agentRepo.FindById(ctx, record.AgentID)
+			if err != nil {
+				continue
+			}
+			agent = &found
+			agents[record.AgentID] = agent
+		}
+
+		channelTypes := s.notifyChannelTypes(ctx, alertConfig, record, agent)
+		for _, channel := range channels {
+			if channelTypes == nil || slices.Contains(channelTypes, channel.Type) {
+				items[channel.Type] = append(items[channel.Type], AlertGroupItem{Agent: agent, Record: record})
+			}
+		}
+	}
+
+	for i := range channels {
+		channel := &channels[i]
+		if err := s.deliveryService.notifier.RefreshAlertmanagerAlerts(ctx, channel, items[channel.Type], alertConfig.MaskIP); err != nil {
+			s.logger.Warn("重新推送 Alertmanager 告警失败", zap.Error(err))
+		}
+	}
+	return nil
+}
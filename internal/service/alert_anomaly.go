@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/vmclient"
+	"go.uber.org/zap"
+)
+
+const (
+	// AlertTypeAnomaly 异常检测告警类型
+	AlertTypeAnomaly = "anomaly"
+
+	// anomalyBaselineTTL 基线缓存时间，基线变化缓慢，无需每个检查周期都重新计算
+	anomalyBaselineTTL = 10 * time.Minute
+
+	defaultAnomalyWindow = 24 // 默认 rolling 基线时长（小时）
+	defaultAnomalyDays   = 7  // 默认 same_hour 回溯天数
+	defaultAnomalySigma  = 3  // 默认标准差倍数
+
+	// minAnomalyDays same_hour 模式至少需要的历史天数
+	minAnomalyDays = 3
+	// anomalyMinStddevRatio 标准差下限占均值的比例，避免平稳指标的微小波动触发告警
+	anomalyMinStddevRatio = 0.05
+)
+
+// anomalyMetricQueries 异常检测支持的指标及其 PromQL
+var anomalyMetricQueries = map[string]string{
+	"cpu":         `pika_cpu_usage_percent{agent_id="%s"}`,
+	"memory":      `pika_memory_usage_percent{agent_id="%s"}`,
+	"load":        `pika_load1{agent_id="%s"}`,
+	"network_in":  `sum(pika_network_recv_bytes_rate{agent_id="%s"})`,
+	"network_out": `sum(pika_network_sent_bytes_rate{agent_id="%s"})`,
+	"connections": `pika_network_conn_total{agent_id="%s"}`,
+}
+
+// anomalyBaseline 指标的历史基线
+type anomalyBaseline struct {
+	Mean   float64
+	Stddev float64
+}
+
+// checkAnomalies 按异常检测规则检查探针指标是否偏离历史基线
+func (s *AlertService) checkAnomalies(ctx context.Context, config *models.AlertConfig, agent *models.Agent, configID string, rules *models.AlertRules, now int64) {
+	for i := range rules.AnomalyRules {
+		rule := &rules.AnomalyRules[i]
+		if !rule.Enabled {
+			continue
+		}
+		if err := s.checkAnomaly(ctx, config, agent, configID, rule, rules.ResolveDuration, now); err != nil {
+			s.logger.Error("检查异常检测告警失败",
+				zap.String("agentId", agent.ID),
+				zap.String("metric", rule.Metric),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// checkAnomaly 检查单条异常检测规则
+func (s *AlertService) checkAnomaly(ctx context.Context, config *models.AlertConfig, agent *models.Agent, configID string, rule *models.AnomalyRule, resolveDuration int, now int64) error {
+	query, ok := anomalyMetricQueries[rule.Metric]
+	if !ok {
+		return fmt.Errorf("不支持的异常检测指标: %s", rule.Metric)
+	}
+	expr := fmt.Sprintf(query, agent.ID)
+
+	baseline, ok, err := s.anomalyBaseline(ctx, agent.ID, expr, rule)
+	if err != nil || !ok {
+		// 历史数据不足时跳过
+		return err
+	}
+
+	current, ok, err := s.queryScalar(ctx, expr)
+	if err != nil || !ok {
+		return err
+	}
+
+	sigma := rule.Sigma
+	if sigma <= 0 {
+		sigma = defaultAnomalySigma
+	}
+	stddev := max(baseline.Stddev, math.Abs(baseline.Mean)*anomalyMinStddevRatio)
+	if stddev == 0 {
+		return nil
+	}
+
+	// 偏离程度（标准差倍数），按检测方向取值
+	deviation := (current - baseline.Mean) / stddev
+	switch rule.Direction {
+	case models.AnomalyDirectionDown:
+		deviation = -deviation
+	case models.AnomalyDirectionBoth:
+		deviation = math.Abs(deviation)
+	}
+
+	mode := rule.Mode
+	if mode == "" {
+		mode = models.AnomalyModeRolling
+	}
+
+	s.checkAlert(ctx, config, agent, configID, &metricCheck{
+		alertType: AlertTypeAnomaly,
+		target:    rule.Metric,
+		labels: map[string]string{
+			"metric":       rule.Metric,
+			"mode":         mode,
+			"current":      formatAnomalyValue(current),
+			"expected_min": formatAnomalyValue(baseline.Mean - sigma*stddev),
+			"expected_max": formatAnomalyValue(baseline.Mean + sigma*stddev),
+		},
+		value:           deviation,
+		threshold:       sigma,
+		duration:        rule.Duration,
+		resolveDuration: resolveDuration,
+	}, now)
+	return nil
+}
+
+// anomalyBaseline 获取指标基线，结果缓存一段时间
+func (s *AlertService) anomalyBaseline(ctx context.Context, agentID, expr string, rule *models.AnomalyRule) (anomalyBaseline, bool, error) {
+	key := fmt.Sprintf("%s:%s:%s:%d:%d", agentID, rule.Metric, rule.Mode, rule.Window, rule.Days)
+	if baseline, ok := s.anomalyBaselines.Get(key); ok {
+		return baseline, true, nil
+	}
+
+	var (
+		baseline anomalyBaseline
+		ok       bool
+		err      error
+	)
+	if rule.Mode == models.AnomalyModeSameHour {
+		baseline, ok, err = s.sameHourBaseline(ctx, expr, rule.Days)
+	} else {
+		baseline, ok, err = s.rollingBaseline(ctx, expr, rule.Window)
+	}
+	if err != nil || !ok {
+		return baseline, ok, err
+	}
+
+	s.anomalyBaselines.Set(key, baseline, anomalyBaselineTTL)
+	return baseline, true, nil
+}
+
+// rollingBaseline 最近 window 小时的均值和标准差
+func (s *AlertService) rollingBaseline(ctx context.Context, expr string, window int) (anomalyBaseline, bool, error) {
+	if window <= 0 {
+		window = defaultAnomalyWindow
+	}
+
+	mean, ok, err := s.queryScalar(ctx, fmt.Sprintf(`avg_over_time((%s)[%dh:1m])`, expr, window))
+	if err != nil || !ok {
+		return anomalyBaseline{}, false, err
+	}
+	stddev, ok, err := s.queryScalar(ctx, fmt.Sprintf(`stddev_over_time((%s)[%dh:1m])`, expr, window))
+	if err != nil || !ok {
+		return anomalyBaseline{}, false, err
+	}
+	return anomalyBaseline{Mean: mean, Stddev: stddev}, true, nil
+}
+
+// sameHourBaseline 过去 days 天同一时段（当前时间前后半小时）的均值和标准差，
+// 标准差由各天时段内的方差均值与各天均值的方差合成
+func (s *AlertService) sameHourBaseline(ctx context.Context, expr string, days int) (anomalyBaseline, bool, error) {
+	if days <= 0 {
+		days = defaultAnomalyDays
+	}
+
+	var means, variances []float64
+	for day := 1; day <= days; day++ {
+		// 窗口结束于 day 天前的半小时后
+		offset := day*24*60 - 30
+		mean, ok, err := s.queryScalar(ctx, fmt.Sprintf(`avg_over_time((%s)[1h:1m] offset %dm)`, expr, offset))
+		if err != nil {
+			return anomalyBaseline{}, false, err
+		}
+		if !ok {
+			continue
+		}
+		stddev, _, err := s.queryScalar(ctx, fmt.Sprintf(`stddev_over_time((%s)[1h:1m] offset %dm)`, expr, offset))
+		if err != nil {
+			return anomalyBaseline{}, false, err
+		}
+		means = append(means, mean)
+		variances = append(variances, stddev*stddev)
+	}
+	if len(means) < minAnomalyDays {
+		return anomalyBaseline{}, false, nil
+	}
+
+	mean := average(means)
+	var meanVariance float64
+	for _, m := range means {
+		meanVariance += (m - mean) * (m - mean)
+	}
+	meanVariance /= float64(len(means))
+
+	return anomalyBaseline{
+		Mean:   mean,
+		Stddev: math.Sqrt(average(variances) + meanVariance),
+	}, true, nil
+}
+
+// queryScalar 执行即时查询并返回第一个时间序列的值
+func (s *AlertService) queryScalar(ctx context.Context, query string) (float64, bool, error) {
+	result, err := s.vmClient.Query(ctx, query)
+	if err != nil {
+		return 0, false, err
+	}
+	points := vmclient.ConvertToInstantPoints(result)
+	if len(points) == 0 || math.IsNaN(points[0].Value) {
+		return 0, false, nil
+	}
+	return points[0].Value, true, nil
+}
+
+// buildAnomalyMessage 构建异常检测告警消息，包含基线期望范围
+func buildAnomalyMessage(state *models.AlertState) string {
+	labels := state.Labels.Data()
+	modeName := i18n.T("alert.anomaly.rolling")
+	if labels["mode"] == models.AnomalyModeSameHour {
+		modeName = i18n.T("alert.anomaly.same_hour")
+	}
+	return i18n.T("alert.msg.anomaly",
+		labels["metric"],
+		labels["current"],
+		modeName,
+		fmt.Sprintf("%.1f", state.Value),
+		labels["expected_min"],
+		labels["expected_max"],
+	)
+}
+
+// formatAnomalyValue 格式化异常检测的指标值
+func formatAnomalyValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	// AlertTypeDDNSFailure DDNS 连续更新失败告警类型
+	AlertTypeDDNSFailure = "ddns_failure"
+	// AlertTypeDDNSIPChange 公网 IP 变化通知类型
+	AlertTypeDDNSIPChange = "ddns_ip_change"
+
+	defaultDDNSFailureThreshold = 3 // 默认连续更新失败次数阈值
+)
+
+// DDNSIPChange 公网 IP 变化
+type DDNSIPChange struct {
+	RecordType string // 记录类型: A, AAAA
+	OldIP      string
+	NewIP      string
+}
+
+// ReportDDNSUpdate 记录一次 DDNS 更新结果，连续失败次数达到阈值时告警，更新成功后恢复
+func (s *AlertService) ReportDDNSUpdate(ctx context.Context, agentID string, ddnsConfig *models.DDNSConfig, updateErr error) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	resolved := s.resolveAgentRules(ctx, alertConfig, agent)
+	if !resolved.rules.DDNSFailureEnabled {
+		return nil
+	}
+
+	threshold := resolved.rules.DDNSFailureThreshold
+	if threshold <= 0 {
+		threshold = defaultDDNSFailureThreshold
+	}
+
+	stateKey := fmt.Sprintf("%s:%s:%s:%s", agent.ID, resolved.configID, AlertTypeDDNSFailure, ddnsConfig.ID)
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:      stateKey,
+			AgentID: agent.ID,
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	state.ConfigID = resolved.configID
+	state.AlertType = AlertTypeDDNSFailure
+	state.Threshold = float64(threshold)
+	state.LastCheckTime = now
+
+	var shouldFire, shouldResolve bool
+	if updateErr != nil {
+		if state.Value == 0 {
+			state.StartTime = now
+		}
+		state.Value++
+		state.Labels = datatypes.NewJSONType(map[string]string{
+			"ddns_config": ddnsConfig.Name,
+			"provider":    ddnsConfig.Provider,
+			"error":       updateErr.Error(),
+		})
+		// 维护期内只累计失败次数，不触发告警
+		if state.Value >= state.Threshold && !state.IsFiring && !resolved.inMaintenance {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else {
+		state.Value = 0
+		state.StartTime = 0
+		shouldResolve = state.IsFiring
+	}
+
+	if shouldFire || shouldResolve {
+		trackFlapping(&alertConfig.Flapping, state, now)
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		return err
+	}
+
+	if shouldFire {
+		s.fireDDNSFailureAlert(ctx, &agent, state, now)
+	}
+	if shouldResolve {
+		s.resolveAlert(ctx, alertConfig, &agent, state)
+	}
+	return nil
+}
+
+// fireDDNSFailureAlert 触发 DDNS 更新失败告警
+func (s *AlertService) fireDDNSFailureAlert(ctx context.Context, agent *models.Agent, state *models.AlertState, now int64) {
+	labels := state.Labels.Data()
+	s.logger.Info("触发 DDNS 更新失败告警",
+		zap.String("agentId", agent.ID),
+		zap.String("ddnsConfig", labels["ddns_config"]),
+		zap.Float64("failures", state.Value),
+	)
+
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		ConfigID:  state.ConfigID,
+		StateID:   state.ID,
+		AlertType: AlertTypeDDNSFailure,
+		Message: i18n.T("alert.msg.ddns_failure",
+			labels["ddns_config"],
+			labels["provider"],
+			fmt.Sprintf("%.0f", state.Value),
+			labels["error"],
+		),
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Labels:      state.Labels,
+		Level:       "critical",
+		Flapping:    state.IsFlapping,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		s.logger.Error("创建 DDNS 更新失败告警记录失败", zap.Error(err))
+		return
+	}
+
+	s.notifyStateChange(state, record, agent)
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+}
+
+// NotifyIPChange 发送公网 IP 变化通知，通知只作为记录保存，不需要恢复
+func (s *AlertService) NotifyIPChange(ctx context.Context, agentID string, changes []DDNSIPChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	resolved := s.resolveAgentRules(ctx, alertConfig, agent)
+	if !resolved.rules.DDNSIPChangeEnabled || resolved.inMaintenance {
+		return nil
+	}
+
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, i18n.T("alert.msg.ip_change_item", change.RecordType, change.OldIP, change.NewIP))
+	}
+
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:    agent.ID,
+		AgentName:  agent.Name,
+		ConfigID:   resolved.configID,
+		AlertType:  AlertTypeDDNSIPChange,
+		Message:    i18n.T("alert.msg.ip_change", strings.Join(parts, i18n.T("alert.msg.separator"))),
+		Level:      "info",
+		Status:     "resolved",
+		FiredAt:    now,
+		ResolvedAt: now,
+		CreatedAt:  now,
+	}
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		return err
+	}
+
+	// 记录直接保存为已恢复，通知使用 info 状态发送
+	notice := *record
+	notice.Status = "info"
+	go s.sendAlertNotification(&notice, &agent)
+	return nil
+}
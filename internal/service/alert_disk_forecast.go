@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/dushixiang/pika/internal/vmclient"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	// AlertTypeDiskForecast 磁盘写满预测告警类型
+	AlertTypeDiskForecast = "disk_forecast"
+
+	// diskForecastInterval 磁盘写满预测的检查间隔，预测查询开销较大，无需每个检查周期都执行
+	diskForecastInterval = 5 * time.Minute
+
+	defaultDiskForecastHorizon  = 72 // 默认预测范围（小时）
+	defaultDiskForecastLookback = 6  // 默认回归历史时长（小时）
+)
+
+// checkDiskForecastAlerts 按磁盘剩余空间的线性趋势预测写满时间，预计在预测范围内写满时告警
+func (s *AlertService) checkDiskForecastAlerts(ctx context.Context, agentRules map[string]*agentAlertRules, now int64) {
+	last := s.lastDiskForecastAt.Load()
+	if now-last < diskForecastInterval.Milliseconds() {
+		return
+	}
+	s.lastDiskForecastAt.Store(now)
+
+	disabled := false
+	for _, resolved := range agentRules {
+		if resolved.inMaintenance {
+			continue
+		}
+		if !resolved.rules.DiskForecastEnabled {
+			disabled = true
+			continue
+		}
+		if err := s.checkAgentDiskForecast(ctx, resolved, now); err != nil {
+			s.logger.Error("检查磁盘写满预测失败", zap.String("agentId", resolved.agent.ID), zap.Error(err))
+		}
+	}
+	if disabled {
+		s.resolveDisabledDiskForecasts(ctx, agentRules, now)
+	}
+}
+
+// resolveDisabledDiskForecasts 关闭磁盘写满预测（或探针的规则集不再启用）后，恢复仍在告警中的预测告警
+func (s *AlertService) resolveDisabledDiskForecasts(ctx context.Context, agentRules map[string]*agentAlertRules, now int64) {
+	states, err := s.AlertStateRepo.FindFiring(ctx)
+	if err != nil {
+		s.logger.Error("获取告警状态失败", zap.Error(err))
+		return
+	}
+	for i := range states {
+		state := &states[i]
+		if state.AlertType != AlertTypeDiskForecast {
+			continue
+		}
+		resolved, ok := agentRules[state.AgentID]
+		if !ok || resolved.inMaintenance || resolved.rules.DiskForecastEnabled {
+			continue
+		}
+		s.closeDiskForecastAlert(ctx, resolved, state, 0, now)
+	}
+}
+
+// checkAgentDiskForecast 检查单个探针各挂载点的磁盘写满预测
+func (s *AlertService) checkAgentDiskForecast(ctx context.Context, resolved *agentAlertRules, now int64) error {
+	agent := &resolved.agent
+	rules := &resolved.rules
+
+	horizon := rules.DiskForecastHorizon
+	if horizon <= 0 {
+		horizon = defaultDiskForecastHorizon
+	}
+	lookback := rules.DiskForecastLookback
+	if lookback <= 0 {
+		lookback = defaultDiskForecastLookback
+	}
+
+	// 剩余空间除以剩余空间下降速率（最小二乘回归斜率）即为预计写满的秒数，只保留正在下降的挂载点
+	selector := fmt.Sprintf(`pika_disk_free_bytes{agent_id="%s",mount_point!=""}`, agent.ID)
+	query := fmt.Sprintf(`(%s / -deriv(%s[%dh])) > 0`, selector, selector, lookback)
+	result, err := s.vmClient.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	// 挂载点 -> 预计写满小时数
+	forecasts := make(map[string]float64)
+	for _, point := range vmclient.ConvertToInstantPoints(result) {
+		mountPoint := point.Labels["mount_point"]
+		if mountPoint == "" || math.IsInf(point.Value, 0) || math.IsNaN(point.Value) {
+			continue
+		}
+		forecasts[mountPoint] = point.Value / 3600
+	}
+
+	for mountPoint, hoursLeft := range forecasts {
+		stateKey := fmt.Sprintf("%s:%s:%s:%s", agent.ID, resolved.configID, AlertTypeDiskForecast, mountPoint)
+		if hoursLeft <= float64(horizon) {
+			s.fireDiskForecastAlert(ctx, resolved, stateKey, mountPoint, hoursLeft, float64(horizon), now)
+		} else {
+			s.resolveDiskForecastAlert(ctx, resolved, stateKey, hoursLeft, now)
+		}
+	}
+
+	// 不再下降或已删除的挂载点
+	states, err := s.AlertStateRepo.FindByAgentID(ctx, agent.ID)
+	if err != nil {
+		return err
+	}
+	for _, state := range states {
+		if state.AlertType != AlertTypeDiskForecast || !state.IsFiring {
+			continue
+		}
+		if _, ok := forecasts[state.Labels.Data()["mount_point"]]; ok {
+			continue
+		}
+		s.resolveDiskForecastAlert(ctx, resolved, state.ID, 0, now)
+	}
+	return nil
+}
+
+// fireDiskForecastAlert 触发磁盘写满预测告警，已在告警中时只更新预测值
+func (s *AlertService) fireDiskForecastAlert(ctx context.Context, resolved *agentAlertRules, stateKey, mountPoint string, hoursLeft, horizon float64, now int64) {
+	agent := &resolved.agent
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{ID: stateKey}
+	}
+	state.AgentID = agent.ID
+	state.ConfigID = resolved.configID
+	state.AlertType = AlertTypeDiskForecast
+	state.Threshold = horizon
+	state.Value = hoursLeft
+	state.LastCheckTime = now
+	state.ResolveStartTime = 0
+	state.Labels = datatypes.NewJSONType(map[string]string{"mount_point": mountPoint})
+
+	shouldFire := !state.IsFiring
+	state.IsFiring = true
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+	if !shouldFire {
+		return
+	}
+
+	fullAt := now + int64(hoursLeft*float64(time.Hour.Milliseconds()))
+	s.logger.Info("触发磁盘写满预测告警",
+		zap.String("agentId", agent.ID),
+		zap.String("mountPoint", mountPoint),
+		zap.Float64("hoursLeft", hoursLeft),
+		zap.Float64("horizon", horizon),
+	)
+
+	level := "warning"
+	if hoursLeft <= 24 {
+		level = "critical"
+	}
+
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		ConfigID:  state.ConfigID,
+		StateID:   state.ID,
+		AlertType: AlertTypeDiskForecast,
+		Message: i18n.T("alert.msg.disk_forecast",
+			mountPoint,
+			utils.FormatDuration(fullAt-now),
+			utils.FormatTimestamp(fullAt),
+			fmt.Sprintf("%.0f", horizon),
+		),
+		Threshold:   horizon,
+		ActualValue: hoursLeft,
+		Labels:      state.Labels,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		s.logger.Error("创建磁盘写满预测告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+}
+
+// resolveDiskForecastAlert 恢复磁盘写满预测告警，hoursLeft 为 0 表示剩余空间已不再下降
+func (s *AlertService) resolveDiskForecastAlert(ctx context.Context, resolved *agentAlertRules, stateKey string, hoursLeft float64, now int64) {
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil || !state.IsFiring {
+		return
+	}
+	state.Value = hoursLeft
+	state.LastCheckTime = now
+
+	if !resolveConfirmed(state, now, resolved.rules.ResolveDuration) {
+		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+			s.logger.Error("保存告警状态失败", zap.Error(err))
+		}
+		return
+	}
+	s.closeDiskForecastAlert(ctx, resolved, state, hoursLeft, now)
+}
+
+// closeDiskForecastAlert 将磁盘写满预测告警标记为已恢复并发送恢复通知
+func (s *AlertService) closeDiskForecastAlert(ctx context.Context, resolved *agentAlertRules, state *models.AlertState, hoursLeft float64, now int64) {
+	agent := &resolved.agent
+	state.Value = hoursLeft
+	state.LastCheckTime = now
+
+	s.logger.Info("磁盘写满预测告警恢复",
+		zap.String("agentId", agent.ID),
+		zap.String("stateId", state.ID),
+		zap.Float64("hoursLeft", hoursLeft),
+	)
+
+	if state.LastRecordID > 0 {
+		record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+		if err != nil {
+			s.logger.Error("获取磁盘写满预测告警记录失败", zap.Error(err))
+		} else if record.IsActive() {
+			record.Status = "resolved"
+			record.ActualValue = hoursLeft
+			record.ResolvedAt = now
+			record.UpdatedAt = now
+			if err := s.AlertRecordRepo.UpdateAlertRecord(ctx, record); err != nil {
+				s.logger.Error("更新磁盘写满预测告警记录失败", zap.Error(err))
+			} else {
+				go s.sendAlertNotification(record, agent)
+			}
+		}
+	}
+
+	state.IsFiring = false
+	state.LastRecordID = 0
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/internal/vmclient"
+	"github.com/go-orz/orz"
+	"github.com/valyala/fasttemplate"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AlertExpressionRuleService PromQL 表达式告警规则服务
+type AlertExpressionRuleService struct {
+	logger *zap.Logger
+	*orz.Service
+	*repo.AlertExpressionRuleRepo
+	vmClient *vmclient.VMClient
+}
+
+func NewAlertExpressionRuleService(logger *zap.Logger, db *gorm.DB, vmClient *vmclient.VMClient) *AlertExpressionRuleService {
+	return &AlertExpressionRuleService{
+		logger:                  logger,
+		Service:                 orz.NewService(db),
+		AlertExpressionRuleRepo: repo.NewAlertExpressionRuleRepo(db),
+		vmClient:                vmClient,
+	}
+}
+
+// Evaluate 执行表达式即时查询，返回每个时间序列的当前值
+func (s *AlertExpressionRuleService) Evaluate(ctx context.Context, expr string) ([]vmclient.DataPoint, error) {
+	result, err := s.vmClient.Query(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data.ResultType != "" && result.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("表达式结果类型必须为 vector，当前为 %s", result.Data.ResultType)
+	}
+	return vmclient.ConvertToInstantPoints(result), nil
+}
+
+// expressionSeriesLabels 合并时间序列标签与规则附加标签
+func expressionSeriesLabels(rule *models.AlertExpressionRule, seriesLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(seriesLabels))
+	for k, v := range seriesLabels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels.Data() {
+		labels[k] = v
+	}
+	return labels
+}
+
+// labelsFingerprint 根据标签集合生成稳定的指纹，用作告警状态ID的一部分
+func labelsFingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(labels[k])
+		sb.WriteByte(',')
+	}
+	sum := sha1.Sum([]byte(sb.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// renderExpressionAnnotation 渲染注释模板，支持 {{value}} 和 {{labels.xxx}}
+func renderExpressionAnnotation(text string, value float64, labels map[string]string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return fasttemplate.ExecuteFuncString(text, "{{", "}}", func(w io.Writer, tag string) (int, error) {
+		tag = strings.TrimSpace(tag)
+		if tag == "value" {
+			return w.Write([]byte(fmt.Sprintf("%.2f", value)))
+		}
+		if name, ok := strings.CutPrefix(tag, "labels."); ok {
+			return w.Write([]byte(labels[name]))
+		}
+		return w.Write([]byte("{{" + tag + "}}"))
+	})
+}
+
+// buildExpressionMessage 构建表达式告警消息，优先使用 summary/description 注释
+func buildExpressionMessage(rule *models.AlertExpressionRule, value float64, labels map[string]string) string {
+	annotations := rule.Annotations.Data()
+	var parts []string
+	if summary := annotations["summary"]; summary != "" {
+		parts = append(parts, renderExpressionAnnotation(summary, value, labels))
+	}
+	if description := annotations["description"]; description != "" {
+		parts = append(parts, renderExpressionAnnotation(description, value, labels))
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, i18n.T("alert.msg.comma"))
+	}
+	return i18n.T("alert.msg.expression", rule.Name, strconv.Itoa(rule.For), rule.Expr, fmt.Sprintf("%.2f", value))
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestStaleExpressionStates(t *testing.T) {
+	states := []models.AlertState{
+		{ID: "agent-1:rule-1:expression:a", ConfigID: "rule-1", AlertType: models.AlertTypeExpression, IsFiring: true, LastRecordID: 1},
+		// 规则禁用时仍在告警中
+		{ID: "agent-1:rule-2:expression:a", ConfigID: "rule-2", AlertType: models.AlertTypeExpression, IsFiring: true, LastRecordID: 2},
+		// 规则已删除，尚未触发
+		{ID: "agent-1:rule-3:expression:a", ConfigID: "rule-3", AlertType: models.AlertTypeExpression},
+		{ID: "agent-1:config-1:cpu", ConfigID: "config-1", AlertType: "cpu", IsFiring: true, LastRecordID: 3},
+	}
+	enabled := []models.AlertExpressionRule{{ID: "rule-1", Enabled: true}}
+
+	stale := staleExpressionStates(states, enabled)
+	if len(stale) != 2 || stale[0].ConfigID != "rule-2" || stale[1].ConfigID != "rule-3" {
+		t.Fatalf("应返回已禁用和已删除规则的告警状态: %+v", stale)
+	}
+	if !stale[0].IsFiring || stale[0].LastRecordID != 2 {
+		t.Errorf("禁用规则的告警状态应保留告警记录以便恢复: %+v", stale[0])
+	}
+
+	if got := staleExpressionStates(states, nil); len(got) != 3 {
+		t.Errorf("没有启用的规则时所有表达式告警状态都应恢复，实际 %d 个", len(got))
+	}
+}
+
+func TestExpressionRuleNeedsResolve(t *testing.T) {
+	old := &models.AlertExpressionRule{Enabled: true, Expr: "up == 0", For: 60}
+
+	tests := []struct {
+		name    string
+		updated models.AlertExpressionRule
+		want    bool
+	}{
+		{name: "仅修改持续时间", updated: models.AlertExpressionRule{Enabled: true, Expr: "up == 0", For: 120}, want: false},
+		{name: "禁用规则", updated: models.AlertExpressionRule{Enabled: false, Expr: "up == 0", For: 60}, want: true},
+		{name: "修改表达式", updated: models.AlertExpressionRule{Enabled: true, Expr: "up < 1", For: 60}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpressionRuleNeedsResolve(old, &tt.updated); got != tt.want {
+				t.Errorf("ExpressionRuleNeedsResolve = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
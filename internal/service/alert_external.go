@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// AlertTypeExternal 外部系统推送的告警类型
+	AlertTypeExternal = "external"
+
+	// externalAlertConfigID 外部告警记录的规则来源
+	externalAlertConfigID = "alertmanager"
+)
+
+// ExternalAlertResult 外部告警接入结果
+type ExternalAlertResult struct {
+	Created  int `json:"created"`  // 新建的告警记录数
+	Resolved int `json:"resolved"` // 恢复的告警记录数
+	Skipped  int `json:"skipped"`  // 忽略的告警数（重复推送、Pika 自身生成的告警等）
+}
+
+// IngestAlertmanager 接收 Alertmanager Webhook 格式的告警，按指纹关联告警记录并发送通知
+func (s *AlertService) IngestAlertmanager(ctx context.Context, msg *AlertmanagerMessage) (*ExternalAlertResult, error) {
+	result := &ExternalAlertResult{}
+
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !alertConfig.Enabled {
+		result.Skipped = len(msg.Alerts)
+		return result, nil
+	}
+
+	s.externalMu.Lock()
+	defer s.externalMu.Unlock()
+
+	for _, alert := range msg.Alerts {
+		// Pika 推送给 Alertmanager 的告警再被转发回来时忽略，避免循环
+		if alert.Labels[alertmanagerGeneratorLabel] == "pika" {
+			result.Skipped++
+			continue
+		}
+
+		fingerprint := alert.Fingerprint
+		if fingerprint == "" {
+			fingerprint = alertmanagerFingerprint(alert.Labels)
+		}
+		stateID := externalAlertConfigID + ":" + fingerprint
+
+		var changed bool
+		if alert.Status == "resolved" {
+			changed, err = s.resolveExternalAlert(ctx, stateID, &alert)
+			if changed {
+				result.Resolved++
+			}
+		} else {
+			changed, err = s.fireExternalAlert(ctx, stateID, &alert)
+			if changed {
+				result.Created++
+			}
+		}
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+// fireExternalAlert 创建外部告警记录，同一指纹已有未恢复记录时不重复创建
+func (s *AlertService) fireExternalAlert(ctx context.Context, stateID string, alert *AlertmanagerAlert) (bool, error) {
+	_, err := s.AlertRecordRepo.GetActiveByStateID(ctx, stateID)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	agent := s.externalAlertAgent(ctx, alert.Labels)
+	now := time.Now().UnixMilli()
+	firedAt := now
+	if !alert.StartsAt.IsZero() {
+		firedAt = alert.StartsAt.UnixMilli()
+	}
+
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		ConfigID:  externalAlertConfigID,
+		StateID:   stateID,
+		AlertType: AlertTypeExternal,
+		Message:   externalAlertMessage(alert),
+		Labels:    datatypes.NewJSONType(alert.Labels),
+		Level:     externalAlertLevel(alert.Labels["severity"]),
+		Status:    "firing",
+		FiredAt:   firedAt,
+		CreatedAt: now,
+	}
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		return false, err
+	}
+
+	s.logger.Info("接收外部告警",
+		zap.String("stateId", stateID),
+		zap.String("agentId", agent.ID),
+		zap.String("message", record.Message),
+	)
+
+	go s.sendAlertNotification(record, agent)
+	return true, nil
+}
+
+// resolveExternalAlert 恢复外部告警记录，没有对应的未恢复记录时忽略
+func (s *AlertService) resolveExternalAlert(ctx context.Context, stateID string, alert *AlertmanagerAlert) (bool, error) {
+	record, err := s.AlertRecordRepo.GetActiveByStateID(ctx, stateID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UnixMilli()
+	record.Status = "resolved"
+	record.ResolvedAt = now
+	if !alert.EndsAt.IsZero() && alert.EndsAt.UnixMilli() <= now {
+		record.ResolvedAt = alert.EndsAt.UnixMilli()
+	}
+	record.UpdatedAt = now
+	if err := s.AlertRecordRepo.UpdateAlertRecord(ctx, record); err != nil {
+		return false, err
+	}
+
+	go s.sendAlertNotification(record, s.recordAgent(ctx, record))
+	return true, nil
+}
+
+// externalAlertAgent 根据告警标签查找关联的探针，没有 agent_id 标签或探针不存在时使用 instance 等标签构造展示用探针
+func (s *AlertService) externalAlertAgent(ctx context.Context, labels map[string]string) *models.Agent {
+	if agentID := labels["agent_id"]; agentID != "" {
+		if agent, err := s.agentRepo.FindById(ctx, agentID); err == nil {
+			return &agent
+		}
+	}
+
+	name := labels["instance"]
+	if name == "" {
+		name = labels["job"]
+	}
+	if name == "" {
+		name = "Alertmanager"
+	}
+	return &models.Agent{Name: name, Hostname: labels["instance"]}
+}
+
+// externalAlertMessage 外部告警消息，依次使用 summary、description、message 注解，均为空时使用告警名称
+func externalAlertMessage(alert *AlertmanagerAlert) string {
+	alertname := alert.Labels["alertname"]
+	for _, key := range []string{"summary", "description", "message"} {
+		if text := strings.TrimSpace(alert.Annotations[key]); text != "" {
+			if alertname != "" {
+				return "[" + alertname + "] " + text
+			}
+			return text
+		}
+	}
+	if alertname != "" {
+		return alertname
+	}
+	return AlertTypeExternal
+}
+
+// externalAlertLevel 将 severity 标签映射为告警级别，未设置时按 warning 处理
+func externalAlertLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "error", "page", "high", "fatal":
+		return "critical"
+	case "info", "informational", "low", "none":
+		return "info"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// AlertGroupItem 分组中的单条告警
+type AlertGroupItem struct {
+	Record *models.AlertRecord
+	Agent  *models.Agent
+}
+
+// AlertGroup 聚合发送的告警分组
+type AlertGroup struct {
+	Key    string            // 分组键
+	Status string            // 告警状态: firing, resolved
+	Labels map[string]string // 分组维度取值
+	Items  []AlertGroupItem  // 待发送的告警
+}
+
+// alertGroupState 分组的发送状态
+type alertGroupState struct {
+	pending     *AlertGroup                        // 等待发送的告警
+	channels    []models.NotificationChannelConfig // 通知渠道
+	timer       *time.Timer                        // 发送定时器
+	lastFlushAt time.Time                          // 上次发送时间
+}
+
+// alertGrouper 告警分组器，按分组维度合并一段时间内的告警通知
+type alertGrouper struct {
+	mu     sync.Mutex
+	groups map[string]*alertGroupState
+	flush  func(group *AlertGroup, channels []models.NotificationChannelConfig)
+}
+
+func newAlertGrouper(flush func(group *AlertGroup, channels []models.NotificationChannelConfig)) *alertGrouper {
+	return &alertGrouper{
+		groups: make(map[string]*alertGroupState),
+		flush:  flush,
+	}
+}
+
+// Add 将告警加入分组等待聚合发送，未启用分组时返回 false
+func (g *alertGrouper) Add(policy *models.GroupingPolicy, record *models.AlertRecord, agent *models.Agent, channels []models.NotificationChannelConfig) bool {
+	if !policy.Enabled {
+		return false
+	}
+
+	labels := alertGroupLabels(policy, record, agent)
+	key := alertGroupKey(record.Status, labels, channels)
+	interval := time.Duration(policy.GroupInterval) * time.Second
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.cleanup(now, interval)
+
+	state, ok := g.groups[key]
+	if !ok {
+		state = &alertGroupState{}
+		g.groups[key] = state
+	}
+	if state.pending == nil {
+		state.pending = &AlertGroup{
+			Key:    key,
+			Status: record.Status,
+			Labels: labels,
+		}
+		state.channels = channels
+	}
+	state.pending.Items = append(state.pending.Items, AlertGroupItem{Record: record, Agent: agent})
+
+	if state.timer == nil {
+		delay := time.Duration(policy.GroupWait) * time.Second
+		if !state.lastFlushAt.IsZero() {
+			// 分组在发送间隔内已发送过，等到间隔结束后再发送
+			delay = state.lastFlushAt.Add(interval).Sub(now)
+		}
+		state.timer = time.AfterFunc(delay, func() {
+			g.flushGroup(key)
+		})
+	}
+	return true
+}
+
+// flushGroup 发送分组中等待的告警
+func (g *alertGrouper) flushGroup(key string) {
+	g.mu.Lock()
+	state, ok := g.groups[key]
+	if !ok || state.pending == nil {
+		g.mu.Unlock()
+		return
+	}
+	group, channels := state.pending, state.channels
+	state.pending = nil
+	state.timer = nil
+	state.lastFlushAt = time.Now()
+	g.mu.Unlock()
+
+	g.flush(group, channels)
+}
+
+// cleanup 清理发送间隔已过且没有等待告警的分组，调用方需持有锁
+func (g *alertGrouper) cleanup(now time.Time, interval time.Duration) {
+	for key, state := range g.groups {
+		if state.pending == nil && now.Sub(state.lastFlushAt) >= interval {
+			delete(g.groups, key)
+		}
+	}
+}
+
+// alertGroupLabels 计算告警在各分组维度上的取值
+func alertGroupLabels(policy *models.GroupingPolicy, record *models.AlertRecord, agent *models.Agent) map[string]string {
+	groupBy := policy.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = []string{models.AlertGroupByAlertType}
+	}
+
+	labels := make(map[string]string, len(groupBy))
+	for _, by := range groupBy {
+		switch by {
+		case models.AlertGroupByAlertType:
+			labels[by] = record.AlertType
+		case models.AlertGroupByLevel:
+			labels[by] = record.Level
+		case models.AlertGroupByTag:
+			tags := slices.Sorted(slices.Values(agent.Tags))
+			labels[by] = strings.Join(tags, ",")
+		case models.AlertGroupByMonitor:
+			labels[by] = record.MonitorID
+		case models.AlertGroupByAgent:
+			labels[by] = record.AgentID
+		}
+	}
+	return labels
+}
+
+// alertGroupKey 生成分组键，不同状态和不同通知渠道的告警不会合并
+func alertGroupKey(status string, labels map[string]string, channels []models.NotificationChannelConfig) string {
+	keys := slices.Sorted(maps.Keys(labels))
+
+	var sb strings.Builder
+	sb.WriteString(status)
+	for _, k := range keys {
+		sb.WriteString("|")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(labels[k])
+	}
+
+	channelTypes := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channelTypes = append(channelTypes, channel.Type)
+	}
+	slices.Sort(channelTypes)
+	sb.WriteString("|channels=")
+	sb.WriteString(strings.Join(channelTypes, ","))
+	return sb.String()
+}
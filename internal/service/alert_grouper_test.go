@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// flushedGroup 测试中记录的一次分组发送
+type flushedGroup struct {
+	group *AlertGroup
+	at    time.Time
+}
+
+func TestAlertGrouperWaitAndInterval(t *testing.T) {
+	flushed := make(chan flushedGroup, 10)
+	grouper := newAlertGrouper(func(group *AlertGroup, channels []models.NotificationChannelConfig) {
+		flushed <- flushedGroup{group: group, at: time.Now()}
+	})
+	channels := []models.NotificationChannelConfig{{Type: "webhook", Enabled: true}}
+	policy := &models.GroupingPolicy{Enabled: true, GroupWait: 1, GroupInterval: 2}
+	agent := &models.Agent{ID: "agent-1"}
+	newRecord := func(id int64, alertType string) *models.AlertRecord {
+		return &models.AlertRecord{ID: id, AgentID: agent.ID, AlertType: alertType, Status: "firing"}
+	}
+	receive := func() flushedGroup {
+		t.Helper()
+		select {
+		case f := <-flushed:
+			return f
+		case <-time.After(5 * time.Second):
+			t.Fatalf("等待分组发送超时")
+			return flushedGroup{}
+		}
+	}
+
+	if grouper.Add(&models.GroupingPolicy{}, newRecord(1, "cpu"), agent, channels) {
+		t.Fatalf("未启用分组时不应加入分组")
+	}
+
+	// 等待时间内的同类告警合并发送
+	start := time.Now()
+	grouper.Add(policy, newRecord(1, "cpu"), agent, channels)
+	grouper.Add(policy, newRecord(2, "cpu"), agent, channels)
+	first := receive()
+	if len(first.group.Items) != 2 || first.group.Labels[models.AlertGroupByAlertType] != "cpu" {
+		t.Fatalf("首次发送应合并 2 条 cpu 告警: %+v", first.group)
+	}
+	if wait := first.at.Sub(start); wait < time.Second {
+		t.Errorf("首次发送应等待 group wait，实际 %s", wait)
+	}
+
+	// 发送间隔内的新告警等到间隔结束后发送
+	grouper.Add(policy, newRecord(3, "cpu"), agent, channels)
+	second := receive()
+	if len(second.group.Items) != 1 || second.group.Items[0].Record.ID != 3 {
+		t.Fatalf("第二次发送应只包含新告警: %+v", second.group)
+	}
+	if interval := second.at.Sub(first.at); interval < 2*time.Second-50*time.Millisecond {
+		t.Errorf("同一分组两次发送的间隔应不小于 group interval，实际 %s", interval)
+	}
+
+	select {
+	case f := <-flushed:
+		t.Errorf("不应有多余的发送: %+v", f.group)
+	default:
+	}
+}
+
+func TestAlertGroupKey(t *testing.T) {
+	policy := &models.GroupingPolicy{GroupBy: []string{models.AlertGroupByAlertType, models.AlertGroupByTag}}
+	record := &models.AlertRecord{AlertType: "cpu", Level: "warning", Status: "firing"}
+	labels := alertGroupLabels(policy, record, &models.Agent{Tags: []string{"web", "prod"}})
+	if labels[models.AlertGroupByAlertType] != "cpu" || labels[models.AlertGroupByTag] != "prod,web" {
+		t.Fatalf("分组维度取值不符合预期: %v", labels)
+	}
+	if _, ok := labels[models.AlertGroupByLevel]; ok {
+		t.Errorf("未配置的分组维度不应参与分组: %v", labels)
+	}
+
+	// 默认按告警类型分组
+	if got := alertGroupLabels(&models.GroupingPolicy{}, record, &models.Agent{}); len(got) != 1 || got[models.AlertGroupByAlertType] != "cpu" {
+		t.Errorf("默认分组维度不符合预期: %v", got)
+	}
+
+	webhook := []models.NotificationChannelConfig{{Type: "webhook"}}
+	both := []models.NotificationChannelConfig{{Type: "webhook"}, {Type: "email"}}
+	bothReversed := []models.NotificationChannelConfig{{Type: "email"}, {Type: "webhook"}}
+	if alertGroupKey("firing", labels, both) != alertGroupKey("firing", labels, bothReversed) {
+		t.Errorf("渠道顺序不应影响分组键")
+	}
+	if alertGroupKey("firing", labels, webhook) == alertGroupKey("firing", labels, both) {
+		t.Errorf("不同通知渠道的告警不应合并")
+	}
+	if alertGroupKey("firing", labels, webhook) == alertGroupKey("resolved", labels, webhook) {
+		t.Errorf("不同状态的告警不应合并")
+	}
+}
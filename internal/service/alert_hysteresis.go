@@ -0,0 +1,70 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// resolveThresholdOrDefault 获取恢复阈值，未配置时与告警阈值相同
+func resolveThresholdOrDefault(threshold, resolveThreshold float64) float64 {
+	if resolveThreshold <= 0 {
+		return threshold
+	}
+	return resolveThreshold
+}
+
+// resolveConfirmed 判断恢复条件是否已持续满足恢复持续时间，满足后重置计时
+func resolveConfirmed(state *models.AlertState, now int64, resolveDuration int) bool {
+	if state.ResolveStartTime == 0 {
+		state.ResolveStartTime = now
+	}
+	if (now-state.ResolveStartTime)/1000 < int64(resolveDuration) {
+		return false
+	}
+	state.ResolveStartTime = 0
+	return true
+}
+
+// trackFlapping 记录一次告警状态变化，并根据窗口内的变化次数更新抖动状态
+func trackFlapping(policy *models.FlappingPolicy, state *models.AlertState, now int64) {
+	if !policy.Enabled || policy.Threshold <= 0 || policy.Window <= 0 {
+		state.Transitions = nil
+		state.IsFlapping = false
+		state.FlapNotified = false
+		return
+	}
+
+	windowStart := now - int64(policy.Window)*1000
+	state.Transitions = slices.DeleteFunc(append(state.Transitions, now), func(t int64) bool {
+		return t < windowStart
+	})
+
+	state.IsFlapping = len(state.Transitions) >= policy.Threshold
+	if !state.IsFlapping {
+		state.FlapNotified = false
+	}
+}
+
+// flappingEnded 按当前时间重新统计窗口内的状态变化次数，抖动结束时清除抖动状态并返回 true
+//
+// 抖动只在告警触发或恢复时重新统计，告警停止变化后需要定时调用以结束抖动。
+func flappingEnded(policy *models.FlappingPolicy, state *models.AlertState, now int64) bool {
+	if !state.IsFlapping {
+		return false
+	}
+	if policy.Enabled && policy.Threshold > 0 && policy.Window > 0 {
+		windowStart := now - int64(policy.Window)*1000
+		state.Transitions = slices.DeleteFunc(state.Transitions, func(t int64) bool {
+			return t < windowStart
+		})
+		if len(state.Transitions) >= policy.Threshold {
+			return false
+		}
+	} else {
+		state.Transitions = nil
+	}
+	state.IsFlapping = false
+	state.FlapNotified = false
+	return true
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestResolveThresholdOrDefault(t *testing.T) {
+	if got := resolveThresholdOrDefault(80, 0); got != 80 {
+		t.Errorf("未配置恢复阈值时应使用告警阈值，实际 %v", got)
+	}
+	if got := resolveThresholdOrDefault(80, 70); got != 70 {
+		t.Errorf("应使用配置的恢复阈值，实际 %v", got)
+	}
+}
+
+func TestResolveConfirmed(t *testing.T) {
+	// 未配置恢复持续时间时立即恢复
+	state := &models.AlertState{}
+	if !resolveConfirmed(state, 1000, 0) || state.ResolveStartTime != 0 {
+		t.Fatalf("未配置恢复持续时间时应立即恢复: %+v", state)
+	}
+
+	state = &models.AlertState{}
+	if resolveConfirmed(state, 10_000, 60) {
+		t.Fatalf("首次满足恢复条件时不应恢复")
+	}
+	if state.ResolveStartTime != 10_000 {
+		t.Fatalf("应记录开始满足恢复条件的时间: %d", state.ResolveStartTime)
+	}
+	if resolveConfirmed(state, 69_999, 60) {
+		t.Errorf("恢复条件持续时间不足时不应恢复")
+	}
+	if !resolveConfirmed(state, 70_000, 60) {
+		t.Errorf("恢复条件持续满足恢复持续时间后应恢复")
+	}
+	if state.ResolveStartTime != 0 {
+		t.Errorf("恢复后应重置计时: %d", state.ResolveStartTime)
+	}
+}
+
+func TestTrackFlapping(t *testing.T) {
+	policy := &models.FlappingPolicy{Enabled: true, Threshold: 3, Window: 60}
+	state := &models.AlertState{}
+
+	trackFlapping(policy, state, 0)
+	trackFlapping(policy, state, 10_000)
+	if state.IsFlapping {
+		t.Fatalf("变化次数未达到阈值时不应抖动")
+	}
+	trackFlapping(policy, state, 20_000)
+	if !state.IsFlapping {
+		t.Fatalf("窗口内变化次数达到阈值时应抖动")
+	}
+
+	// 窗口外的变化不计入
+	state.FlapNotified = true
+	trackFlapping(policy, state, 65_000)
+	if !state.IsFlapping || len(state.Transitions) != 3 {
+		t.Fatalf("窗口内仍有 3 次变化时应保持抖动: %+v", state)
+	}
+	if !state.FlapNotified {
+		t.Errorf("抖动未结束时应保留已通知标记")
+	}
+	trackFlapping(policy, state, 140_000)
+	if state.IsFlapping || state.FlapNotified || len(state.Transitions) != 1 {
+		t.Errorf("窗口内变化次数低于阈值时应结束抖动: %+v", state)
+	}
+
+	// 未启用时清除抖动状态
+	state = &models.AlertState{Transitions: []int64{1, 2, 3}, IsFlapping: true, FlapNotified: true}
+	trackFlapping(&models.FlappingPolicy{Threshold: 3, Window: 60}, state, 4)
+	if state.IsFlapping || state.FlapNotified || state.Transitions != nil {
+		t.Errorf("未启用抖动检测时应清除抖动状态: %+v", state)
+	}
+}
+
+func TestFlappingEnded(t *testing.T) {
+	policy := &models.FlappingPolicy{Enabled: true, Threshold: 3, Window: 60}
+	newState := func() *models.AlertState {
+		return &models.AlertState{Transitions: []int64{0, 10_000, 20_000}, IsFlapping: true, FlapNotified: true}
+	}
+
+	if flappingEnded(policy, &models.AlertState{}, 100_000) {
+		t.Errorf("未抖动的状态不应结束抖动")
+	}
+
+	state := newState()
+	if flappingEnded(policy, state, 60_000) || !state.IsFlapping {
+		t.Errorf("窗口内变化次数仍达到阈值时不应结束抖动: %+v", state)
+	}
+
+	// 第一次变化移出窗口后抖动结束
+	state = newState()
+	if !flappingEnded(policy, state, 60_001) {
+		t.Fatalf("窗口内变化次数低于阈值时应结束抖动")
+	}
+	if state.IsFlapping || state.FlapNotified || len(state.Transitions) != 2 {
+		t.Errorf("抖动结束后应清除抖动状态: %+v", state)
+	}
+
+	// 关闭抖动检测后立即结束
+	state = newState()
+	if !flappingEnded(&models.FlappingPolicy{}, state, 1) || state.IsFlapping || state.Transitions != nil {
+		t.Errorf("关闭抖动检测后应结束抖动: %+v", state)
+	}
+}
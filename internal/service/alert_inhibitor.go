@@ -0,0 +1,71 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// DefaultInhibitRule 内置抑制规则：探针离线时抑制该探针的其他告警
+var DefaultInhibitRule = models.InhibitRule{
+	Name:        "探针离线抑制",
+	Enabled:     true,
+	SourceTypes: []string{"agent_offline"},
+	Equal:       []string{models.InhibitEqualAgent},
+}
+
+// InhibitRules 获取生效的抑制规则，内置规则未禁用时排在最前
+func InhibitRules(policy *models.InhibitionPolicy) []models.InhibitRule {
+	var rules []models.InhibitRule
+	if !policy.DisableDefault {
+		rules = append(rules, DefaultInhibitRule)
+	}
+	for _, rule := range policy.Rules {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// MatchInhibitRule 判断源告警是否按规则抑制目标告警
+func MatchInhibitRule(rule *models.InhibitRule, source, target *models.AlertRecord) bool {
+	if source.ID == target.ID {
+		return false
+	}
+	if !matchAlertTypeLevel(rule.SourceTypes, rule.SourceLevels, source) {
+		return false
+	}
+	if !matchAlertTypeLevel(rule.TargetTypes, rule.TargetLevels, target) {
+		return false
+	}
+	// 源告警本身也满足目标条件时不互相抑制，避免同类告警彼此屏蔽
+	if matchAlertTypeLevel(rule.TargetTypes, rule.TargetLevels, source) && matchAlertTypeLevel(rule.SourceTypes, rule.SourceLevels, target) {
+		return false
+	}
+
+	for _, equal := range rule.Equal {
+		switch equal {
+		case models.InhibitEqualAgent:
+			if source.AgentID != target.AgentID {
+				return false
+			}
+		case models.InhibitEqualMonitor:
+			if source.MonitorID != target.MonitorID {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchAlertTypeLevel 判断告警类型和级别是否匹配，条件为空时匹配所有
+func matchAlertTypeLevel(types, levels []string, record *models.AlertRecord) bool {
+	if len(types) > 0 && !slices.Contains(types, record.AlertType) {
+		return false
+	}
+	if len(levels) > 0 && !slices.Contains(levels, record.Level) {
+		return false
+	}
+	return true
+}
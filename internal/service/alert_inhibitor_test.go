@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMatchInhibitRule(t *testing.T) {
+	offline := &models.AlertRecord{ID: 1, AgentID: "agent-1", AlertType: "agent_offline", Level: "critical"}
+	cpu := &models.AlertRecord{ID: 2, AgentID: "agent-1", AlertType: "cpu", Level: "warning"}
+	otherAgentCPU := &models.AlertRecord{ID: 3, AgentID: "agent-2", AlertType: "cpu", Level: "warning"}
+	otherOffline := &models.AlertRecord{ID: 4, AgentID: "agent-1", AlertType: "agent_offline", Level: "critical"}
+	serviceDown := &models.AlertRecord{ID: 5, AgentID: "agent-1", AlertType: "service", Level: "critical", MonitorID: "monitor-1"}
+	certExpiring := &models.AlertRecord{ID: 6, AgentID: "agent-2", AlertType: "cert", Level: "warning", MonitorID: "monitor-1"}
+	otherCert := &models.AlertRecord{ID: 7, AgentID: "agent-2", AlertType: "cert", Level: "warning", MonitorID: "monitor-2"}
+	criticalCPU := &models.AlertRecord{ID: 8, AgentID: "agent-1", AlertType: "cpu", Level: "critical"}
+
+	byMonitor := models.InhibitRule{Enabled: true, SourceTypes: []string{"service"}, TargetTypes: []string{"cert"}, Equal: []string{models.InhibitEqualMonitor}}
+	byLevel := models.InhibitRule{Enabled: true, SourceLevels: []string{"critical"}, TargetLevels: []string{"warning"}, Equal: []string{models.InhibitEqualAgent}}
+	noEqual := models.InhibitRule{Enabled: true, SourceTypes: []string{"agent_offline"}, TargetTypes: []string{"cpu"}}
+
+	tests := []struct {
+		name   string
+		rule   models.InhibitRule
+		source *models.AlertRecord
+		target *models.AlertRecord
+		want   bool
+	}{
+		{name: "内置规则: 探针离线抑制同一探针的其他告警", rule: DefaultInhibitRule, source: offline, target: cpu, want: true},
+		{name: "内置规则: 不抑制其他探针的告警", rule: DefaultInhibitRule, source: offline, target: otherAgentCPU, want: false},
+		{name: "内置规则: 离线告警之间不互相抑制", rule: DefaultInhibitRule, source: offline, target: otherOffline, want: false},
+		{name: "内置规则: 普通告警不能作为源告警", rule: DefaultInhibitRule, source: cpu, target: offline, want: false},
+		{name: "告警不抑制自身", rule: DefaultInhibitRule, source: offline, target: offline, want: false},
+		{name: "同一监控项", rule: byMonitor, source: serviceDown, target: certExpiring, want: true},
+		{name: "不同监控项", rule: byMonitor, source: serviceDown, target: otherCert, want: false},
+		{name: "按级别抑制同一探针", rule: byLevel, source: criticalCPU, target: cpu, want: true},
+		{name: "按级别时其他探针不抑制", rule: byLevel, source: criticalCPU, target: otherAgentCPU, want: false},
+		{name: "目标级别不匹配", rule: byLevel, source: serviceDown, target: criticalCPU, want: false},
+		{name: "未设置相等维度时跨探针抑制", rule: noEqual, source: offline, target: otherAgentCPU, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchInhibitRule(&tt.rule, tt.source, tt.target); got != tt.want {
+				t.Errorf("MatchInhibitRule = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInhibitRules(t *testing.T) {
+	custom := models.InhibitRule{Name: "custom", Enabled: true}
+	disabled := models.InhibitRule{Name: "disabled", Enabled: false}
+
+	rules := InhibitRules(&models.InhibitionPolicy{Rules: []models.InhibitRule{custom, disabled}})
+	if len(rules) != 2 || rules[0].Name != DefaultInhibitRule.Name || rules[1].Name != "custom" {
+		t.Errorf("内置规则应排在最前且跳过禁用的规则: %+v", rules)
+	}
+
+	rules = InhibitRules(&models.InhibitionPolicy{DisableDefault: true, Rules: []models.InhibitRule{custom}})
+	if len(rules) != 1 || rules[0].Name != "custom" {
+		t.Errorf("禁用内置规则后只保留自定义规则: %+v", rules)
+	}
+}
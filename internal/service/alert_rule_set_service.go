@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/cache"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const enabledRuleSetsCacheKey = "enabled"
+
+// AlertRuleSetService 告警规则集服务
+type AlertRuleSetService struct {
+	logger *zap.Logger
+	*orz.Service
+	*repo.AlertRuleSetRepo
+	alertRecordRepo *repo.AlertRecordRepo
+	alertStateRepo  *repo.AlertStateRepo
+	// 已启用规则集缓存，告警检查每个周期都会读取
+	cache cache.Cache[string, []models.AlertRuleSet]
+}
+
+func NewAlertRuleSetService(logger *zap.Logger, db *gorm.DB) *AlertRuleSetService {
+	return &AlertRuleSetService{
+		logger:           logger,
+		Service:          orz.NewService(db),
+		AlertRuleSetRepo: repo.NewAlertRuleSetRepo(db),
+		alertRecordRepo:  repo.NewAlertRecordRepo(db),
+		alertStateRepo:   repo.NewAlertStateRepo(db),
+		cache:            cache.New[string, []models.AlertRuleSet](time.Minute),
+	}
+}
+
+// Create 创建告警规则集
+func (s *AlertRuleSetService) Create(ctx context.Context, ruleSet *models.AlertRuleSet) error {
+	if err := s.AlertRuleSetRepo.Create(ctx, ruleSet); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledRuleSetsCacheKey)
+	return nil
+}
+
+// Update 更新告警规则集
+func (s *AlertRuleSetService) Update(ctx context.Context, ruleSet *models.AlertRuleSet) error {
+	if err := s.AlertRuleSetRepo.Save(ctx, ruleSet); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledRuleSetsCacheKey)
+	return nil
+}
+
+// UpdateEnabled 启用/禁用告警规则集
+func (s *AlertRuleSetService) UpdateEnabled(ctx context.Context, id string, enabled bool) error {
+	if err := s.AlertRuleSetRepo.UpdateEnabled(ctx, id, enabled); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledRuleSetsCacheKey)
+	return nil
+}
+
+// Delete 删除告警规则集，同时恢复由该规则集触发的告警并清理告警状态
+func (s *AlertRuleSetService) Delete(ctx context.Context, id string) error {
+	err := s.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.AlertRuleSetRepo.DeleteById(ctx, id); err != nil {
+			return err
+		}
+
+		states, err := s.alertStateRepo.FindByConfigID(ctx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		for _, state := range states {
+			if !state.IsFiring || state.LastRecordID == 0 {
+				continue
+			}
+			record, err := s.alertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+			if err != nil || !record.IsActive() {
+				continue
+			}
+			record.Status = "resolved"
+			record.ResolvedAt = now
+			record.UpdatedAt = now
+			if err := s.alertRecordRepo.UpdateAlertRecord(ctx, record); err != nil {
+				return err
+			}
+		}
+
+		return s.alertStateRepo.DeleteAlertStatesByConfigID(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+	s.cache.Delete(enabledRuleSetsCacheKey)
+	return nil
+}
+
+// FindEnabled 获取所有已启用的告警规则集（带缓存）
+func (s *AlertRuleSetService) FindEnabled(ctx context.Context) ([]models.AlertRuleSet, error) {
+	if ruleSets, ok := s.cache.Get(enabledRuleSetsCacheKey); ok {
+		return ruleSets, nil
+	}
+
+	ruleSets, err := s.AlertRuleSetRepo.FindAllEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(enabledRuleSetsCacheKey, ruleSets, time.Hour)
+	return ruleSets, nil
+}
+
+// ResolveRules 计算探针生效的告警规则，返回规则来源ID与规则
+//
+// 匹配优先级：按探针ID匹配 > 按标签匹配 > 未限定范围的规则集 > 全局规则；
+// 同一类别内 Priority 大的优先，相同时先创建的优先。
+func (s *AlertRuleSetService) ResolveRules(ctx context.Context, agent *models.Agent, globalRules models.AlertRules) (string, models.AlertRules, error) {
+	ruleSets, err := s.FindEnabled(ctx)
+	if err != nil {
+		return models.AlertConfigIDGlobal, globalRules, err
+	}
+
+	if ruleSet := MatchAlertRuleSet(ruleSets, agent); ruleSet != nil {
+		return ruleSet.ID, ruleSet.Rules.Data(), nil
+	}
+	return models.AlertConfigIDGlobal, globalRules, nil
+}
+
+// MatchAlertRuleSet 从规则集中选出对探针生效的一个，ruleSets 需按优先级降序排列
+func MatchAlertRuleSet(ruleSets []models.AlertRuleSet, agent *models.Agent) *models.AlertRuleSet {
+	var byTag, unscoped *models.AlertRuleSet
+	for i := range ruleSets {
+		ruleSet := &ruleSets[i]
+		if !ruleSet.Enabled {
+			continue
+		}
+		if slices.Contains(ruleSet.AgentIds, agent.ID) {
+			return ruleSet
+		}
+		if byTag == nil && slices.ContainsFunc(ruleSet.Tags, func(tag string) bool {
+			return slices.Contains(agent.Tags, tag)
+		}) {
+			byTag = ruleSet
+		}
+		if unscoped == nil && len(ruleSet.AgentIds) == 0 && len(ruleSet.Tags) == 0 {
+			unscoped = ruleSet
+		}
+	}
+	if byTag != nil {
+		return byTag
+	}
+	return unscoped
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMatchAlertRuleSet(t *testing.T) {
+	agent := &models.Agent{ID: "agent-1", Tags: []string{"prod", "web"}}
+
+	// 按优先级降序排列
+	byAgentLow := models.AlertRuleSet{ID: "agent-low", Enabled: true, Priority: 1, AgentIds: []string{"agent-1"}}
+	byTagHigh := models.AlertRuleSet{ID: "tag-high", Enabled: true, Priority: 10, Tags: []string{"web"}}
+	byTagLow := models.AlertRuleSet{ID: "tag-low", Enabled: true, Priority: 5, Tags: []string{"prod"}}
+	unscopedHigh := models.AlertRuleSet{ID: "unscoped-high", Enabled: true, Priority: 20}
+	unscopedLow := models.AlertRuleSet{ID: "unscoped-low", Enabled: true, Priority: 0}
+	otherAgent := models.AlertRuleSet{ID: "other-agent", Enabled: true, Priority: 30, AgentIds: []string{"agent-2"}}
+	otherTag := models.AlertRuleSet{ID: "other-tag", Enabled: true, Priority: 30, Tags: []string{"db"}}
+	disabledAgent := models.AlertRuleSet{ID: "disabled-agent", Enabled: false, Priority: 30, AgentIds: []string{"agent-1"}}
+
+	tests := []struct {
+		name     string
+		ruleSets []models.AlertRuleSet
+		want     string
+	}{
+		{name: "无规则集", want: ""},
+		{name: "指定探针优先于高优先级的标签和通用规则集", ruleSets: []models.AlertRuleSet{unscopedHigh, byTagHigh, byAgentLow}, want: "agent-low"},
+		{name: "标签优先于高优先级的通用规则集", ruleSets: []models.AlertRuleSet{unscopedHigh, byTagLow, unscopedLow}, want: "tag-low"},
+		{name: "多个标签规则集取优先级最高的", ruleSets: []models.AlertRuleSet{byTagHigh, byTagLow}, want: "tag-high"},
+		{name: "多个通用规则集取优先级最高的", ruleSets: []models.AlertRuleSet{unscopedHigh, unscopedLow}, want: "unscoped-high"},
+		{name: "其他探针和标签的规则集不匹配", ruleSets: []models.AlertRuleSet{otherAgent, otherTag, unscopedLow}, want: "unscoped-low"},
+		{name: "只有其他探针的规则集时使用全局规则", ruleSets: []models.AlertRuleSet{otherAgent, otherTag}, want: ""},
+		{name: "禁用的规则集不生效", ruleSets: []models.AlertRuleSet{disabledAgent, byTagLow}, want: "tag-low"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if ruleSet := MatchAlertRuleSet(tt.ruleSets, agent); ruleSet != nil {
+				got = ruleSet.ID
+			}
+			if got != tt.want {
+				t.Errorf("命中的规则集 = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,23 @@ package service
 import (
 	"context"
 	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/metric"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/internal/vmclient"
+	"github.com/go-orz/cache"
 	"github.com/go-orz/orz"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -18,23 +28,118 @@ type AlertService struct {
 	Service         *orz.Service
 	AlertRecordRepo *repo.AlertRecordRepo
 	AlertStateRepo  *repo.AlertStateRepo
+	alertNoteRepo   *repo.AlertNoteRepo
 	agentRepo       *repo.AgentRepo
 	monitorService  *MonitorService
 	propertyService *PropertyService
-	notifier        *Notifier
+	ruleSetService  *AlertRuleSetService
+	exprRuleService *AlertExpressionRuleService
+	silenceService  *AlertSilenceService
+	maintenance     *MaintenanceService
+	deliveryService *NotificationDeliveryService
+	grouper         *alertGrouper
+	vmClient        *vmclient.VMClient
 	logger          *zap.Logger
+
+	lastDiskForecastAt atomic.Int64                         // 上次磁盘写满预测检查时间
+	anomalyBaselines   cache.Cache[string, anomalyBaseline] // 异常检测基线缓存
+	tamperMu           sync.Mutex
+	tamperLimits       cache.Cache[string, int64] // 防篡改告警限流，值为限流间隔内的告警记录ID
+	externalMu         sync.Mutex                 // 外部告警接入锁，避免并发推送重复创建记录
 }
 
-func NewAlertService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, monitorService *MonitorService, notifier *Notifier) *AlertService {
-	return &AlertService{
+func NewAlertService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, monitorService *MonitorService, ruleSetService *AlertRuleSetService, exprRuleService *AlertExpressionRuleService, silenceService *AlertSilenceService, maintenance *MaintenanceService, deliveryService *NotificationDeliveryService, vmClient *vmclient.VMClient) *AlertService {
+	s := &AlertService{
 		Service:         orz.NewService(db),
 		AlertRecordRepo: repo.NewAlertRecordRepo(db),
 		AlertStateRepo:  repo.NewAlertStateRepo(db),
+		alertNoteRepo:   repo.NewAlertNoteRepo(db),
 		agentRepo:       repo.NewAgentRepo(db),
 		monitorService:  monitorService,
 		propertyService: propertyService,
-		notifier:        notifier,
+		ruleSetService:  ruleSetService,
+		exprRuleService: exprRuleService,
+		silenceService:  silenceService,
+		maintenance:     maintenance,
+		deliveryService: deliveryService,
+		vmClient:        vmClient,
 		logger:          logger,
+
+		anomalyBaselines: cache.New[string, anomalyBaseline](time.Minute),
+		tamperLimits:     cache.New[string, int64](time.Minute),
+	}
+	s.grouper = newAlertGrouper(s.flushAlertGroup)
+	return s
+}
+
+// agentAlertRules 探针当前生效的告警规则
+type agentAlertRules struct {
+	agent         models.Agent
+	configID      string
+	rules         models.AlertRules
+	inMaintenance bool // 是否处于维护期，维护期内不检查告警
+}
+
+// resolveAgentRules 计算探针生效的告警规则，规则集读取失败时回退到全局规则
+func (s *AlertService) resolveAgentRules(ctx context.Context, config *models.AlertConfig, agent models.Agent) *agentAlertRules {
+	configID, rules, err := s.ruleSetService.ResolveRules(ctx, &agent, config.Rules)
+	if err != nil {
+		s.logger.Error("获取告警规则集失败，使用全局规则", zap.String("agentId", agent.ID), zap.Error(err))
+	}
+	return &agentAlertRules{
+		agent:         agent,
+		configID:      configID,
+		rules:         rules,
+		inMaintenance: s.maintenance.IsAgentInMaintenance(ctx, &agent),
+	}
+}
+
+// resolveStaleStates 探针切换到其他规则集后，恢复旧规则集遗留的告警并删除其状态
+func (s *AlertService) resolveStaleStates(ctx context.Context, agent *models.Agent, configID string) {
+	states, err := s.AlertStateRepo.FindByAgentID(ctx, agent.ID)
+	if err != nil {
+		s.logger.Error("获取探针告警状态失败", zap.String("agentId", agent.ID), zap.Error(err))
+		return
+	}
+
+	for i := range states {
+		state := &states[i]
+		if state.AlertType == models.AlertTypeExpression {
+			// 表达式告警状态由 CheckExpressionRules 维护
+			continue
+		}
+		stateConfigID := state.ConfigID
+		if stateConfigID == "" {
+			// 兼容规则集功能之前的状态
+			stateConfigID = models.AlertConfigIDGlobal
+		}
+		if stateConfigID == configID {
+			continue
+		}
+
+		if state.IsFiring && state.LastRecordID > 0 {
+			record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+			if err == nil && record.IsActive() {
+				now := time.Now().UnixMilli()
+				record.Status = "resolved"
+				record.ResolvedAt = now
+				record.UpdatedAt = now
+				if err := s.AlertRecordRepo.UpdateAlertRecord(ctx, record); err != nil {
+					s.logger.Error("更新告警记录失败", zap.Error(err))
+					continue
+				}
+			}
+		}
+
+		s.logger.Info("告警规则来源变更，清理旧告警状态",
+			zap.String("agentId", agent.ID),
+			zap.String("stateId", state.ID),
+			zap.String("from", stateConfigID),
+			zap.String("to", configID),
+		)
+		if err := s.AlertStateRepo.DeleteAlertState(ctx, state.ID); err != nil {
+			s.logger.Error("删除告警状态失败", zap.Error(err))
+		}
 	}
 }
 
@@ -53,12 +158,117 @@ func (s *AlertService) Clear(ctx context.Context) error {
 			return err
 		}
 
+		// 清空告警备注
+		if err := s.alertNoteRepo.Clear(ctx); err != nil {
+			s.logger.Error("清空告警备注失败", zap.Error(err))
+			return err
+		}
+
+		// 清空通知投递记录
+		if err := s.deliveryService.DeliveryRepo.Clear(ctx); err != nil {
+			s.logger.Error("清空通知投递记录失败", zap.Error(err))
+			return err
+		}
+
 		return nil
 	})
 }
 
+// recordAgent 获取告警记录关联的探针，探针不存在时使用记录中的信息占位
+func (s *AlertService) recordAgent(ctx context.Context, record *models.AlertRecord) *models.Agent {
+	if record.AgentID != "" {
+		agent, err := s.agentRepo.FindById(ctx, record.AgentID)
+		if err == nil {
+			return &agent
+		}
+	}
+	return &models.Agent{ID: record.AgentID, Name: record.AgentName}
+}
+
+// Acknowledge 确认告警，确认后发送一次确认通知
+func (s *AlertService) Acknowledge(ctx context.Context, id int64, username, comment string) (*models.AlertRecord, error) {
+	if _, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// 按条件更新确认字段，检查与更新之间告警被恢复时确认失败，不会把已恢复的记录改回未恢复
+	now := time.Now().UnixMilli()
+	err := s.Service.Transaction(ctx, func(ctx context.Context) error {
+		acknowledged, err := s.AlertRecordRepo.Acknowledge(ctx, id, username, now)
+		if err != nil {
+			return err
+		}
+		if !acknowledged {
+			return orz.NewError(400, "只能确认告警中的记录")
+		}
+		if comment == "" {
+			return nil
+		}
+		return s.alertNoteRepo.Create(ctx, &models.AlertNote{
+			RecordID:  id,
+			Author:    username,
+			Content:   comment,
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("告警已确认",
+		zap.Int64("recordId", record.ID),
+		zap.String("agentId", record.AgentID),
+		zap.String("alertType", record.AlertType),
+		zap.String("acknowledgedBy", username),
+	)
+
+	// 确认后告警可能已被恢复，此时由恢复通知告知最新状态
+	if record.Status == "acknowledged" {
+		go s.sendAlertNotification(record, s.recordAgent(ctx, record))
+	}
+	return record, nil
+}
+
+// Assign 指派告警处理人
+func (s *AlertService) Assign(ctx context.Context, id int64, assignee string) error {
+	if _, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, id); err != nil {
+		return err
+	}
+	return s.AlertRecordRepo.UpdateColumnsById(ctx, id, map[string]interface{}{
+		"assignee":   assignee,
+		"updated_at": time.Now().UnixMilli(),
+	})
+}
+
+// AddNote 添加告警备注
+func (s *AlertService) AddNote(ctx context.Context, id int64, author, content string) (*models.AlertNote, error) {
+	if _, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, id); err != nil {
+		return nil, err
+	}
+	note := &models.AlertNote{
+		RecordID:  id,
+		Author:    author,
+		Content:   content,
+		CreatedAt: time.Now().UnixMilli(),
+	}
+	if err := s.alertNoteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// ListNotes 获取告警备注列表
+func (s *AlertService) ListNotes(ctx context.Context, id int64) ([]models.AlertNote, error) {
+	return s.alertNoteRepo.ListByRecordID(ctx, id)
+}
+
 // CheckMetrics 检查指标并触发告警
-func (s *AlertService) CheckMetrics(ctx context.Context, agentID string, cpu, memory, disk, networkSpeed float64) error {
+func (s *AlertService) CheckMetrics(ctx context.Context, agentID string, latest *metric.LatestMetrics) error {
 	// 获取全局告警配置
 	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
 	if err != nil {
@@ -78,34 +288,206 @@ func (s *AlertService) CheckMetrics(ctx context.Context, agentID string, cpu, me
 		return err
 	}
 
+	// 按探针ID/标签匹配生效的规则集
+	resolved := s.resolveAgentRules(ctx, alertConfig, agent)
+	if resolved.inMaintenance {
+		return nil
+	}
+	rules := &resolved.rules
+	configID := resolved.configID
+
 	now := time.Now().UnixMilli()
 
 	// 检查 CPU 告警
-	if alertConfig.Rules.CPUEnabled {
-		s.checkAlert(ctx, alertConfig, &agent, "cpu", cpu, alertConfig.Rules.CPUThreshold, alertConfig.Rules.CPUDuration, now)
+	if rules.CPUEnabled && latest.CPU != nil {
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "cpu",
+			value:            latest.CPU.UsagePercent,
+			threshold:        rules.CPUThreshold,
+			resolveThreshold: rules.CPUResolveThreshold,
+			duration:         rules.CPUDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
 	}
 
 	// 检查内存告警
-	if alertConfig.Rules.MemoryEnabled {
-		s.checkAlert(ctx, alertConfig, &agent, "memory", memory, alertConfig.Rules.MemoryThreshold, alertConfig.Rules.MemoryDuration, now)
+	if rules.MemoryEnabled && latest.Memory != nil {
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "memory",
+			value:            latest.Memory.UsagePercent,
+			threshold:        rules.MemoryThreshold,
+			resolveThreshold: rules.MemoryResolveThreshold,
+			duration:         rules.MemoryDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
+	}
+
+	// 检查每核负载告警
+	if rules.LoadEnabled && latest.CPU != nil && latest.CPU.Load != nil && latest.CPU.LogicalCores > 0 {
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "load",
+			value:            latest.CPU.Load.Load1 / float64(latest.CPU.LogicalCores),
+			threshold:        rules.LoadThreshold,
+			resolveThreshold: rules.LoadResolveThreshold,
+			duration:         rules.LoadDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
+	}
+
+	// 检查 Swap 告警，未启用 Swap 的主机不检查
+	if rules.SwapEnabled && latest.Memory != nil && latest.Memory.SwapTotal > 0 {
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "swap",
+			value:            latest.Memory.SwapUsagePercent,
+			threshold:        rules.SwapThreshold,
+			resolveThreshold: rules.SwapResolveThreshold,
+			duration:         rules.SwapDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
 	}
 
-	// 检查磁盘告警
-	if alertConfig.Rules.DiskEnabled {
-		s.checkAlert(ctx, alertConfig, &agent, "disk", disk, alertConfig.Rules.DiskThreshold, alertConfig.Rules.DiskDuration, now)
+	// 检查 CPU Steal 告警
+	if rules.StealEnabled && latest.CPU != nil {
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "steal",
+			value:            latest.CPU.StealPercent,
+			threshold:        rules.StealThreshold,
+			resolveThreshold: rules.StealResolveThreshold,
+			duration:         rules.StealDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
 	}
 
+	// 按挂载点检查磁盘和 inode 告警
+	if latest.Disk != nil {
+		s.checkDiskMounts(ctx, alertConfig, &agent, configID, rules, latest.Disk.Mounts, now)
+	}
+
+	// 基于历史基线的异常检测
+	s.checkAnomalies(ctx, alertConfig, &agent, configID, rules, now)
+
 	// 检查网速告警
-	if alertConfig.Rules.NetworkEnabled {
-		s.checkAlert(ctx, alertConfig, &agent, "network", networkSpeed, alertConfig.Rules.NetworkThreshold, alertConfig.Rules.NetworkDuration, now)
+	if rules.NetworkEnabled && latest.Network != nil {
+		// 网速 = (发送速率 + 接收速率) / 1024 / 1024 (转换为 MB/s)
+		networkSpeed := float64(latest.Network.TotalBytesSentRate+latest.Network.TotalBytesRecvRate) / 1024 / 1024
+		s.checkAlert(ctx, alertConfig, &agent, configID, &metricCheck{
+			alertType:        "network",
+			value:            networkSpeed,
+			threshold:        rules.NetworkThreshold,
+			resolveThreshold: rules.NetworkResolveThreshold,
+			duration:         rules.NetworkDuration,
+			resolveDuration:  rules.ResolveDuration,
+		}, now)
 	}
 
 	return nil
 }
 
-// checkAlert 检查单个告警规则
-func (s *AlertService) checkAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, alertType string, currentValue, threshold float64, duration int, now int64) {
-	stateKey := fmt.Sprintf("%s:global:%s", agent.ID, alertType)
+// metricCheck 单项指标告警的检查参数
+type metricCheck struct {
+	alertType        string
+	target           string            // 检查对象，如磁盘告警的挂载点，作为状态ID后缀
+	labels           map[string]string // 写入告警状态和记录的标签
+	value            float64
+	threshold        float64
+	resolveThreshold float64
+	duration         int
+	resolveDuration  int
+}
+
+// checkDiskMounts 按挂载点检查磁盘和 inode 告警，并恢复已不再参与告警的挂载点
+func (s *AlertService) checkDiskMounts(ctx context.Context, config *models.AlertConfig, agent *models.Agent, configID string, rules *models.AlertRules, mounts []protocol.DiskData, now int64) {
+	checked := make(map[string]bool)
+	for _, mount := range mounts {
+		if !MatchDiskMount(rules, mount.MountPoint) {
+			continue
+		}
+
+		if rules.DiskEnabled {
+			threshold, resolveThreshold := DiskMountThresholds(rules, mount.MountPoint)
+			s.checkAlert(ctx, config, agent, configID, &metricCheck{
+				alertType:        "disk",
+				target:           mount.MountPoint,
+				labels:           map[string]string{"mount_point": mount.MountPoint},
+				value:            mount.UsagePercent,
+				threshold:        threshold,
+				resolveThreshold: resolveThreshold,
+				duration:         rules.DiskDuration,
+				resolveDuration:  rules.ResolveDuration,
+			}, now)
+			checked["disk:"+mount.MountPoint] = true
+		}
+
+		if rules.InodeEnabled && mount.InodesTotal > 0 {
+			s.checkAlert(ctx, config, agent, configID, &metricCheck{
+				alertType:        "inode",
+				target:           mount.MountPoint,
+				labels:           map[string]string{"mount_point": mount.MountPoint},
+				value:            mount.InodesUsagePercent,
+				threshold:        rules.InodeThreshold,
+				resolveThreshold: rules.InodeResolveThreshold,
+				duration:         rules.InodeDuration,
+				resolveDuration:  rules.ResolveDuration,
+			}, now)
+			checked["inode:"+mount.MountPoint] = true
+		}
+	}
+
+	// 挂载点被排除、卸载或告警被关闭后，恢复遗留的告警（包括按汇总使用率触发的旧磁盘告警）
+	states, err := s.AlertStateRepo.FindByAgentID(ctx, agent.ID)
+	if err != nil {
+		s.logger.Error("获取探针告警状态失败", zap.String("agentId", agent.ID), zap.Error(err))
+		return
+	}
+	for i := range states {
+		state := &states[i]
+		if state.AlertType != "disk" && state.AlertType != "inode" {
+			continue
+		}
+		if !state.IsFiring || checked[state.AlertType+":"+state.Labels.Data()["mount_point"]] {
+			continue
+		}
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// MatchDiskMount 判断挂载点是否参与磁盘告警
+func MatchDiskMount(rules *models.AlertRules, mountPoint string) bool {
+	if len(rules.DiskInclude) > 0 && !matchMountPatterns(rules.DiskInclude, mountPoint) {
+		return false
+	}
+	return !matchMountPatterns(rules.DiskExclude, mountPoint)
+}
+
+// DiskMountThresholds 获取挂载点的磁盘告警阈值和恢复阈值，未配置覆盖时使用磁盘告警阈值
+func DiskMountThresholds(rules *models.AlertRules, mountPoint string) (threshold, resolveThreshold float64) {
+	for _, override := range rules.DiskMountThresholds {
+		if matchMountPatterns([]string{override.Pattern}, mountPoint) {
+			return override.Threshold, override.ResolveThreshold
+		}
+	}
+	return rules.DiskThreshold, rules.DiskResolveThreshold
+}
+
+// matchMountPatterns 判断挂载点是否匹配任一 glob 模式
+func matchMountPatterns(patterns []string, mountPoint string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, mountPoint); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAlert 检查单个告警规则，低于恢复阈值并持续恢复持续时间后才恢复
+func (s *AlertService) checkAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, configID string, check *metricCheck, now int64) {
+	stateKey := fmt.Sprintf("%s:%s:%s", agent.ID, configID, check.alertType)
+	if check.target != "" {
+		stateKey += ":" + check.target
+	}
+	alertType := check.alertType
+	currentValue := check.value
+	threshold := check.threshold
 
 	var shouldFire, shouldResolve bool
 
@@ -122,27 +504,41 @@ func (s *AlertService) checkAlert(ctx context.Context, config *models.AlertConfi
 
 	// 按探针维度更新最新阈值/持续时间，支持配置变更
 	state.AgentID = agent.ID
+	state.ConfigID = configID
 	state.AlertType = alertType
 	state.Threshold = threshold
-	state.Duration = duration
+	state.Duration = check.duration
 	state.Value = currentValue
 	state.LastCheckTime = now
+	if check.labels != nil {
+		state.Labels = datatypes.NewJSONType(check.labels)
+	}
 
 	if currentValue >= threshold {
 		if state.StartTime == 0 {
 			state.StartTime = now
 		}
+		state.ResolveStartTime = 0
 
 		elapsedSeconds := (now - state.StartTime) / 1000
-		if elapsedSeconds >= int64(duration) && !state.IsFiring {
+		if elapsedSeconds >= int64(check.duration) && !state.IsFiring {
 			shouldFire = true
 			state.IsFiring = true
 		}
 	} else {
+		state.StartTime = 0
 		if state.IsFiring {
-			shouldResolve = true
+			// 介于恢复阈值和告警阈值之间时保持告警状态
+			if currentValue < resolveThresholdOrDefault(threshold, check.resolveThreshold) {
+				shouldResolve = resolveConfirmed(state, now, check.resolveDuration)
+			} else {
+				state.ResolveStartTime = 0
+			}
 		}
-		state.StartTime = 0
+	}
+
+	if shouldFire || shouldResolve {
+		trackFlapping(&config.Flapping, state, now)
 	}
 
 	// 保存状态到数据库
@@ -175,11 +571,15 @@ func (s *AlertService) fireAlert(ctx context.Context, config *models.AlertConfig
 	record := &models.AlertRecord{
 		AgentID:     agent.ID,
 		AgentName:   agent.Name,
+		ConfigID:    state.ConfigID,
+		StateID:     state.ID,
 		AlertType:   state.AlertType,
 		Message:     s.buildAlertMessage(state),
 		Threshold:   state.Threshold,
 		ActualValue: state.Value,
+		Labels:      state.Labels,
 		Level:       s.calculateLevel(state.Value, state.Threshold),
+		Flapping:    state.IsFlapping,
 		Status:      "firing",
 		FiredAt:     now,
 		CreatedAt:   now,
@@ -193,14 +593,14 @@ func (s *AlertService) fireAlert(ctx context.Context, config *models.AlertConfig
 		return
 	}
 
+	// 发送通知 - 使用新的 context 避免父 context 取消影响通知发送
+	s.notifyStateChange(state, record, agent)
+
 	// 更新状态
 	state.LastRecordID = record.ID
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
-
-	// 发送通知 - 使用新的 context 避免父 context 取消影响通知发送
-	go s.sendAlertNotification(record, agent)
 }
 
 // resolveAlert 恢复告警
@@ -217,8 +617,8 @@ func (s *AlertService) resolveAlert(ctx context.Context, config *models.AlertCon
 		if err != nil {
 			s.logger.Error("获取告警记录失败", zap.Error(err))
 		} else if existingRecord != nil {
-			// 只有当记录状态为 firing 时才更新为 resolved
-			if existingRecord.Status != "firing" {
+			// 只有当记录状态为 firing 或 acknowledged 时才更新为 resolved
+			if !existingRecord.IsActive() {
 				s.logger.Warn("告警记录状态异常,跳过恢复",
 					zap.Int64("recordId", existingRecord.ID),
 					zap.String("status", existingRecord.Status),
@@ -235,7 +635,7 @@ func (s *AlertService) resolveAlert(ctx context.Context, config *models.AlertCon
 					s.logger.Error("更新告警记录失败", zap.Error(err))
 				} else {
 					// 发送恢复通知
-					go s.sendAlertNotification(existingRecord, agent)
+					s.notifyStateChange(state, existingRecord, agent)
 				}
 			}
 		}
@@ -253,31 +653,40 @@ func (s *AlertService) resolveAlert(ctx context.Context, config *models.AlertCon
 func (s *AlertService) buildAlertMessage(state *models.AlertState) string {
 	var alertTypeName string
 	switch state.AlertType {
-	case "cpu":
-		alertTypeName = "CPU使用率"
-	case "memory":
-		alertTypeName = "内存使用率"
-	case "disk":
-		alertTypeName = "磁盘使用率"
+	case "cpu", "memory", "disk", "inode", "swap", "steal":
+		alertTypeName = i18n.T("alert.metric." + state.AlertType)
+	case AlertTypeAnomaly:
+		return buildAnomalyMessage(state)
+	case "load":
+		return i18n.T("alert.msg.load",
+			strconv.Itoa(state.Duration),
+			fmt.Sprintf("%.2f", state.Threshold),
+			fmt.Sprintf("%.2f", state.Value),
+		)
 	case "network":
-		return fmt.Sprintf("网速持续%d秒超过%.2fMB/s，当前值%.2fMB/s",
-			state.Duration,
-			state.Threshold,
-			state.Value,
+		return i18n.T("alert.msg.network",
+			strconv.Itoa(state.Duration),
+			fmt.Sprintf("%.2f", state.Threshold),
+			fmt.Sprintf("%.2f", state.Value),
 		)
 	case "cert":
-		return fmt.Sprintf("HTTPS证书剩余天数%.0f天，低于阈值%.0f天", state.Value, state.Threshold)
+		return i18n.T("alert.msg.cert", fmt.Sprintf("%.0f", state.Value), fmt.Sprintf("%.0f", state.Threshold))
 	case "service":
-		return fmt.Sprintf("服务持续离线%d秒", state.Duration)
+		return i18n.T("alert.msg.service", strconv.Itoa(state.Duration))
 	default:
 		alertTypeName = state.AlertType
 	}
 
-	return fmt.Sprintf("%s持续%d秒超过%.2f%%，当前值%.2f%%",
+	// 磁盘和 inode 告警带上挂载点
+	if mountPoint := state.Labels.Data()["mount_point"]; mountPoint != "" {
+		alertTypeName = i18n.T("alert.msg.mount", mountPoint, alertTypeName)
+	}
+
+	return i18n.T("alert.msg.metric",
 		alertTypeName,
-		state.Duration,
-		state.Threshold,
-		state.Value,
+		strconv.Itoa(state.Duration),
+		fmt.Sprintf("%.2f", state.Threshold),
+		fmt.Sprintf("%.2f", state.Value),
 	)
 }
 
@@ -294,8 +703,59 @@ func (s *AlertService) calculateLevel(value, threshold float64) string {
 	}
 }
 
-// sendAlertNotification 发送告警通知(带panic恢复)
+// sendAlertNotification 发送告警通知，通知渠道按升级策略或路由规则计算
 func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *models.Agent) {
+	s.deliverAlertNotification(record, agent, nil, nil)
+}
+
+// stateNotification 告警状态变化需要发送的通知
+type stateNotification struct {
+	record  *models.AlertRecord
+	include func(channelType string) bool // 只发送到满足条件的渠道，为 nil 时不限制
+}
+
+// isOtherChannel 是否为事件管理渠道以外的渠道
+func isOtherChannel(channelType string) bool {
+	return !IsLifecycleChannel(channelType)
+}
+
+// planStateNotifications 计算告警触发或恢复时需要发送的通知
+//
+// 未抖动时发送到所有渠道；抖动期间事件管理渠道仍接收每次触发和恢复，保证对端的事件能够关闭，
+// 其他渠道只收到一次抖动通知。调用方随后会保存状态。
+func planStateNotifications(state *models.AlertState, record *models.AlertRecord) []stateNotification {
+	if !state.IsFlapping {
+		return []stateNotification{{record: record}}
+	}
+
+	plan := []stateNotification{{record: record, include: IsLifecycleChannel}}
+	if !state.FlapNotified {
+		state.FlapNotified = true
+		flapping := *record
+		flapping.Status = "flapping"
+		plan = append(plan, stateNotification{record: &flapping, include: isOtherChannel})
+	}
+	return plan
+}
+
+// notifyStateChange 发送告警触发或恢复通知，抖动期间其他渠道只发送一次抖动通知
+func (s *AlertService) notifyStateChange(state *models.AlertState, record *models.AlertRecord, agent *models.Agent) {
+	if state.IsFlapping && state.FlapNotified {
+		s.logger.Info("告警抖动中，只通知事件管理渠道",
+			zap.String("stateId", state.ID),
+			zap.Int64("recordId", record.ID),
+			zap.String("status", record.Status),
+		)
+	}
+	for _, n := range planStateNotifications(state, record) {
+		go s.deliverAlertNotification(n.record, agent, nil, n.include)
+	}
+}
+
+// deliverAlertNotification 发送告警通知(带panic恢复)
+//
+// channelTypes 为 nil 时按升级策略和路由规则计算通知渠道；include 不为 nil 时只发送到满足条件的渠道。
+func (s *AlertService) deliverAlertNotification(record *models.AlertRecord, agent *models.Agent, channelTypes []string, include func(channelType string) bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			s.logger.Error("发送告警通知时发生panic",
@@ -308,6 +768,11 @@ func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// 静默命中时仍保留告警记录，但不发送通知
+	if s.isSilenced(ctx, record, agent) {
+		return
+	}
+
 	// 获取告警配置（包含 MaskIP 设置）
 	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
 	if err != nil {
@@ -315,6 +780,16 @@ func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *
 		return
 	}
 
+	// 被其他未恢复告警抑制时不发送通知
+	if s.isInhibited(ctx, &alertConfig.Inhibition, record) {
+		return
+	}
+
+	escalated := channelTypes != nil
+	if channelTypes == nil {
+		channelTypes = s.notifyChannelTypes(ctx, alertConfig, record, agent)
+	}
+
 	channelConfigs, err := s.propertyService.GetNotificationChannelConfigs(ctx)
 	if err != nil {
 		s.logger.Error("获取通知渠道配置失败", zap.Error(err))
@@ -323,21 +798,370 @@ func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *
 
 	var enabledChannels []models.NotificationChannelConfig
 	for _, channel := range channelConfigs {
-		if channel.Enabled {
-			enabledChannels = append(enabledChannels, channel)
+		if !channel.Enabled {
+			continue
+		}
+		if channelTypes != nil && !slices.Contains(channelTypes, channel.Type) {
+			continue
 		}
+		if include != nil && !include(channel.Type) {
+			continue
+		}
+		enabledChannels = append(enabledChannels, channel)
 	}
 
 	if len(enabledChannels) == 0 {
 		return
 	}
 
-	if err := s.notifier.SendNotificationByConfigs(ctx, enabledChannels, record, agent, alertConfig.MaskIP); err != nil {
+	// 首次触发和恢复通知按分组策略聚合发送，升级、重复提醒和只发送到部分渠道的通知不参与聚合
+	groupable := record.Status == "resolved" || (record.Status == "firing" && record.NotifyCount == 0)
+	if !escalated && include == nil && groupable && s.grouper.Add(&alertConfig.Grouping, record, agent, enabledChannels) {
+		return
+	}
+
+	s.notifyChannels(ctx, enabledChannels, record, agent, alertConfig.MaskIP)
+}
+
+// notifyChannelTypes 获取告警通知的渠道类型，返回 nil 表示发送到所有已启用的渠道
+func (s *AlertService) notifyChannelTypes(ctx context.Context, alertConfig *models.AlertConfig, record *models.AlertRecord, agent *models.Agent) []string {
+	routes, err := s.propertyService.GetNotificationRoutes(ctx)
+	if err != nil {
+		s.logger.Error("获取通知路由规则失败", zap.Error(err))
+	}
+	return mergeNotifyChannels(RouteNotification(routes, record, agent), &alertConfig.Escalation, record.EscalationLevel)
+}
+
+// mergeNotifyChannels 合并路由结果与升级策略的通知渠道
+//
+// 命中路由规则时以路由的渠道为准，再追加已到达的升级步骤渠道；命中的规则丢弃通知时不再追加。
+// 未命中任何规则时使用升级策略的首次通知渠道和已到达的升级步骤渠道，均未配置时发送到所有已启用渠道。
+func mergeNotifyChannels(route RouteResult, policy *models.EscalationPolicy, level int) []string {
+	if route.Fallback {
+		return policy.NotifyChannels(level)
+	}
+	if len(route.Channels) == 0 {
+		return route.Channels
+	}
+	return policy.AppendStepChannels(append([]string{}, route.Channels...), level)
+}
+
+// notifyChannels 向指定渠道发送告警通知，触发中的告警同时累加通知次数
+func (s *AlertService) notifyChannels(ctx context.Context, channels []models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent, maskIP bool) {
+	// 发送失败的渠道由通知投递服务继续重试
+	if err := s.deliveryService.Deliver(ctx, channels, record, agent, maskIP); err != nil {
 		s.logger.Error("发送告警通知失败", zap.Error(err))
 	}
+
+	if record.Status == "firing" {
+		if err := s.AlertRecordRepo.MarkNotified(ctx, record.ID, time.Now().UnixMilli()); err != nil {
+			s.logger.Error("更新告警通知次数失败", zap.Error(err))
+		}
+	}
+}
+
+// flushAlertGroup 发送分组聚合后的告警通知，分组内只有一条告警时按普通通知发送
+func (s *AlertService) flushAlertGroup(group *AlertGroup, channels []models.NotificationChannelConfig) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("发送告警聚合通知时发生panic",
+				zap.Any("panic", r),
+				zap.String("groupKey", group.Key),
+			)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取告警配置失败", zap.Error(err))
+		return
+	}
+
+	if len(group.Items) == 1 {
+		item := group.Items[0]
+		s.notifyChannels(ctx, channels, item.Record, item.Agent, alertConfig.MaskIP)
+		return
+	}
+
+	s.logger.Info("发送告警聚合通知",
+		zap.String("groupKey", group.Key),
+		zap.String("status", group.Status),
+		zap.Int("count", len(group.Items)),
+	)
+	if err := s.deliveryService.DeliverGroup(ctx, channels, group, alertConfig.MaskIP); err != nil {
+		s.logger.Error("发送告警聚合通知失败", zap.Error(err))
+	}
+
+	if group.Status == "firing" {
+		now := time.Now().UnixMilli()
+		for _, item := range group.Items {
+			if err := s.AlertRecordRepo.MarkNotified(ctx, item.Record.ID, now); err != nil {
+				s.logger.Error("更新告警通知次数失败", zap.Error(err))
+			}
+		}
+	}
+}
+
+// CheckEscalations 检查未确认的告警，按升级策略重复通知或升级到更多渠道
+func (s *AlertService) CheckEscalations(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	policy := alertConfig.Escalation
+	if !alertConfig.Enabled || !policy.Enabled {
+		return nil
+	}
+
+	states, err := s.AlertStateRepo.FindFiring(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	for _, state := range states {
+		record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+		if err != nil {
+			continue
+		}
+		// 已确认、已恢复或抖动中触发的告警不再提醒
+		if record.Status != "firing" || record.Flapping {
+			continue
+		}
+		s.escalate(ctx, &policy, record, now)
+	}
+
+	return nil
+}
+
+// CheckFlapping 结束已停止变化的告警抖动，并发送抖动期间被跳过的最终状态通知
+func (s *AlertService) CheckFlapping(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	states, err := s.AlertStateRepo.FindFlapping(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	for i := range states {
+		state := &states[i]
+		notified := state.FlapNotified
+		if !flappingEnded(&alertConfig.Flapping, state, now) {
+			continue
+		}
+		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+			s.logger.Error("保存告警状态失败", zap.Error(err))
+			continue
+		}
+
+		s.logger.Info("告警抖动结束",
+			zap.String("stateId", state.ID),
+			zap.Bool("isFiring", state.IsFiring),
+		)
+		s.notifyFlappingEnded(ctx, state, notified)
+	}
+	return nil
+}
+
+// notifyFlappingEnded 抖动结束后清除告警记录的抖动标记，并补发被跳过的最终状态通知
+func (s *AlertService) notifyFlappingEnded(ctx context.Context, state *models.AlertState, notified bool) {
+	if state.IsFiring {
+		if state.LastRecordID == 0 {
+			return
+		}
+		record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+		if err != nil || !record.Flapping {
+			return
+		}
+		// 清除抖动标记后，仍在告警的记录恢复重复提醒和升级
+		if err := s.AlertRecordRepo.UpdateColumnsById(ctx, record.ID, map[string]interface{}{"flapping": false}); err != nil {
+			s.logger.Error("更新告警记录失败", zap.Int64("recordId", record.ID), zap.Error(err))
+			return
+		}
+		record.Flapping = false
+		// 抖动期间触发的告警没有向其他渠道发送过触发通知，事件管理渠道已收到
+		if record.Status == "firing" {
+			go s.deliverAlertNotification(record, s.recordAgent(ctx, record), nil, isOtherChannel)
+		}
+		return
+	}
+
+	// 抖动期间恢复的告警没有向其他渠道发送过恢复通知，事件管理渠道已收到
+	if !notified {
+		return
+	}
+	record, err := s.AlertRecordRepo.GetLatestByStateID(ctx, state.ID)
+	if err != nil || record.Status != "resolved" {
+		return
+	}
+	go s.deliverAlertNotification(record, s.recordAgent(ctx, record), nil, isOtherChannel)
 }
 
-// CheckMonitorAlerts 检查监控相关告警（证书和服务下线）
+// escalate 对单条告警执行升级和重复通知
+func (s *AlertService) escalate(ctx context.Context, policy *models.EscalationPolicy, record *models.AlertRecord, now int64) {
+	elapsed := now - record.FiredAt
+
+	// 升级步骤：到达延迟时间后通知该步骤的渠道
+	var escalatedChannels []string
+	level := record.EscalationLevel
+	for level < len(policy.Steps) && elapsed >= int64(policy.Steps[level].Delay)*60*1000 {
+		escalatedChannels = append(escalatedChannels, policy.Steps[level].Channels...)
+		level++
+	}
+
+	// 重复通知：距上次通知超过间隔时通知当前级别的所有渠道
+	lastNotifiedAt := record.LastNotifiedAt
+	if lastNotifiedAt == 0 {
+		lastNotifiedAt = record.FiredAt
+	}
+	shouldRepeat := policy.RepeatInterval > 0 && now-lastNotifiedAt >= int64(policy.RepeatInterval)*60*1000
+
+	if level == record.EscalationLevel && !shouldRepeat {
+		return
+	}
+
+	// 先同步落库，避免下个检查周期在通知发送完成前重复触发
+	columns := map[string]interface{}{
+		"escalation_level": level,
+		"last_notified_at": now,
+	}
+	if err := s.AlertRecordRepo.UpdateColumnsById(ctx, record.ID, columns); err != nil {
+		s.logger.Error("更新告警升级状态失败", zap.Int64("recordId", record.ID), zap.Error(err))
+		return
+	}
+	record.EscalationLevel = level
+
+	agent := s.recordAgent(ctx, record)
+	if shouldRepeat {
+		s.logger.Info("重复发送告警通知",
+			zap.Int64("recordId", record.ID),
+			zap.String("agentId", record.AgentID),
+			zap.String("alertType", record.AlertType),
+			zap.Int("notifyCount", record.NotifyCount),
+		)
+		go s.deliverAlertNotification(record, agent, nil, nil)
+		return
+	}
+
+	if len(escalatedChannels) == 0 {
+		return
+	}
+
+	s.logger.Info("告警升级",
+		zap.Int64("recordId", record.ID),
+		zap.String("agentId", record.AgentID),
+		zap.String("alertType", record.AlertType),
+		zap.Int("escalationLevel", level),
+		zap.Strings("channels", escalatedChannels),
+	)
+	go s.deliverAlertNotification(record, agent, escalatedChannels, nil)
+}
+
+// isSilenced 判断告警通知是否被静默，触发时命中的静默会写入告警记录
+func (s *AlertService) isSilenced(ctx context.Context, record *models.AlertRecord, agent *models.Agent) bool {
+	silence, err := s.silenceService.FindMatching(ctx, record, agent)
+	if err != nil {
+		s.logger.Error("获取告警静默规则失败", zap.Error(err))
+	}
+
+	if silence == nil {
+		// 触发通知被静默的告警，确认和恢复时同样不再通知
+		return record.Status != "firing" && record.SilenceID != ""
+	}
+
+	if record.Status == "firing" {
+		record.SilenceID = silence.ID
+		if err := s.AlertRecordRepo.UpdateColumnsById(ctx, record.ID, map[string]interface{}{
+			"silence_id": silence.ID,
+		}); err != nil {
+			s.logger.Error("更新告警记录静默信息失败", zap.Error(err))
+		}
+	}
+
+	s.logger.Info("告警通知已静默",
+		zap.Int64("recordId", record.ID),
+		zap.String("agentId", record.AgentID),
+		zap.String("alertType", record.AlertType),
+		zap.String("status", record.Status),
+		zap.String("silenceId", silence.ID),
+	)
+	return true
+}
+
+// isInhibited 判断告警通知是否被抑制，触发时命中的源告警会写入告警记录
+func (s *AlertService) isInhibited(ctx context.Context, policy *models.InhibitionPolicy, record *models.AlertRecord) bool {
+	if record.Status != "firing" {
+		// 触发通知被抑制的告警，确认和恢复时同样不再通知
+		return record.InhibitedBy != 0
+	}
+
+	rules := InhibitRules(policy)
+	if len(rules) == 0 {
+		return false
+	}
+
+	var sourceTypes []string
+	for _, rule := range rules {
+		if len(rule.SourceTypes) == 0 {
+			sourceTypes = nil
+			break
+		}
+		sourceTypes = append(sourceTypes, rule.SourceTypes...)
+	}
+	sources, err := s.AlertRecordRepo.FindActiveByTypes(ctx, sourceTypes)
+	if err != nil {
+		s.logger.Error("获取未恢复告警失败", zap.Error(err))
+		return false
+	}
+
+	for i := range rules {
+		for j := range sources {
+			source := &sources[j]
+			if !MatchInhibitRule(&rules[i], source, record) {
+				continue
+			}
+
+			record.InhibitedBy = source.ID
+			if err := s.AlertRecordRepo.UpdateColumnsById(ctx, record.ID, map[string]interface{}{
+				"inhibited_by": source.ID,
+			}); err != nil {
+				s.logger.Error("更新告警记录抑制信息失败", zap.Error(err))
+			}
+
+			s.logger.Info("告警通知已抑制",
+				zap.Int64("recordId", record.ID),
+				zap.String("agentId", record.AgentID),
+				zap.String("alertType", record.AlertType),
+				zap.String("rule", rules[i].Name),
+				zap.Int64("sourceRecordId", source.ID),
+			)
+			return true
+		}
+	}
+
+	// 源告警已恢复，重复提醒时解除抑制标记
+	if record.InhibitedBy != 0 {
+		record.InhibitedBy = 0
+		if err := s.AlertRecordRepo.UpdateColumnsById(ctx, record.ID, map[string]interface{}{
+			"inhibited_by": 0,
+		}); err != nil {
+			s.logger.Error("更新告警记录抑制信息失败", zap.Error(err))
+		}
+	}
+	return false
+}
+
+// CheckMonitorAlerts 检查监控相关告警（证书、服务下线、探针离线和磁盘写满预测）
 func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 	// 获取全局告警配置
 	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
@@ -351,34 +1175,41 @@ func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 		return nil
 	}
 
+	// 计算所有探针生效的规则，并清理规则来源变更后遗留的状态
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	agentRules := make(map[string]*agentAlertRules, len(agents))
+	for _, agent := range agents {
+		resolved := s.resolveAgentRules(ctx, alertConfig, agent)
+		s.resolveStaleStates(ctx, &resolved.agent, resolved.configID)
+		agentRules[agent.ID] = resolved
+	}
+
 	now := time.Now().UnixMilli()
 
 	// 检查证书告警
-	if alertConfig.Rules.CertEnabled {
-		if err := s.checkCertificateAlerts(ctx, alertConfig, now); err != nil {
-			s.logger.Error("检查证书告警失败", zap.Error(err))
-		}
+	if err := s.checkCertificateAlerts(ctx, alertConfig, agentRules, now); err != nil {
+		s.logger.Error("检查证书告警失败", zap.Error(err))
 	}
 
 	// 检查服务下线告警
-	if alertConfig.Rules.ServiceEnabled {
-		if err := s.checkServiceDownAlerts(ctx, alertConfig, now); err != nil {
-			s.logger.Error("检查服务下线告警失败", zap.Error(err))
-		}
+	if err := s.checkServiceDownAlerts(ctx, alertConfig, agentRules, now); err != nil {
+		s.logger.Error("检查服务下线告警失败", zap.Error(err))
 	}
 
 	// 检查探针离线告警
-	if alertConfig.Rules.AgentOfflineEnabled {
-		if err := s.checkAgentOfflineAlerts(ctx, alertConfig, now); err != nil {
-			s.logger.Error("检查探针离线告警失败", zap.Error(err))
-		}
-	}
+	s.checkAgentOfflineAlerts(ctx, alertConfig, agentRules, now)
+
+	// 检查磁盘写满预测告警
+	s.checkDiskForecastAlerts(ctx, agentRules, now)
 
 	return nil
 }
 
 // checkCertificateAlerts 检查证书告警
-func (s *AlertService) checkCertificateAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
+func (s *AlertService) checkCertificateAlerts(ctx context.Context, config *models.AlertConfig, agentRules map[string]*agentAlertRules, now int64) error {
 	// 获取所有最新的监控指标（仅HTTPS类型）
 	// 这里需要查询最新的 monitor_metrics 记录，获取证书剩余天数
 	monitors, err := s.monitorService.GetLatestMonitorMetricsByType(ctx, "http")
@@ -394,20 +1225,24 @@ func (s *AlertService) checkCertificateAlerts(ctx context.Context, config *model
 
 		certDaysLeft := float64(monitor.CertDaysLeft)
 
-		// 获取探针信息
-		agent, err := s.agentRepo.FindById(ctx, monitor.AgentId)
-		if err != nil {
-			s.logger.Error("获取探针信息失败", zap.String("agentId", monitor.AgentId), zap.Error(err))
+		// 获取探针及其生效规则
+		resolved, ok := agentRules[monitor.AgentId]
+		if !ok {
+			s.logger.Warn("探针不存在，跳过证书告警检查", zap.String("agentId", monitor.AgentId))
+			continue
+		}
+		if !resolved.rules.CertEnabled || resolved.inMaintenance || s.maintenance.IsMonitorInMaintenance(ctx, monitor.MonitorId) {
 			continue
 		}
 
 		// 检查证书剩余天数是否低于阈值
-		if certDaysLeft <= config.Rules.CertThreshold && certDaysLeft >= 0 {
+		rules := &resolved.rules
+		if certDaysLeft <= rules.CertThreshold && certDaysLeft >= 0 {
 			// 触发告警（证书告警不需要持续时间，直接触发）
-			s.checkCertAlert(ctx, config, &agent, &monitor, certDaysLeft, now)
-		} else {
-			// 恢复告警（如果之前触发过）
-			s.resolveCertAlert(ctx, config, &agent, &monitor, certDaysLeft)
+			s.checkCertAlert(ctx, config, resolved, &monitor, certDaysLeft, now)
+		} else if certDaysLeft < 0 || certDaysLeft > resolveThresholdOrDefault(rules.CertThreshold, rules.CertResolveThreshold) {
+			// 恢复告警（如果之前触发过），介于告警阈值和恢复阈值之间时保持告警状态
+			s.resolveCertAlert(ctx, config, resolved, &monitor, certDaysLeft, now)
 		}
 	}
 
@@ -415,8 +1250,10 @@ func (s *AlertService) checkCertificateAlerts(ctx context.Context, config *model
 }
 
 // checkCertAlert 检查并触发证书告警
-func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, monitor *protocol.MonitorData, certDaysLeft float64, now int64) {
-	stateKey := fmt.Sprintf("%s:global:cert:%s", agent.ID, monitor.MonitorId)
+func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertConfig, resolved *agentAlertRules, monitor *protocol.MonitorData, certDaysLeft float64, now int64) {
+	agent := &resolved.agent
+	rules := &resolved.rules
+	stateKey := fmt.Sprintf("%s:%s:cert:%s", agent.ID, resolved.configID, monitor.MonitorId)
 
 	// 从数据库加载状态
 	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
@@ -429,16 +1266,19 @@ func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertC
 		}
 	}
 	state.AgentID = agent.ID
+	state.ConfigID = resolved.configID
 	state.AlertType = "cert"
-	state.Threshold = config.Rules.CertThreshold
+	state.Threshold = rules.CertThreshold
 	state.Duration = 0
 	state.Value = certDaysLeft
 	state.LastCheckTime = now
 
-	shouldFire := certDaysLeft <= config.Rules.CertThreshold && !state.IsFiring
+	shouldFire := certDaysLeft <= rules.CertThreshold && !state.IsFiring
+	state.ResolveStartTime = 0
 
 	if shouldFire {
 		state.IsFiring = true
+		trackFlapping(&config.Flapping, state, now)
 	}
 
 	// 保存状态到数据库
@@ -455,17 +1295,21 @@ func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertC
 		zap.String("monitorId", monitor.MonitorId),
 		zap.String("target", monitor.Target),
 		zap.Float64("certDaysLeft", certDaysLeft),
-		zap.Float64("threshold", config.Rules.CertThreshold),
+		zap.Float64("threshold", rules.CertThreshold),
 	)
 
 	record := &models.AlertRecord{
 		AgentID:     agent.ID,
 		AgentName:   agent.Name,
+		ConfigID:    state.ConfigID,
+		StateID:     state.ID,
 		AlertType:   "cert",
-		Message:     fmt.Sprintf("监控项 %s 的HTTPS证书剩余天数%.0f天，低于阈值%.0f天", monitor.Target, certDaysLeft, config.Rules.CertThreshold),
-		Threshold:   config.Rules.CertThreshold,
+		MonitorID:   monitor.MonitorId,
+		Message:     i18n.T("alert.msg.cert_monitor", monitor.Target, fmt.Sprintf("%.0f", certDaysLeft), fmt.Sprintf("%.0f", rules.CertThreshold)),
+		Threshold:   rules.CertThreshold,
 		ActualValue: certDaysLeft,
 		Level:       s.calculateCertLevel(certDaysLeft),
+		Flapping:    state.IsFlapping,
 		Status:      "firing",
 		FiredAt:     now,
 		CreatedAt:   now,
@@ -477,24 +1321,33 @@ func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertC
 		return
 	}
 
+	// 发送通知
+	s.notifyStateChange(state, record, agent)
+
 	state.LastRecordID = record.ID
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
-
-	// 发送通知
-	go s.sendAlertNotification(record, agent)
 }
 
 // resolveCertAlert 恢复证书告警
-func (s *AlertService) resolveCertAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, monitor *protocol.MonitorData, certDaysLeft float64) {
-	stateKey := fmt.Sprintf("%s:global:cert:%s", agent.ID, monitor.MonitorId)
+func (s *AlertService) resolveCertAlert(ctx context.Context, config *models.AlertConfig, resolved *agentAlertRules, monitor *protocol.MonitorData, certDaysLeft float64, now int64) {
+	agent := &resolved.agent
+	stateKey := fmt.Sprintf("%s:%s:cert:%s", agent.ID, resolved.configID, monitor.MonitorId)
 
 	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
 	if err != nil || !state.IsFiring {
 		return
 	}
 
+	if !resolveConfirmed(state, now, resolved.rules.ResolveDuration) {
+		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+			s.logger.Error("保存告警状态失败", zap.Error(err))
+		}
+		return
+	}
+	trackFlapping(&config.Flapping, state, now)
+
 	s.logger.Info("证书告警恢复",
 		zap.String("agentId", agent.ID),
 		zap.String("monitorId", monitor.MonitorId),
@@ -506,8 +1359,7 @@ func (s *AlertService) resolveCertAlert(ctx context.Context, config *models.Aler
 		existingRecord, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
 		if err != nil {
 			s.logger.Error("获取证书告警记录失败", zap.Error(err))
-		} else if existingRecord != nil && existingRecord.Status == "firing" {
-			now := time.Now().UnixMilli()
+		} else if existingRecord != nil && existingRecord.IsActive() {
 			existingRecord.Status = "resolved"
 			existingRecord.ActualValue = certDaysLeft
 			existingRecord.ResolvedAt = now
@@ -518,7 +1370,7 @@ func (s *AlertService) resolveCertAlert(ctx context.Context, config *models.Aler
 				s.logger.Error("更新证书告警记录失败", zap.Error(err))
 			} else {
 				// 发送恢复通知
-				go s.sendAlertNotification(existingRecord, agent)
+				s.notifyStateChange(state, existingRecord, agent)
 			}
 		}
 	}
@@ -542,7 +1394,7 @@ func (s *AlertService) calculateCertLevel(daysLeft float64) string {
 }
 
 // checkServiceDownAlerts 检查服务下线告警
-func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
+func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *models.AlertConfig, agentRules map[string]*agentAlertRules, now int64) error {
 	// 获取所有最新的监控指标
 	monitors, err := s.monitorService.GetAllLatestMonitorMetrics(ctx)
 	if err != nil {
@@ -550,14 +1402,19 @@ func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *model
 	}
 
 	for _, monitor := range monitors {
-		// 获取探针信息
-		agent, err := s.agentRepo.FindById(ctx, monitor.AgentId)
-		if err != nil {
-			s.logger.Error("获取探针信息失败", zap.String("agentId", monitor.AgentId), zap.Error(err))
+		// 获取探针及其生效规则
+		resolved, ok := agentRules[monitor.AgentId]
+		if !ok {
+			s.logger.Warn("探针不存在，跳过服务下线告警检查", zap.String("agentId", monitor.AgentId))
+			continue
+		}
+		if !resolved.rules.ServiceEnabled || resolved.inMaintenance || s.maintenance.IsMonitorInMaintenance(ctx, monitor.MonitorId) {
 			continue
 		}
+		agent := resolved.agent
+		rules := &resolved.rules
 
-		stateKey := fmt.Sprintf("%s:global:service:%s", agent.ID, monitor.MonitorId)
+		stateKey := fmt.Sprintf("%s:%s:service:%s", agent.ID, resolved.configID, monitor.MonitorId)
 
 		var shouldFire, shouldResolve bool
 
@@ -572,27 +1429,33 @@ func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *model
 			}
 		}
 		state.AgentID = agent.ID
+		state.ConfigID = resolved.configID
 		state.AlertType = "service"
-		state.Duration = config.Rules.ServiceDuration
+		state.Duration = rules.ServiceDuration
 		state.LastCheckTime = now
 
 		if monitor.Status == "down" {
 			if state.StartTime == 0 {
 				state.StartTime = monitor.CheckedAt
 			}
+			state.ResolveStartTime = 0
 
 			elapsedSeconds := (now - state.StartTime) / 1000
-			if elapsedSeconds >= int64(config.Rules.ServiceDuration) && !state.IsFiring {
+			if elapsedSeconds >= int64(rules.ServiceDuration) && !state.IsFiring {
 				shouldFire = true
 				state.IsFiring = true
 			}
 		} else {
 			if state.IsFiring {
-				shouldResolve = true
+				shouldResolve = resolveConfirmed(state, now, rules.ResolveDuration)
 			}
 			state.StartTime = 0
 		}
 
+		if shouldFire || shouldResolve {
+			trackFlapping(&config.Flapping, state, now)
+		}
+
 		// 保存状态到数据库
 		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 			s.logger.Error("保存告警状态失败", zap.Error(err))
@@ -623,11 +1486,15 @@ func (s *AlertService) fireServiceDownAlert(ctx context.Context, config *models.
 	record := &models.AlertRecord{
 		AgentID:     agent.ID,
 		AgentName:   agent.Name,
+		ConfigID:    state.ConfigID,
+		StateID:     state.ID,
 		AlertType:   "service",
-		Message:     fmt.Sprintf("监控项 %s 持续离线%d秒", monitor.Target, state.Duration),
+		MonitorID:   monitor.MonitorId,
+		Message:     i18n.T("alert.msg.service_monitor", monitor.Target, strconv.Itoa(state.Duration)),
 		Threshold:   0,
 		ActualValue: float64(state.Duration),
 		Level:       "critical",
+		Flapping:    state.IsFlapping,
 		Status:      "firing",
 		FiredAt:     now,
 		CreatedAt:   now,
@@ -639,13 +1506,13 @@ func (s *AlertService) fireServiceDownAlert(ctx context.Context, config *models.
 		return
 	}
 
+	// 发送通知
+	s.notifyStateChange(state, record, agent)
+
 	state.LastRecordID = record.ID
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
-
-	// 发送通知
-	go s.sendAlertNotification(record, agent)
 }
 
 // resolveServiceDownAlert 恢复服务下线告警
@@ -660,7 +1527,7 @@ func (s *AlertService) resolveServiceDownAlert(ctx context.Context, config *mode
 		existingRecord, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
 		if err != nil {
 			s.logger.Error("获取服务下线告警记录失败", zap.Error(err))
-		} else if existingRecord != nil && existingRecord.Status == "firing" {
+		} else if existingRecord != nil && existingRecord.IsActive() {
 			now := time.Now().UnixMilli()
 			existingRecord.Status = "resolved"
 			existingRecord.ResolvedAt = now
@@ -671,7 +1538,7 @@ func (s *AlertService) resolveServiceDownAlert(ctx context.Context, config *mode
 				s.logger.Error("更新服务下线告警记录失败", zap.Error(err))
 			} else {
 				// 发送恢复通知
-				go s.sendAlertNotification(existingRecord, agent)
+				s.notifyStateChange(state, existingRecord, agent)
 			}
 		}
 	}
@@ -684,15 +1551,15 @@ func (s *AlertService) resolveServiceDownAlert(ctx context.Context, config *mode
 }
 
 // checkAgentOfflineAlerts 检查探针离线告警
-func (s *AlertService) checkAgentOfflineAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
-	// 获取所有探针
-	agents, err := s.agentRepo.FindAll(ctx)
-	if err != nil {
-		return err
-	}
+func (s *AlertService) checkAgentOfflineAlerts(ctx context.Context, config *models.AlertConfig, agentRules map[string]*agentAlertRules, now int64) {
+	for _, resolved := range agentRules {
+		if !resolved.rules.AgentOfflineEnabled || resolved.inMaintenance {
+			continue
+		}
+		agent := resolved.agent
+		rules := &resolved.rules
 
-	for _, agent := range agents {
-		stateKey := fmt.Sprintf("%s:global:agent_offline:%s", agent.ID, agent.ID)
+		stateKey := fmt.Sprintf("%s:%s:agent_offline:%s", agent.ID, resolved.configID, agent.ID)
 
 		// 防止时钟回拨导致负数
 		offlineSeconds := int64(0)
@@ -712,15 +1579,16 @@ func (s *AlertService) checkAgentOfflineAlerts(ctx context.Context, config *mode
 		}
 
 		state.AgentID = agent.ID
+		state.ConfigID = resolved.configID
 		state.AlertType = "agent_offline"
-		state.Duration = config.Rules.AgentOfflineDuration
-		state.Threshold = float64(config.Rules.AgentOfflineDuration)
+		state.Duration = rules.AgentOfflineDuration
+		state.Threshold = float64(rules.AgentOfflineDuration)
 		state.Value = float64(offlineSeconds)
 		state.LastCheckTime = now
 
 		var shouldFire, shouldResolve bool
 
-		if offlineSeconds >= int64(config.Rules.AgentOfflineDuration) {
+		if offlineSeconds >= int64(rules.AgentOfflineDuration) {
 			if !state.IsFiring {
 				shouldFire = true
 				state.IsFiring = true
@@ -744,8 +1612,6 @@ func (s *AlertService) checkAgentOfflineAlerts(ctx context.Context, config *mode
 			s.resolveAgentOfflineAlert(ctx, config, &agent, state)
 		}
 	}
-
-	return nil
 }
 
 // fireAgentOfflineAlert 触发探针离线告警
@@ -761,8 +1627,10 @@ func (s *AlertService) fireAgentOfflineAlert(ctx context.Context, config *models
 	record := &models.AlertRecord{
 		AgentID:     agent.ID,
 		AgentName:   agent.Name,
+		ConfigID:    state.ConfigID,
+		StateID:     state.ID,
 		AlertType:   "agent_offline",
-		Message:     fmt.Sprintf("探针 %s 已离线%d秒，超过阈值%d秒", agent.Name, offlineSeconds, state.Duration),
+		Message:     i18n.T("alert.msg.agent_offline", agent.Name, strconv.FormatInt(offlineSeconds, 10), strconv.Itoa(state.Duration)),
 		Threshold:   float64(state.Duration),
 		ActualValue: float64(offlineSeconds),
 		Level:       "critical",
@@ -798,7 +1666,7 @@ func (s *AlertService) resolveAgentOfflineAlert(ctx context.Context, config *mod
 		existingRecord, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
 		if err != nil {
 			s.logger.Error("获取探针离线告警记录失败", zap.Error(err))
-		} else if existingRecord != nil && existingRecord.Status == "firing" {
+		} else if existingRecord != nil && existingRecord.IsActive() {
 			now := time.Now().UnixMilli()
 			existingRecord.Status = "resolved"
 			existingRecord.ResolvedAt = now
@@ -820,3 +1688,258 @@ func (s *AlertService) resolveAgentOfflineAlert(ctx context.Context, config *mod
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
 }
+
+// CheckExpressionRules 检查 PromQL 表达式告警规则
+func (s *AlertService) CheckExpressionRules(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	rules, err := s.exprRuleService.FindAllEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	for i := range rules {
+		s.checkExpressionRule(ctx, &rules[i], now)
+	}
+	s.resolveStaleExpressionStates(ctx, rules)
+
+	return nil
+}
+
+// ResolveExpressionRule 恢复表达式规则触发的所有告警并清理其告警状态，用于规则被禁用、删除或修改表达式时
+func (s *AlertService) ResolveExpressionRule(ctx context.Context, rule *models.AlertExpressionRule) error {
+	states, err := s.AlertStateRepo.FindByConfigID(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	for i := range states {
+		s.closeExpressionState(ctx, rule, &states[i])
+	}
+	return nil
+}
+
+// resolveStaleExpressionStates 恢复已禁用或已删除的表达式规则遗留的告警状态
+func (s *AlertService) resolveStaleExpressionStates(ctx context.Context, rules []models.AlertExpressionRule) {
+	states, err := s.AlertStateRepo.FindByAlertType(ctx, models.AlertTypeExpression)
+	if err != nil {
+		s.logger.Error("获取表达式告警状态失败", zap.Error(err))
+		return
+	}
+	for _, state := range staleExpressionStates(states, rules) {
+		rule, err := s.exprRuleService.FindById(ctx, state.ConfigID)
+		if err != nil {
+			// 规则已删除
+			rule = models.AlertExpressionRule{ID: state.ConfigID}
+		}
+		s.closeExpressionState(ctx, &rule, state)
+	}
+}
+
+// staleExpressionStates 筛选不属于任何启用规则的表达式告警状态
+func staleExpressionStates(states []models.AlertState, enabledRules []models.AlertExpressionRule) []*models.AlertState {
+	enabled := make(map[string]bool, len(enabledRules))
+	for _, rule := range enabledRules {
+		enabled[rule.ID] = true
+	}
+	var stale []*models.AlertState
+	for i := range states {
+		if states[i].AlertType == models.AlertTypeExpression && !enabled[states[i].ConfigID] {
+			stale = append(stale, &states[i])
+		}
+	}
+	return stale
+}
+
+// ExpressionRuleNeedsResolve 判断规则更新后是否需要恢复其现有告警：规则被禁用或表达式发生变化
+func ExpressionRuleNeedsResolve(old, updated *models.AlertExpressionRule) bool {
+	return !updated.Enabled || old.Expr != updated.Expr
+}
+
+// closeExpressionState 恢复告警中的表达式告警状态并删除该状态
+func (s *AlertService) closeExpressionState(ctx context.Context, rule *models.AlertExpressionRule, state *models.AlertState) {
+	if state.IsFiring {
+		s.resolveExpressionAlert(ctx, rule, state)
+	}
+	if err := s.AlertStateRepo.DeleteAlertState(ctx, state.ID); err != nil {
+		s.logger.Error("删除告警状态失败", zap.Error(err))
+	}
+}
+
+// checkExpressionRule 检查单条表达式规则，查询结果中的每个时间序列对应一个告警状态
+func (s *AlertService) checkExpressionRule(ctx context.Context, rule *models.AlertExpressionRule, now int64) {
+	points, err := s.exprRuleService.Evaluate(ctx, rule.Expr)
+	if err != nil {
+		// 查询失败时保持现有状态，避免误恢复
+		s.logger.Error("执行告警表达式失败",
+			zap.String("ruleId", rule.ID),
+			zap.String("expr", rule.Expr),
+			zap.Error(err),
+		)
+		return
+	}
+
+	states, err := s.AlertStateRepo.FindByConfigID(ctx, rule.ID)
+	if err != nil {
+		s.logger.Error("获取表达式告警状态失败", zap.String("ruleId", rule.ID), zap.Error(err))
+		return
+	}
+	existing := make(map[string]*models.AlertState, len(states))
+	for i := range states {
+		existing[states[i].ID] = &states[i]
+	}
+
+	active := make(map[string]bool, len(points))
+	for _, point := range points {
+		labels := expressionSeriesLabels(rule, point.Labels)
+		agentID := labels["agent_id"]
+		stateKey := fmt.Sprintf("%s:%s:%s:%s", agentID, rule.ID, models.AlertTypeExpression, labelsFingerprint(labels))
+		active[stateKey] = true
+
+		state, ok := existing[stateKey]
+		if !ok {
+			state = &models.AlertState{
+				ID:        stateKey,
+				StartTime: now,
+			}
+		}
+		state.AgentID = agentID
+		state.ConfigID = rule.ID
+		state.AlertType = models.AlertTypeExpression
+		state.Value = point.Value
+		state.Duration = rule.For
+		state.Labels = datatypes.NewJSONType(labels)
+		state.LastCheckTime = now
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+
+		shouldFire := !state.IsFiring && (now-state.StartTime)/1000 >= int64(rule.For)
+		if shouldFire && agentID != "" {
+			// 维护期内的探针不触发表达式告警
+			if agent, err := s.agentRepo.FindById(ctx, agentID); err == nil && s.maintenance.IsAgentInMaintenance(ctx, &agent) {
+				shouldFire = false
+			}
+		}
+		if shouldFire {
+			state.IsFiring = true
+		}
+
+		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+			s.logger.Error("保存告警状态失败", zap.Error(err))
+		}
+
+		if shouldFire {
+			s.fireExpressionAlert(ctx, rule, state, now)
+		}
+	}
+
+	// 不再出现在结果中的时间序列视为已恢复
+	for _, state := range existing {
+		if active[state.ID] {
+			continue
+		}
+		s.closeExpressionState(ctx, rule, state)
+	}
+}
+
+// expressionAlertAgent 获取表达式告警关联的探针，时间序列不带 agent_id 时使用规则名称占位
+func (s *AlertService) expressionAlertAgent(ctx context.Context, rule *models.AlertExpressionRule, agentID string) *models.Agent {
+	if agentID != "" {
+		agent, err := s.agentRepo.FindById(ctx, agentID)
+		if err == nil {
+			return &agent
+		}
+	}
+	return &models.Agent{ID: agentID, Name: rule.Name}
+}
+
+// fireExpressionAlert 触发表达式告警
+func (s *AlertService) fireExpressionAlert(ctx context.Context, rule *models.AlertExpressionRule, state *models.AlertState, now int64) {
+	agent := s.expressionAlertAgent(ctx, rule, state.AgentID)
+	labels := state.Labels.Data()
+
+	s.logger.Info("触发表达式告警",
+		zap.String("ruleId", rule.ID),
+		zap.String("ruleName", rule.Name),
+		zap.String("agentId", state.AgentID),
+		zap.Any("labels", labels),
+		zap.Float64("value", state.Value),
+	)
+
+	level := rule.Level
+	if level == "" {
+		level = "warning"
+	}
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		ConfigID:    rule.ID,
+		StateID:     state.ID,
+		AlertType:   models.AlertTypeExpression,
+		Message:     buildExpressionMessage(rule, state.Value, labels),
+		Threshold:   0,
+		ActualValue: state.Value,
+		Labels:      state.Labels,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		s.logger.Error("创建表达式告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+}
+
+// resolveExpressionAlert 恢复表达式告警
+func (s *AlertService) resolveExpressionAlert(ctx context.Context, rule *models.AlertExpressionRule, state *models.AlertState) {
+	s.logger.Info("表达式告警恢复",
+		zap.String("ruleId", rule.ID),
+		zap.String("ruleName", rule.Name),
+		zap.String("agentId", state.AgentID),
+	)
+
+	if state.LastRecordID == 0 {
+		return
+	}
+
+	existingRecord, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+	if err != nil {
+		s.logger.Error("获取表达式告警记录失败", zap.Error(err))
+		return
+	}
+	if !existingRecord.IsActive() {
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	existingRecord.Status = "resolved"
+	existingRecord.ActualValue = state.Value
+	existingRecord.ResolvedAt = now
+	existingRecord.UpdatedAt = now
+
+	if err := s.AlertRecordRepo.UpdateAlertRecord(ctx, existingRecord); err != nil {
+		s.logger.Error("更新表达式告警记录失败", zap.Error(err))
+		return
+	}
+
+	go s.sendAlertNotification(existingRecord, s.expressionAlertAgent(ctx, rule, state.AgentID))
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// sentStatuses 按渠道收集计划发送的通知状态
+func sentStatuses(plan []stateNotification, sent map[string][]string) {
+	for _, channelType := range []string{"pagerduty", "opsgenie", "alertmanager", "dingtalk", "email"} {
+		for _, n := range plan {
+			if n.include == nil || n.include(channelType) {
+				sent[channelType] = append(sent[channelType], n.record.Status)
+			}
+		}
+	}
+}
+
+func TestPlanStateNotificationsFlappingThenResolve(t *testing.T) {
+	policy := &models.FlappingPolicy{Enabled: true, Threshold: 3, Window: 600}
+	state := &models.AlertState{ID: "agent-1:global:cpu"}
+	sent := map[string][]string{}
+
+	var now int64 = 1_700_000_000_000
+	for _, status := range []string{"firing", "resolved", "firing", "resolved"} {
+		now += 60_000
+		trackFlapping(policy, state, now)
+		sentStatuses(planStateNotifications(state, &models.AlertRecord{Status: status}), sent)
+	}
+
+	// 事件管理渠道收到每一次触发和恢复，最终状态为恢复，对端事件能够关闭
+	for _, channelType := range []string{"pagerduty", "opsgenie", "alertmanager"} {
+		want := []string{"firing", "resolved", "firing", "resolved"}
+		if got := sent[channelType]; len(got) != len(want) || got[len(got)-1] != "resolved" {
+			t.Errorf("%s 收到的通知 = %v，期望 %v", channelType, got, want)
+		}
+	}
+	// 其他渠道在抖动后只收到一次抖动通知
+	for _, channelType := range []string{"dingtalk", "email"} {
+		want := []string{"firing", "resolved", "flapping"}
+		if got := sent[channelType]; len(got) != len(want) || got[2] != "flapping" {
+			t.Errorf("%s 收到的通知 = %v，期望 %v", channelType, got, want)
+		}
+	}
+
+	// 停止变化一个窗口后抖动结束，由 CheckFlapping 向其他渠道补发最终的恢复通知
+	if !state.FlapNotified {
+		t.Fatalf("抖动通知发送后应标记 FlapNotified")
+	}
+	if flappingEnded(policy, state, now+60_000) {
+		t.Fatalf("窗口内仍有足够的状态变化，抖动不应结束")
+	}
+	if !flappingEnded(policy, state, now+601_000) {
+		t.Fatalf("窗口内的状态变化不足阈值时抖动应结束")
+	}
+	if state.IsFlapping || state.FlapNotified {
+		t.Errorf("抖动结束后应清除抖动状态: %+v", state)
+	}
+}
+
+func TestIsLifecycleChannel(t *testing.T) {
+	for channelType, want := range map[string]bool{
+		"pagerduty":    true,
+		"opsgenie":     true,
+		"alertmanager": true,
+		"webhook":      false,
+		"slack":        false,
+	} {
+		if got := IsLifecycleChannel(channelType); got != want {
+			t.Errorf("IsLifecycleChannel(%q) = %v，期望 %v", channelType, got, want)
+		}
+	}
+}
+
+func TestMatchDiskMount(t *testing.T) {
+	tests := []struct {
+		name       string
+		include    []string
+		exclude    []string
+		mountPoint string
+		want       bool
+	}{
+		{name: "未配置时包含所有挂载点", mountPoint: "/data", want: true},
+		{name: "匹配包含模式", include: []string{"/", "/data*"}, mountPoint: "/data1", want: true},
+		{name: "不匹配包含模式", include: []string{"/", "/data*"}, mountPoint: "/var", want: false},
+		{name: "glob 不跨越路径分隔符", include: []string{"/mnt/*"}, mountPoint: "/mnt/a/b", want: false},
+		{name: "匹配排除模式", exclude: []string{"/snap/*", "/boot"}, mountPoint: "/snap/core", want: false},
+		{name: "不匹配排除模式", exclude: []string{"/snap/*"}, mountPoint: "/home", want: true},
+		{name: "排除优先于包含", include: []string{"/mnt/*"}, exclude: []string{"/mnt/tmp"}, mountPoint: "/mnt/tmp", want: false},
+		{name: "包含且未被排除", include: []string{"/mnt/*"}, exclude: []string{"/mnt/tmp"}, mountPoint: "/mnt/backup", want: true},
+		{name: "无效模式不匹配", exclude: []string{"["}, mountPoint: "/", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := &models.AlertRules{DiskInclude: tt.include, DiskExclude: tt.exclude}
+			if got := MatchDiskMount(rules, tt.mountPoint); got != tt.want {
+				t.Errorf("MatchDiskMount(%q) = %v，期望 %v", tt.mountPoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiskMountThresholds(t *testing.T) {
+	rules := &models.AlertRules{
+		DiskThreshold:        85,
+		DiskResolveThreshold: 80,
+		DiskMountThresholds: []models.DiskMountThreshold{
+			{Pattern: "/data*", Threshold: 95, ResolveThreshold: 90},
+			{Pattern: "/data1", Threshold: 70},
+		},
+	}
+	if threshold, resolve := DiskMountThresholds(rules, "/data1"); threshold != 95 || resolve != 90 {
+		t.Errorf("应按顺序使用第一条匹配的覆盖阈值: %v %v", threshold, resolve)
+	}
+	if threshold, resolve := DiskMountThresholds(rules, "/"); threshold != 85 || resolve != 80 {
+		t.Errorf("未匹配覆盖时应使用磁盘告警阈值: %v %v", threshold, resolve)
+	}
+}
+
+func TestNotifyChannelsWithEscalationAndRouting(t *testing.T) {
+	routes := []models.NotificationRoute{
+		{Name: "traffic-info", Enabled: true, AlertTypes: []string{"traffic"}, Levels: []string{"info"}, Channels: []string{"webhook"}},
+		{Name: "drop-memory", Enabled: true, AlertTypes: []string{"memory"}},
+	}
+	policy := &models.EscalationPolicy{
+		Enabled:  true,
+		Channels: []string{"dingtalk", "email"},
+		Steps: []models.EscalationStep{
+			{Delay: 10, Channels: []string{"pagerduty"}},
+			{Delay: 30, Channels: []string{"email", "sms"}},
+		},
+	}
+	agent := &models.Agent{ID: "agent-1"}
+
+	tests := []struct {
+		name   string
+		record *models.AlertRecord
+		policy *models.EscalationPolicy
+		want   []string
+	}{
+		{name: "命中路由时不使用升级策略的首次通知渠道", record: &models.AlertRecord{AlertType: "traffic", Level: "info"}, policy: policy, want: []string{"webhook"}},
+		{name: "命中路由后追加已到达的升级步骤渠道", record: &models.AlertRecord{AlertType: "traffic", Level: "info", EscalationLevel: 2}, policy: policy, want: []string{"webhook", "pagerduty", "email", "sms"}},
+		{name: "命中的路由丢弃通知时不追加升级渠道", record: &models.AlertRecord{AlertType: "memory", EscalationLevel: 1}, policy: policy, want: []string{}},
+		{name: "未命中路由时使用升级策略的渠道", record: &models.AlertRecord{AlertType: "cpu", EscalationLevel: 1}, policy: policy, want: []string{"dingtalk", "email", "pagerduty"}},
+		{name: "未命中路由且未启用升级时发送到所有渠道", record: &models.AlertRecord{AlertType: "cpu", EscalationLevel: 1}, policy: &models.EscalationPolicy{}, want: nil},
+		{name: "未启用升级时只使用路由渠道", record: &models.AlertRecord{AlertType: "traffic", Level: "info", EscalationLevel: 1}, policy: &models.EscalationPolicy{Steps: policy.Steps}, want: []string{"webhook"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeNotifyChannels(RouteNotification(routes, tt.record, agent), tt.policy, tt.record.EscalationLevel)
+			if (got == nil) != (tt.want == nil) || !slices.Equal(got, tt.want) {
+				t.Errorf("通知渠道 = %#v，期望 %#v", got, tt.want)
+			}
+		})
+	}
+}
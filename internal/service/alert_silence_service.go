@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AlertSilenceService 告警静默服务
+type AlertSilenceService struct {
+	logger *zap.Logger
+	*repo.AlertSilenceRepo
+}
+
+func NewAlertSilenceService(logger *zap.Logger, db *gorm.DB) *AlertSilenceService {
+	return &AlertSilenceService{
+		logger:           logger,
+		AlertSilenceRepo: repo.NewAlertSilenceRepo(db),
+	}
+}
+
+// Expire 立即结束静默
+func (s *AlertSilenceService) Expire(ctx context.Context, id string) error {
+	now := time.Now().UnixMilli()
+	return s.UpdateColumnsById(ctx, id, map[string]interface{}{
+		"ends_at":    now,
+		"updated_at": now,
+	})
+}
+
+// FindMatching 查找当前命中告警记录的静默规则，未命中返回 nil
+func (s *AlertSilenceService) FindMatching(ctx context.Context, record *models.AlertRecord, agent *models.Agent) (*models.AlertSilence, error) {
+	silences, err := s.FindActive(ctx, time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	for i := range silences {
+		if MatchAlertSilence(&silences[i], record, agent) {
+			return &silences[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// MatchAlertSilence 判断静默规则是否命中告警记录，未设置任何匹配条件的静默不命中
+func MatchAlertSilence(silence *models.AlertSilence, record *models.AlertRecord, agent *models.Agent) bool {
+	if silence.AgentID == "" && silence.Tag == "" && silence.AlertType == "" && silence.MonitorID == "" {
+		return false
+	}
+	if silence.AgentID != "" && silence.AgentID != record.AgentID {
+		return false
+	}
+	if silence.Tag != "" && (agent == nil || !slices.Contains(agent.Tags, silence.Tag)) {
+		return false
+	}
+	if silence.AlertType != "" && silence.AlertType != record.AlertType {
+		return false
+	}
+	if silence.MonitorID != "" && silence.MonitorID != record.MonitorID {
+		return false
+	}
+	return true
+}
+
+// SilenceStatus 计算静默状态: pending（未开始）, active（生效中）, expired（已过期）
+func SilenceStatus(silence *models.AlertSilence, now int64) string {
+	switch {
+	case now < silence.StartsAt:
+		return "pending"
+	case now < silence.EndsAt:
+		return "active"
+	default:
+		return "expired"
+	}
+}
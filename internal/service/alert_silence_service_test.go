@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMatchAlertSilence(t *testing.T) {
+	agent := &models.Agent{ID: "agent-1", Tags: []string{"prod"}}
+	record := &models.AlertRecord{AgentID: "agent-1", AlertType: "service", MonitorID: "monitor-1"}
+
+	tests := []struct {
+		name    string
+		silence models.AlertSilence
+		agent   *models.Agent
+		want    bool
+	}{
+		{name: "未设置匹配条件不命中", silence: models.AlertSilence{}, agent: agent, want: false},
+		{name: "匹配探针", silence: models.AlertSilence{AgentID: "agent-1"}, agent: agent, want: true},
+		{name: "其他探针", silence: models.AlertSilence{AgentID: "agent-2"}, agent: agent, want: false},
+		{name: "匹配标签", silence: models.AlertSilence{Tag: "prod"}, agent: agent, want: true},
+		{name: "探针没有该标签", silence: models.AlertSilence{Tag: "dev"}, agent: agent, want: false},
+		{name: "按标签匹配时探针不存在", silence: models.AlertSilence{Tag: "prod"}, agent: nil, want: false},
+		{name: "匹配告警类型", silence: models.AlertSilence{AlertType: "service"}, agent: agent, want: true},
+		{name: "匹配监控项", silence: models.AlertSilence{MonitorID: "monitor-1"}, agent: agent, want: true},
+		{name: "所有条件都满足", silence: models.AlertSilence{AgentID: "agent-1", Tag: "prod", AlertType: "service", MonitorID: "monitor-1"}, agent: agent, want: true},
+		{name: "任一条件不满足", silence: models.AlertSilence{AgentID: "agent-1", AlertType: "cpu"}, agent: agent, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchAlertSilence(&tt.silence, record, tt.agent); got != tt.want {
+				t.Errorf("MatchAlertSilence = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSilenceStatus(t *testing.T) {
+	silence := &models.AlertSilence{StartsAt: 1000, EndsAt: 2000}
+	for now, want := range map[int64]string{999: "pending", 1000: "active", 1999: "active", 2000: "expired"} {
+		if got := SilenceStatus(silence, now); got != want {
+			t.Errorf("SilenceStatus(%d) = %s，期望 %s", now, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	// AlertTypeTamperEvent 受保护目录文件变更告警类型
+	AlertTypeTamperEvent = "tamper_event"
+	// AlertTypeTamperAttr 不可变属性被移除告警类型
+	AlertTypeTamperAttr = "tamper_attr"
+
+	defaultTamperRateLimit = 300 // 默认同一受保护目录的通知间隔（秒）
+)
+
+// TamperAlert 防篡改告警事件
+type TamperAlert struct {
+	AlertType string // 告警类型: tamper_event, tamper_attr
+	Root      string // 所属受保护目录，用于限流
+	Path      string // 发生变更的路径
+	Operation string // 操作类型，仅文件变更事件
+	Details   string // 详细信息
+	Restored  bool   // 属性是否已自动恢复，仅属性告警
+}
+
+// HandleTamperAlert 将防篡改事件转换为告警记录并发送通知，
+// 同一受保护目录在限流间隔内的后续事件只累加到已有告警记录，不再重复通知。
+// 防篡改事件是时间点事件，没有恢复条件，记录直接保存为已恢复，不会停留在活跃告警中
+func (s *AlertService) HandleTamperAlert(ctx context.Context, agentID string, alert *TamperAlert) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	resolved := s.resolveAgentRules(ctx, alertConfig, agent)
+	if resolved.inMaintenance {
+		return nil
+	}
+	rules := &resolved.rules
+	switch alert.AlertType {
+	case AlertTypeTamperEvent:
+		if !rules.TamperEventEnabled {
+			return nil
+		}
+	case AlertTypeTamperAttr:
+		if !rules.TamperAttrEnabled {
+			return nil
+		}
+	default:
+		return fmt.Errorf("未知的防篡改告警类型: %s", alert.AlertType)
+	}
+
+	rateLimit := rules.TamperRateLimit
+	if rateLimit <= 0 {
+		rateLimit = defaultTamperRateLimit
+	}
+
+	s.tamperMu.Lock()
+	defer s.tamperMu.Unlock()
+
+	now := time.Now().UnixMilli()
+	key := fmt.Sprintf("%s:%s:%s", agentID, alert.AlertType, alert.Root)
+	if recordID, ok := s.tamperLimits.Get(key); ok {
+		record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, recordID)
+		if err == nil {
+			// 限流间隔内，合并到已有告警记录
+			record.ActualValue++
+			record.Message = buildTamperMessage(alert, int(record.ActualValue))
+			record.UpdatedAt = now
+			return s.AlertRecordRepo.UpdateAlertRecord(ctx, record)
+		}
+	}
+
+	level := "critical"
+	if alert.AlertType == AlertTypeTamperAttr && alert.Restored {
+		level = "warning"
+	}
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		ConfigID:    resolved.configID,
+		AlertType:   alert.AlertType,
+		Message:     buildTamperMessage(alert, 1),
+		Threshold:   1,
+		ActualValue: 1,
+		Labels: datatypes.NewJSONType(map[string]string{
+			"path": alert.Path,
+			"root": alert.Root,
+		}),
+		Level:      level,
+		Status:     "resolved",
+		FiredAt:    now,
+		ResolvedAt: now,
+		CreatedAt:  now,
+	}
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		return err
+	}
+	s.tamperLimits.Set(key, record.ID, time.Duration(rateLimit)*time.Second)
+
+	s.logger.Info("触发防篡改告警",
+		zap.String("agentId", agent.ID),
+		zap.String("alertType", alert.AlertType),
+		zap.String("path", alert.Path),
+	)
+
+	// 记录直接保存为已恢复，通知使用 info 状态发送
+	notice := *record
+	notice.Status = "info"
+	go s.sendAlertNotification(&notice, &agent)
+	return nil
+}
+
+// buildTamperMessage 构建防篡改告警消息，count 为限流间隔内合并的事件数
+func buildTamperMessage(alert *TamperAlert, count int) string {
+	var message string
+	switch alert.AlertType {
+	case AlertTypeTamperAttr:
+		status := i18n.T("alert.tamper.not_restored")
+		if alert.Restored {
+			status = i18n.T("alert.tamper.restored")
+		}
+		message = i18n.T("alert.msg.tamper_attr", alert.Path, status)
+	default:
+		message = i18n.T("alert.msg.tamper_event", alert.Root, alert.Operation, alert.Path)
+	}
+	if alert.Details != "" {
+		message += i18n.T("alert.msg.details", alert.Details)
+	}
+	if count > 1 {
+		message += i18n.T("alert.msg.count", strconv.Itoa(count))
+	}
+	return message
+}
@@ -26,8 +26,12 @@ func NewApiKeyService(logger *zap.Logger, db *gorm.DB) *ApiKeyService {
 	}
 }
 
-// GenerateApiKey 生成API密钥
-func (s *ApiKeyService) GenerateApiKey(ctx context.Context, name, userID string) (*models.ApiKey, error) {
+// GenerateApiKey 生成API密钥，scope 为空时生成探针注册密钥
+func (s *ApiKeyService) GenerateApiKey(ctx context.Context, name, scope, userID string) (*models.ApiKey, error) {
+	if scope == "" {
+		scope = models.ApiKeyScopeAgent
+	}
+
 	// 生成32字节随机密钥
 	key, err := s.generateSecureKey(32)
 	if err != nil {
@@ -39,6 +43,7 @@ func (s *ApiKeyService) GenerateApiKey(ctx context.Context, name, userID string)
 		ID:        uuid.NewString(),
 		Name:      name,
 		Key:       key,
+		Scope:     scope,
 		Enabled:   true,
 		CreatedBy: userID,
 		CreatedAt: now,
@@ -52,13 +57,14 @@ func (s *ApiKeyService) GenerateApiKey(ctx context.Context, name, userID string)
 	s.logger.Info("api key generated",
 		zap.String("keyID", apiKey.ID),
 		zap.String("name", name),
+		zap.String("scope", scope),
 		zap.String("userID", userID))
 
 	return apiKey, nil
 }
 
-// ValidateApiKey 验证API密钥
-func (s *ApiKeyService) ValidateApiKey(ctx context.Context, key string) (*models.ApiKey, error) {
+// ValidateApiKey 验证API密钥，密钥必须启用且用途与 scope 一致
+func (s *ApiKeyService) ValidateApiKey(ctx context.Context, key, scope string) (*models.ApiKey, error) {
 	if key == "" {
 		return nil, errors.New("api key is required")
 	}
@@ -69,6 +75,19 @@ func (s *ApiKeyService) ValidateApiKey(ctx context.Context, key string) (*models
 		return nil, errors.New("invalid api key")
 	}
 
+	// 升级前创建的密钥没有用途，视为探针注册密钥
+	keyScope := apiKey.Scope
+	if keyScope == "" {
+		keyScope = models.ApiKeyScopeAgent
+	}
+	if keyScope != scope {
+		s.logger.Warn("api key scope mismatch",
+			zap.String("keyID", apiKey.ID),
+			zap.String("scope", keyScope),
+			zap.String("required", scope))
+		return nil, errors.New("invalid api key")
+	}
+
 	return apiKey, nil
 }
 
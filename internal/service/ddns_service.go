@@ -31,7 +31,9 @@ type DDNSService struct {
 	recordRepo      *repo.DDNSRecordRepo
 	propertyService *PropertyService
 	wsManager       *websocket.Manager
+	alertService    *AlertService
 	ipCache         *syncx.SafeMap[string, *ipCacheData] // 使用内存缓存存储 IP
+	reportedIPs     *syncx.SafeMap[string, *ipCacheData] // 探针最近一次上报的 IP，用于 IP 变化通知
 }
 
 func NewDDNSService(
@@ -40,6 +42,7 @@ func NewDDNSService(
 	recordRepo *repo.DDNSRecordRepo,
 	propertyService *PropertyService,
 	wsManager *websocket.Manager,
+	alertService *AlertService,
 ) *DDNSService {
 	s := &DDNSService{
 		logger:          logger,
@@ -47,7 +50,9 @@ func NewDDNSService(
 		recordRepo:      recordRepo,
 		propertyService: propertyService,
 		wsManager:       wsManager,
+		alertService:    alertService,
 		ipCache:         syncx.NewSafeMap[string, *ipCacheData](),
+		reportedIPs:     syncx.NewSafeMap[string, *ipCacheData](),
 	}
 
 	// 初始化 IP 缓存：从 DNS 服务商查询当前记录
@@ -155,6 +160,8 @@ func (s *DDNSService) HandleIPReport(ctx context.Context, agentID string, ipData
 		oldIPv6 = cachedIP.IPv6
 	}
 
+	s.notifyIPChange(ctx, agentID, config, ipData, cachedIP)
+
 	// 检查 IP 是否变化
 	ipv4Changed := config.EnableIPv4 && ipData.IPv4 != "" && oldIPv4 != ipData.IPv4
 	ipv6Changed := config.EnableIPv6 && ipData.IPv6 != "" && oldIPv6 != ipData.IPv6
@@ -186,11 +193,16 @@ func (s *DDNSService) HandleIPReport(ctx context.Context, agentID string, ipData
 	// 创建 DNS 提供商客户端
 	provider, err := s.createProvider(ctx, config)
 	if err != nil {
-		return fmt.Errorf("创建 DNS 提供商失败: %w", err)
+		err = fmt.Errorf("创建 DNS 提供商失败: %w", err)
+		s.reportUpdateResult(ctx, agentID, config, err)
+		return err
 	}
 
-	// 更新 DNS 记录
+	// 更新 DNS 记录，记录最后一次失败原因
+	var updateErr error
+
 	// 处理 IPv4 域名
+	ipv4Failed := false
 	if ipv4Changed {
 		for _, domain := range config.DomainsIPv4 {
 			if err := s.updateRecord(ctx, provider, config, domain, ddns.RecordTypeA, ipData.IPv4, oldIPv4); err != nil {
@@ -198,11 +210,14 @@ func (s *DDNSService) HandleIPReport(ctx context.Context, agentID string, ipData
 					zap.String("agentId", agentID),
 					zap.String("domain", domain),
 					zap.Error(err))
+				ipv4Failed = true
+				updateErr = err
 			}
 		}
 	}
 
 	// 处理 IPv6 域名
+	ipv6Failed := false
 	if ipv6Changed {
 		for _, domain := range config.DomainsIPv6 {
 			if err := s.updateRecord(ctx, provider, config, domain, ddns.RecordTypeAAAA, ipData.IPv6, oldIPv6); err != nil {
@@ -210,16 +225,62 @@ func (s *DDNSService) HandleIPReport(ctx context.Context, agentID string, ipData
 					zap.String("agentId", agentID),
 					zap.String("domain", domain),
 					zap.Error(err))
+				ipv6Failed = true
+				updateErr = err
 			}
 		}
 	}
 
-	// 更新内存缓存
-	s.ipCache.Set(agentID, &ipCacheData{IPv4: ipData.IPv4, IPv6: ipData.IPv6})
+	s.reportUpdateResult(ctx, agentID, config, updateErr)
+
+	// 更新内存缓存，更新失败的记录保留旧 IP，下次上报时重试
+	newCache := &ipCacheData{IPv4: ipData.IPv4, IPv6: ipData.IPv6}
+	if ipv4Failed {
+		newCache.IPv4 = oldIPv4
+	}
+	if ipv6Failed {
+		newCache.IPv6 = oldIPv6
+	}
+	s.ipCache.Set(agentID, newCache)
 
 	return nil
 }
 
+// reportUpdateResult 将 DDNS 更新结果交给告警服务，用于连续失败告警
+func (s *DDNSService) reportUpdateResult(ctx context.Context, agentID string, config *models.DDNSConfig, updateErr error) {
+	if err := s.alertService.ReportDDNSUpdate(ctx, agentID, config, updateErr); err != nil {
+		s.logger.Error("处理 DDNS 更新失败告警出错",
+			zap.String("agentId", agentID),
+			zap.String("configId", config.ID),
+			zap.Error(err))
+	}
+}
+
+// notifyIPChange 对比探针上一次上报的 IP，变化时发送 IP 变化通知
+// 服务重启后没有上报记录，使用 DNS 记录中的 IP 作为对比基准
+func (s *DDNSService) notifyIPChange(ctx context.Context, agentID string, config *models.DDNSConfig, ipData *protocol.DDNSIPReportData, cachedIP *ipCacheData) {
+	previous, ok := s.reportedIPs.Get(agentID)
+	if !ok {
+		previous = cachedIP
+	}
+	s.reportedIPs.Set(agentID, &ipCacheData{IPv4: ipData.IPv4, IPv6: ipData.IPv6})
+	if previous == nil {
+		return
+	}
+
+	var changes []DDNSIPChange
+	if config.EnableIPv4 && ipData.IPv4 != "" && previous.IPv4 != "" && previous.IPv4 != ipData.IPv4 {
+		changes = append(changes, DDNSIPChange{RecordType: ddns.RecordTypeA, OldIP: previous.IPv4, NewIP: ipData.IPv4})
+	}
+	if config.EnableIPv6 && ipData.IPv6 != "" && previous.IPv6 != "" && previous.IPv6 != ipData.IPv6 {
+		changes = append(changes, DDNSIPChange{RecordType: ddns.RecordTypeAAAA, OldIP: previous.IPv6, NewIP: ipData.IPv6})
+	}
+
+	if err := s.alertService.NotifyIPChange(ctx, agentID, changes); err != nil {
+		s.logger.Error("发送公网 IP 变化通知失败", zap.String("agentId", agentID), zap.Error(err))
+	}
+}
+
 // updateRecord 更新单条 DNS 记录
 func (s *DDNSService) updateRecord(
 	ctx context.Context,
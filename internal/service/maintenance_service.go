@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/cache"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const enabledMaintenanceWindowsCacheKey = "enabled"
+
+// MaintenanceService 维护窗口服务
+type MaintenanceService struct {
+	logger *zap.Logger
+	*repo.MaintenanceWindowRepo
+	cache cache.Cache[string, []models.MaintenanceWindow]
+}
+
+func NewMaintenanceService(logger *zap.Logger, db *gorm.DB) *MaintenanceService {
+	return &MaintenanceService{
+		logger:                logger,
+		MaintenanceWindowRepo: repo.NewMaintenanceWindowRepo(db),
+		cache:                 cache.New[string, []models.MaintenanceWindow](time.Minute),
+	}
+}
+
+// Create 创建维护窗口
+func (s *MaintenanceService) Create(ctx context.Context, window *models.MaintenanceWindow) error {
+	if err := s.MaintenanceWindowRepo.Create(ctx, window); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledMaintenanceWindowsCacheKey)
+	return nil
+}
+
+// Update 更新维护窗口
+func (s *MaintenanceService) Update(ctx context.Context, window *models.MaintenanceWindow) error {
+	if err := s.MaintenanceWindowRepo.Save(ctx, window); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledMaintenanceWindowsCacheKey)
+	return nil
+}
+
+// UpdateEnabled 启用/禁用维护窗口
+func (s *MaintenanceService) UpdateEnabled(ctx context.Context, id string, enabled bool) error {
+	if err := s.MaintenanceWindowRepo.UpdateEnabled(ctx, id, enabled); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledMaintenanceWindowsCacheKey)
+	return nil
+}
+
+// Delete 删除维护窗口
+func (s *MaintenanceService) Delete(ctx context.Context, id string) error {
+	if err := s.MaintenanceWindowRepo.DeleteById(ctx, id); err != nil {
+		return err
+	}
+	s.cache.Delete(enabledMaintenanceWindowsCacheKey)
+	return nil
+}
+
+// activeWindows 获取当前处于维护期的窗口
+func (s *MaintenanceService) activeWindows(ctx context.Context, now time.Time) []models.MaintenanceWindow {
+	windows, ok := s.cache.Get(enabledMaintenanceWindowsCacheKey)
+	if !ok {
+		var err error
+		windows, err = s.MaintenanceWindowRepo.FindAllEnabled(ctx)
+		if err != nil {
+			s.logger.Error("获取维护窗口失败", zap.Error(err))
+			return nil
+		}
+		s.cache.Set(enabledMaintenanceWindowsCacheKey, windows, time.Hour)
+	}
+
+	var active []models.MaintenanceWindow
+	for _, window := range windows {
+		if IsMaintenanceWindowActive(&window, now) {
+			active = append(active, window)
+		}
+	}
+	return active
+}
+
+// IsAgentInMaintenance 判断探针是否处于维护期（按探针ID或标签匹配）
+func (s *MaintenanceService) IsAgentInMaintenance(ctx context.Context, agent *models.Agent) bool {
+	for _, window := range s.activeWindows(ctx, time.Now()) {
+		if slices.Contains(window.AgentIds, agent.ID) {
+			return true
+		}
+		if slices.ContainsFunc(window.Tags, func(tag string) bool {
+			return slices.Contains(agent.Tags, tag)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMonitorInMaintenance 判断监控项是否处于维护期
+func (s *MaintenanceService) IsMonitorInMaintenance(ctx context.Context, monitorID string) bool {
+	for _, window := range s.activeWindows(ctx, time.Now()) {
+		if slices.Contains(window.MonitorIds, monitorID) {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceLocation 获取维护窗口的时区
+func maintenanceLocation(window *models.MaintenanceWindow) (*time.Location, error) {
+	if window.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(window.Timezone)
+}
+
+// ValidateMaintenanceWindow 校验维护窗口配置
+func ValidateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	if _, err := maintenanceLocation(window); err != nil {
+		return fmt.Errorf("无效的时区: %s", window.Timezone)
+	}
+	if len(window.AgentIds) == 0 && len(window.Tags) == 0 && len(window.MonitorIds) == 0 {
+		return errors.New("至少需要指定一个探针、标签或监控项")
+	}
+
+	switch window.Recurrence {
+	case "once":
+		if window.EndsAt <= window.StartsAt {
+			return errors.New("结束时间必须晚于开始时间")
+		}
+	case "weekly":
+		if len(window.Weekdays) == 0 {
+			return errors.New("每周维护需要指定星期")
+		}
+		for _, weekday := range window.Weekdays {
+			if weekday < 0 || weekday > 6 {
+				return fmt.Errorf("无效的星期: %d", weekday)
+			}
+		}
+		if _, err := time.Parse("15:04", window.StartTime); err != nil {
+			return fmt.Errorf("无效的开始时间: %s", window.StartTime)
+		}
+		if window.Duration <= 0 {
+			return errors.New("维护持续时间必须大于 0")
+		}
+	case "cron":
+		if _, err := cron.ParseStandard(window.Cron); err != nil {
+			return fmt.Errorf("无效的 cron 表达式: %w", err)
+		}
+		if window.Duration <= 0 {
+			return errors.New("维护持续时间必须大于 0")
+		}
+	default:
+		return fmt.Errorf("不支持的重复方式: %s", window.Recurrence)
+	}
+	return nil
+}
+
+// IsMaintenanceWindowActive 判断维护窗口在指定时间是否处于维护期
+func IsMaintenanceWindowActive(window *models.MaintenanceWindow, now time.Time) bool {
+	if !window.Enabled {
+		return false
+	}
+
+	loc, err := maintenanceLocation(window)
+	if err != nil {
+		return false
+	}
+	now = now.In(loc)
+	duration := time.Duration(window.Duration) * time.Minute
+
+	switch window.Recurrence {
+	case "once":
+		ts := now.UnixMilli()
+		return window.StartsAt <= ts && ts < window.EndsAt
+	case "weekly":
+		startTime, err := time.Parse("15:04", window.StartTime)
+		if err != nil {
+			return false
+		}
+		// 维护可能跨天，向前检查一周内的开始时间
+		for i := 0; i <= 7; i++ {
+			day := now.AddDate(0, 0, -i)
+			if !slices.Contains(window.Weekdays, int(day.Weekday())) {
+				continue
+			}
+			start := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, loc)
+			if !start.After(now) && now.Before(start.Add(duration)) {
+				return true
+			}
+		}
+		return false
+	case "cron":
+		schedule, err := cron.ParseStandard(window.Cron)
+		if err != nil {
+			return false
+		}
+		// 若 [now-duration, now] 内存在一次开始时间，则处于维护期
+		next := schedule.Next(now.Add(-duration))
+		return !next.After(now)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestIsMaintenanceWindowActive(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("加载时区失败: %v", err)
+	}
+	at := func(day, hour, min int) time.Time {
+		// 2024-06-01 是周六
+		return time.Date(2024, 6, day, hour, min, 0, 0, shanghai)
+	}
+
+	once := models.MaintenanceWindow{
+		Enabled:    true,
+		Recurrence: "once",
+		StartsAt:   at(1, 10, 0).UnixMilli(),
+		EndsAt:     at(1, 12, 0).UnixMilli(),
+	}
+	// 周六 23:00 开始，持续 2 小时，跨越午夜
+	weekly := models.MaintenanceWindow{
+		Enabled:    true,
+		Recurrence: "weekly",
+		Weekdays:   []int{6},
+		StartTime:  "23:00",
+		Duration:   120,
+		Timezone:   "Asia/Shanghai",
+	}
+	weeklyUTC := weekly
+	weeklyUTC.Timezone = "UTC"
+	// 每天 23:30 开始，持续 1 小时
+	cronWindow := models.MaintenanceWindow{
+		Enabled:    true,
+		Recurrence: "cron",
+		Cron:       "30 23 * * *",
+		Duration:   60,
+		Timezone:   "Asia/Shanghai",
+	}
+	disabled := weekly
+	disabled.Enabled = false
+	badTimezone := weekly
+	badTimezone.Timezone = "Mars/Olympus"
+	badCron := cronWindow
+	badCron.Cron = "not a cron"
+
+	tests := []struct {
+		name   string
+		window models.MaintenanceWindow
+		now    time.Time
+		want   bool
+	}{
+		{name: "单次维护开始前", window: once, now: at(1, 9, 59), want: false},
+		{name: "单次维护开始时", window: once, now: at(1, 10, 0), want: true},
+		{name: "单次维护结束时", window: once, now: at(1, 12, 0), want: false},
+		{name: "每周维护开始前", window: weekly, now: at(1, 22, 59), want: false},
+		{name: "每周维护开始时", window: weekly, now: at(1, 23, 0), want: true},
+		{name: "每周维护跨越午夜", window: weekly, now: at(2, 0, 30), want: true},
+		{name: "每周维护跨越午夜后结束", window: weekly, now: at(2, 1, 0), want: false},
+		{name: "其他星期不维护", window: weekly, now: at(3, 23, 30), want: false},
+		{name: "按窗口时区计算", window: weekly, now: time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC), want: true},
+		{name: "UTC 时区的同一时刻不在维护期", window: weeklyUTC, now: time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC), want: false},
+		{name: "UTC 时区按 UTC 时间维护", window: weeklyUTC, now: time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC), want: true},
+		{name: "cron 维护开始前", window: cronWindow, now: at(3, 23, 29), want: false},
+		{name: "cron 维护中", window: cronWindow, now: at(3, 23, 45), want: true},
+		{name: "cron 维护跨越午夜", window: cronWindow, now: at(4, 0, 29), want: true},
+		{name: "cron 维护结束", window: cronWindow, now: at(4, 0, 30), want: false},
+		{name: "禁用的维护窗口", window: disabled, now: at(1, 23, 30), want: false},
+		{name: "无效的时区", window: badTimezone, now: at(1, 23, 30), want: false},
+		{name: "无效的 cron 表达式", window: badCron, now: at(3, 23, 45), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMaintenanceWindowActive(&tt.window, tt.now); got != tt.want {
+				t.Errorf("IsMaintenanceWindowActive(%s) = %v，期望 %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,13 @@ func (s *MetricService) convertToMetrics(agentID string, metricType string, data
 		metrics = append(metrics, createMetric("pika_cpu_usage_percent", agentID, nil, cpuData.UsagePercent, timestamp))
 		metrics = append(metrics, createMetric("pika_cpu_cores_logical", agentID, nil, float64(cpuData.LogicalCores), timestamp))
 		metrics = append(metrics, createMetric("pika_cpu_cores_physical", agentID, nil, float64(cpuData.PhysicalCores), timestamp))
+		metrics = append(metrics, createMetric("pika_cpu_steal_percent", agentID, nil, cpuData.StealPercent, timestamp))
+		metrics = append(metrics, createMetric("pika_cpu_iowait_percent", agentID, nil, cpuData.IOWaitPercent, timestamp))
+		if cpuData.Load != nil {
+			metrics = append(metrics, createMetric("pika_load1", agentID, nil, cpuData.Load.Load1, timestamp))
+			metrics = append(metrics, createMetric("pika_load5", agentID, nil, cpuData.Load.Load5, timestamp))
+			metrics = append(metrics, createMetric("pika_load15", agentID, nil, cpuData.Load.Load15, timestamp))
+		}
 
 	case protocol.MetricTypeMemory:
 		memData := data.(*protocol.MemoryData)
@@ -26,6 +33,7 @@ func (s *MetricService) convertToMetrics(agentID string, metricType string, data
 		metrics = append(metrics, createMetric("pika_memory_available_bytes", agentID, nil, float64(memData.Available), timestamp))
 		metrics = append(metrics, createMetric("pika_memory_swap_total_bytes", agentID, nil, float64(memData.SwapTotal), timestamp))
 		metrics = append(metrics, createMetric("pika_memory_swap_used_bytes", agentID, nil, float64(memData.SwapUsed), timestamp))
+		metrics = append(metrics, createMetric("pika_memory_swap_usage_percent", agentID, nil, memData.SwapUsagePercent, timestamp))
 
 	case protocol.MetricTypeDisk:
 		diskDataList := data.([]protocol.DiskData)
@@ -35,6 +43,12 @@ func (s *MetricService) convertToMetrics(agentID string, metricType string, data
 			metrics = append(metrics, createMetric("pika_disk_total_bytes", agentID, labels, float64(diskData.Total), timestamp))
 			metrics = append(metrics, createMetric("pika_disk_used_bytes", agentID, labels, float64(diskData.Used), timestamp))
 			metrics = append(metrics, createMetric("pika_disk_free_bytes", agentID, labels, float64(diskData.Free), timestamp))
+			// 部分文件系统（如 Windows、btrfs）不提供 inode 信息
+			if diskData.InodesTotal > 0 {
+				metrics = append(metrics, createMetric("pika_disk_inodes_usage_percent", agentID, labels, diskData.InodesUsagePercent, timestamp))
+				metrics = append(metrics, createMetric("pika_disk_inodes_total", agentID, labels, float64(diskData.InodesTotal), timestamp))
+				metrics = append(metrics, createMetric("pika_disk_inodes_used", agentID, labels, float64(diskData.InodesUsed), timestamp))
+			}
 		}
 
 	case protocol.MetricTypeNetwork:
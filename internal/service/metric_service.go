@@ -102,6 +102,7 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Total:        totalTotal,
 			Used:         totalUsed,
 			Free:         totalFree,
+			Mounts:       diskDataList,
 		}
 		metrics := s.convertToMetrics(agentID, metricType, diskDataList, now)
 		return s.vmClient.Write(ctx, metrics)
@@ -366,16 +367,27 @@ func (s *MetricService) buildPromQLQueries(agentID, metricType string, interface
 
 	switch metricType {
 	case "cpu":
-		queries = []metric.QueryDefinition{{
-			Name:  "usage",
-			Query: fmt.Sprintf(`pika_cpu_usage_percent{agent_id="%s"}`, agentID),
-		}}
+		// CPU：使用率、steal 和 iowait
+		queries = []metric.QueryDefinition{
+			{Name: "usage", Query: fmt.Sprintf(`pika_cpu_usage_percent{agent_id="%s"}`, agentID)},
+			{Name: "steal", Query: fmt.Sprintf(`pika_cpu_steal_percent{agent_id="%s"}`, agentID)},
+			{Name: "iowait", Query: fmt.Sprintf(`pika_cpu_iowait_percent{agent_id="%s"}`, agentID)},
+		}
 
 	case "memory":
-		queries = []metric.QueryDefinition{{
-			Name:  "usage",
-			Query: fmt.Sprintf(`pika_memory_usage_percent{agent_id="%s"}`, agentID),
-		}}
+		// 内存：内存和 Swap 使用率
+		queries = []metric.QueryDefinition{
+			{Name: "usage", Query: fmt.Sprintf(`pika_memory_usage_percent{agent_id="%s"}`, agentID)},
+			{Name: "swap", Query: fmt.Sprintf(`pika_memory_swap_usage_percent{agent_id="%s"}`, agentID)},
+		}
+
+	case "load":
+		// 系统负载：1、5、15 分钟平均负载
+		queries = []metric.QueryDefinition{
+			{Name: "load1", Query: fmt.Sprintf(`pika_load1{agent_id="%s"}`, agentID)},
+			{Name: "load5", Query: fmt.Sprintf(`pika_load5{agent_id="%s"}`, agentID)},
+			{Name: "load15", Query: fmt.Sprintf(`pika_load15{agent_id="%s"}`, agentID)},
+		}
 
 	case "disk":
 		queries = []metric.QueryDefinition{{
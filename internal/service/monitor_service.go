@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/dushixiang/pika/internal/metric"
@@ -25,6 +26,7 @@ type MonitorService struct {
 	agentRepo     *repo.AgentRepo
 	metricRepo    *repo.MetricRepo
 	metricService *MetricService
+	maintenance   *MaintenanceService
 	wsManager     *ws.Manager
 
 	// 调度器引用（用于动态管理任务）
@@ -38,7 +40,7 @@ type MonitorScheduler interface {
 	RemoveTask(monitorID string)
 }
 
-func NewMonitorService(logger *zap.Logger, db *gorm.DB, metricService *MetricService, wsManager *ws.Manager) *MonitorService {
+func NewMonitorService(logger *zap.Logger, db *gorm.DB, metricService *MetricService, maintenance *MaintenanceService, wsManager *ws.Manager) *MonitorService {
 	return &MonitorService{
 		logger:        logger,
 		Service:       orz.NewService(db),
@@ -46,6 +48,7 @@ func NewMonitorService(logger *zap.Logger, db *gorm.DB, metricService *MetricSer
 		agentRepo:     repo.NewAgentRepo(db),
 		metricRepo:    repo.NewMetricRepo(db),
 		metricService: metricService,
+		maintenance:   maintenance,
 		wsManager:     wsManager,
 	}
 }
@@ -339,6 +342,11 @@ func (s *MonitorService) SendMonitorTaskToAgents(ctx context.Context, monitor mo
 
 	// 使用统一的方法计算目标探针
 	targetAgents := s.resolveTargetAgents(monitor, onlineAgents)
+
+	// 维护期内的探针不执行检测，避免影响可用率统计
+	targetAgents = slices.DeleteFunc(targetAgents, func(agent models.Agent) bool {
+		return s.maintenance.IsAgentInMaintenance(ctx, &agent)
+	})
 	if len(targetAgents) == 0 {
 		return nil
 	}
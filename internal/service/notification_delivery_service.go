@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"github.com/jpillora/backoff"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxDeliveryAttempts 单条投递的最大尝试次数，超过后标记为失败
+	maxDeliveryAttempts = 8
+	// deliveryTimeout 单次发送的超时时间
+	deliveryTimeout = 30 * time.Second
+	// deliveryLease 发送期间的租约时间，进程在发送中退出时，租约到期后由重试任务接管
+	deliveryLease = 2 * deliveryTimeout
+	// deliveryRetryBatch 每轮重试处理的最大记录数
+	deliveryRetryBatch = 50
+)
+
+// deliveryPayload 投递内容快照，单条通知记录告警和探针，聚合通知记录整个分组
+type deliveryPayload struct {
+	Record *models.AlertRecord `json:"record,omitempty"`
+	Agent  *models.Agent       `json:"agent,omitempty"`
+	Group  *AlertGroup         `json:"group,omitempty"`
+	MaskIP bool                `json:"maskIp"`
+}
+
+// NotificationDeliveryService 通知投递服务（通知发件箱）
+//
+// 每条通知在每个渠道的投递都会持久化为一条记录，发送失败时按指数退避重试，
+// 重试次数用尽后标记为失败，管理员可以查看投递结果并手动重发。
+type NotificationDeliveryService struct {
+	DeliveryRepo    *repo.NotificationDeliveryRepo
+	alertRecordRepo *repo.AlertRecordRepo
+	propertyService *PropertyService
+	notifier        *Notifier
+	backoff         *backoff.Backoff
+	logger          *zap.Logger
+}
+
+func NewNotificationDeliveryService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, notifier *Notifier) *NotificationDeliveryService {
+	return &NotificationDeliveryService{
+		DeliveryRepo:    repo.NewNotificationDeliveryRepo(db),
+		alertRecordRepo: repo.NewAlertRecordRepo(db),
+		propertyService: propertyService,
+		notifier:        notifier,
+		// 重试间隔: 30s, 1m, 2m, 4m ... 最长 1h
+		backoff: &backoff.Backoff{
+			Min:    30 * time.Second,
+			Max:    time.Hour,
+			Factor: 2,
+			Jitter: true,
+		},
+		logger: logger,
+	}
+}
+
+// Deliver 为每个渠道创建投递记录并立即发送，发送失败的渠道由重试任务继续投递
+func (s *NotificationDeliveryService) Deliver(ctx context.Context, channels []models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent, maskIP bool) error {
+	payload := &deliveryPayload{Record: record, Agent: agent, MaskIP: maskIP}
+	template := models.NotificationDelivery{
+		RecordID:    record.ID,
+		RecordIDs:   joinRecordIDs([]int64{record.ID}),
+		AlertStatus: record.Status,
+		Level:       record.Level,
+		Summary:     truncateText(fmt.Sprintf("[%s] %s", agent.Name, record.Message), 500),
+	}
+	return s.deliver(ctx, channels, template, payload)
+}
+
+// DeliverGroup 为每个渠道创建聚合通知的投递记录并立即发送
+func (s *NotificationDeliveryService) DeliverGroup(ctx context.Context, channels []models.NotificationChannelConfig, group *AlertGroup, maskIP bool) error {
+	recordIDs := make([]int64, 0, len(group.Items))
+	for _, item := range group.Items {
+		recordIDs = append(recordIDs, item.Record.ID)
+	}
+	title, _, _ := strings.Cut(s.notifier.buildGroupMessage(group, maskIP), "\n")
+
+	payload := &deliveryPayload{Group: group, MaskIP: maskIP}
+	template := models.NotificationDelivery{
+		RecordID:    recordIDs[0],
+		RecordIDs:   joinRecordIDs(recordIDs),
+		GroupKey:    group.Key,
+		AlertStatus: group.Status,
+		Level:       group.Items[0].Record.Level,
+		Summary:     truncateText(title, 500),
+	}
+	return s.deliver(ctx, channels, template, payload)
+}
+
+// deliver 持久化投递记录后依次发送，返回发送失败的渠道错误
+func (s *NotificationDeliveryService) deliver(ctx context.Context, channels []models.NotificationChannelConfig, template models.NotificationDelivery, payload *deliveryPayload) error {
+	// 调用方的超时只约束发送过程，投递记录的读写不随之取消
+	ctx = context.WithoutCancel(ctx)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		now := time.Now().UnixMilli()
+		delivery := template
+		delivery.ChannelType = channel.Type
+		delivery.Payload = datatypes.JSON(data)
+		delivery.Status = models.DeliveryStatusPending
+		delivery.NextRetryAt = now + deliveryLease.Milliseconds()
+		delivery.CreatedAt = now
+		if err := s.DeliveryRepo.CreateDelivery(ctx, &delivery); err != nil {
+			s.logger.Error("创建通知投递记录失败", zap.String("channelType", channel.Type), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := s.attempt(ctx, &delivery, &channel, payload); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// attempt 发送一次投递并记录结果，失败时按指数退避安排下次重试
+func (s *NotificationDeliveryService) attempt(ctx context.Context, delivery *models.NotificationDelivery, channel *models.NotificationChannelConfig, payload *deliveryPayload) error {
+	sendCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	var sendErr error
+	if payload.Group != nil {
+		sendErr = s.notifier.SendGroupNotificationByConfig(sendCtx, channel, payload.Group, payload.MaskIP)
+	} else {
+		sendErr = s.notifier.SendNotificationByConfig(sendCtx, channel, payload.Record, payload.Agent, payload.MaskIP)
+	}
+
+	s.recordResult(delivery, sendErr)
+	if err := s.DeliveryRepo.UpdateDelivery(ctx, delivery); err != nil {
+		s.logger.Error("更新通知投递记录失败", zap.Int64("deliveryId", delivery.ID), zap.Error(err))
+	}
+	return sendErr
+}
+
+// recordResult 根据发送结果更新投递记录的状态
+func (s *NotificationDeliveryService) recordResult(delivery *models.NotificationDelivery, sendErr error) {
+	now := time.Now()
+	delivery.Attempts++
+
+	if sendErr == nil {
+		delivery.Status = models.DeliveryStatusSent
+		delivery.LastError = ""
+		delivery.NextRetryAt = 0
+		delivery.DeliveredAt = now.UnixMilli()
+		return
+	}
+
+	delivery.LastError = truncateText(sendErr.Error(), 1000)
+	if delivery.Attempts >= maxDeliveryAttempts {
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.NextRetryAt = 0
+		s.logger.Error("通知投递失败，已达到最大重试次数",
+			zap.Int64("deliveryId", delivery.ID),
+			zap.String("channelType", delivery.ChannelType),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(sendErr),
+		)
+		return
+	}
+
+	delivery.Status = models.DeliveryStatusPending
+	delivery.NextRetryAt = now.Add(s.backoff.ForAttempt(float64(delivery.Attempts - 1))).UnixMilli()
+	s.logger.Warn("通知投递失败，等待重试",
+		zap.Int64("deliveryId", delivery.ID),
+		zap.String("channelType", delivery.ChannelType),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Int64("nextRetryAt", delivery.NextRetryAt),
+		zap.Error(sendErr),
+	)
+}
+
+// claim 领取投递记录，领取失败说明其他发送方已在处理该记录
+func (s *NotificationDeliveryService) claim(ctx context.Context, delivery *models.NotificationDelivery) (bool, error) {
+	return s.DeliveryRepo.Claim(ctx, delivery.ID, delivery.NextRetryAt, time.Now().Add(deliveryLease).UnixMilli())
+}
+
+// resend 使用当前的渠道配置重新发送已领取的投递记录
+func (s *NotificationDeliveryService) resend(ctx context.Context, delivery *models.NotificationDelivery) error {
+	// 旧的通知晚于新的通知送达会覆盖告警的最新状态（如事件管理平台重新打开已恢复的事件），不再发送
+	if reason := s.supersededReason(ctx, delivery); reason != "" {
+		delivery.Status = models.DeliveryStatusSuperseded
+		delivery.LastError = reason
+		delivery.NextRetryAt = 0
+		s.logger.Info("通知已被更新的通知取代，不再重试",
+			zap.Int64("deliveryId", delivery.ID),
+			zap.String("channelType", delivery.ChannelType),
+			zap.String("reason", reason),
+		)
+		if err := s.DeliveryRepo.UpdateDelivery(ctx, delivery); err != nil {
+			s.logger.Error("更新通知投递记录失败", zap.Int64("deliveryId", delivery.ID), zap.Error(err))
+		}
+		return nil
+	}
+
+	var payload deliveryPayload
+	err := json.Unmarshal(delivery.Payload, &payload)
+	if err == nil && payload.Record == nil && (payload.Group == nil || len(payload.Group.Items) == 0) {
+		err = fmt.Errorf("通知投递内容为空")
+	}
+
+	// 使用最新的渠道配置，渠道修复配置后重试即可送达
+	var channel *models.NotificationChannelConfig
+	if err == nil {
+		channel, err = s.findChannel(ctx, delivery.ChannelType)
+	}
+	if err != nil {
+		s.recordResult(delivery, err)
+		if err := s.DeliveryRepo.UpdateDelivery(ctx, delivery); err != nil {
+			s.logger.Error("更新通知投递记录失败", zap.Int64("deliveryId", delivery.ID), zap.Error(err))
+		}
+		return err
+	}
+	return s.attempt(ctx, delivery, channel, &payload)
+}
+
+// supersededReason 查询同一告警的更新通知和告警当前状态，返回投递记录被取代的原因，未被取代时返回空字符串
+func (s *NotificationDeliveryService) supersededReason(ctx context.Context, delivery *models.NotificationDelivery) string {
+	hasNewer, err := s.DeliveryRepo.ExistsNewer(ctx, delivery.RecordID, delivery.ChannelType, delivery.ID)
+	if err != nil {
+		// 查询失败时按原计划重试，避免丢失通知
+		s.logger.Warn("查询更新的通知投递记录失败", zap.Int64("deliveryId", delivery.ID), zap.Error(err))
+		return ""
+	}
+
+	var record *models.AlertRecord
+	if delivery.GroupKey == "" {
+		record, err = s.alertRecordRepo.GetAlertRecordByID(ctx, delivery.RecordID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("查询告警记录失败", zap.Int64("recordId", delivery.RecordID), zap.Error(err))
+		}
+	}
+	return deliverySupersededReason(delivery, hasNewer, record)
+}
+
+// deliverySupersededReason 判断投递记录是否已被取代:
+// 同一告警在同一渠道已有更新的投递记录，或单条通知对应的告警状态已经变化
+func deliverySupersededReason(delivery *models.NotificationDelivery, hasNewer bool, record *models.AlertRecord) string {
+	if hasNewer {
+		return "同一告警已有更新的通知"
+	}
+	if record == nil || delivery.GroupKey != "" {
+		return ""
+	}
+
+	status := delivery.AlertStatus
+	switch status {
+	case "flapping":
+		// 抖动通知发送时告警处于触发状态
+		status = "firing"
+	case "firing", "acknowledged", "resolved":
+	default:
+		// 其他通知（如 info 事件）与告警状态无关
+		return ""
+	}
+	if record.Status != status {
+		return fmt.Sprintf("告警状态已变为 %s", record.Status)
+	}
+	return ""
+}
+
+// findChannel 获取指定类型的通知渠道配置
+func (s *NotificationDeliveryService) findChannel(ctx context.Context, channelType string) (*models.NotificationChannelConfig, error) {
+	channels, err := s.propertyService.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range channels {
+		if channel.Type == channelType {
+			return &channel, nil
+		}
+	}
+	return nil, fmt.Errorf("通知渠道不存在: %s", channelType)
+}
+
+// Resend 立即重新发送投递记录，已送达或已失败的记录同样可以重发，已被更新通知取代的记录不再发送，发送结果记录在返回的投递记录中
+func (s *NotificationDeliveryService) Resend(ctx context.Context, id int64) (*models.NotificationDelivery, error) {
+	delivery, err := s.DeliveryRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.claim(ctx, &delivery)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, orz.NewError(400, "通知正在投递中，请稍后再试")
+	}
+
+	if err := s.resend(ctx, &delivery); err != nil {
+		s.logger.Warn("手动重发通知失败", zap.Int64("deliveryId", id), zap.Error(err))
+	}
+	return &delivery, nil
+}
+
+// Run 定时重试到期的投递记录
+func (s *NotificationDeliveryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("通知投递重试任务已启动")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("通知投递重试任务已停止")
+			return
+		case <-ticker.C:
+			s.retryDue(ctx)
+		}
+	}
+}
+
+// retryDue 重试到期的投递记录
+func (s *NotificationDeliveryService) retryDue(ctx context.Context) {
+	deliveries, err := s.DeliveryRepo.FindDue(ctx, time.Now().UnixMilli(), deliveryRetryBatch)
+	if err != nil {
+		s.logger.Error("查询待重试的通知投递失败", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		claimed, err := s.claim(ctx, &delivery)
+		if err != nil {
+			s.logger.Error("领取通知投递记录失败", zap.Int64("deliveryId", delivery.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		// 发送结果已记录在投递记录中
+		_ = s.resend(ctx, &delivery)
+	}
+}
+
+// joinRecordIDs 将告警记录ID拼接为 ,1,2,3, 格式，便于按单个记录ID模糊查询
+func joinRecordIDs(ids []int64) string {
+	var sb strings.Builder
+	sb.WriteString(",")
+	for _, id := range ids {
+		sb.WriteString(strconv.FormatInt(id, 10))
+		sb.WriteString(",")
+	}
+	return sb.String()
+}
+
+// RecordIDPattern 按告警记录ID查询投递记录时使用的匹配串
+func RecordIDPattern(recordID int64) string {
+	return joinRecordIDs([]int64{recordID})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestNotificationDeliveryRecordResult(t *testing.T) {
+	s := NewNotificationDeliveryService(zap.NewNop(), nil, nil, nil)
+	delivery := &models.NotificationDelivery{Status: models.DeliveryStatusPending}
+
+	before := time.Now().UnixMilli()
+	s.recordResult(delivery, errors.New("connection refused"))
+	if delivery.Status != models.DeliveryStatusPending || delivery.Attempts != 1 {
+		t.Fatalf("首次失败后应等待重试: status=%s attempts=%d", delivery.Status, delivery.Attempts)
+	}
+	if delivery.LastError != "connection refused" {
+		t.Errorf("失败原因未记录: %q", delivery.LastError)
+	}
+	// 首次重试间隔为 30s，带抖动
+	if delivery.NextRetryAt <= before || delivery.NextRetryAt > before+30_000+1_000 {
+		t.Errorf("重试时间不正确: %d", delivery.NextRetryAt-before)
+	}
+
+	s.recordResult(delivery, nil)
+	if delivery.Status != models.DeliveryStatusSent || delivery.DeliveredAt == 0 || delivery.NextRetryAt != 0 || delivery.LastError != "" {
+		t.Errorf("发送成功后状态不正确: %+v", delivery)
+	}
+
+	delivery.Attempts = maxDeliveryAttempts - 1
+	s.recordResult(delivery, errors.New("timeout"))
+	if delivery.Status != models.DeliveryStatusFailed || delivery.NextRetryAt != 0 {
+		t.Errorf("达到最大重试次数后应标记为失败: %+v", delivery)
+	}
+}
+
+func TestJoinRecordIDs(t *testing.T) {
+	if got := joinRecordIDs([]int64{1, 12, 3}); got != ",1,12,3," {
+		t.Errorf("joinRecordIDs = %q", got)
+	}
+	if got := RecordIDPattern(12); got != ",12," {
+		t.Errorf("RecordIDPattern = %q", got)
+	}
+}
+
+func TestDeliverySupersededFiringAfterResolved(t *testing.T) {
+	// 触发通知发送失败等待重试，随后的恢复通知已送达
+	firing := &models.NotificationDelivery{ID: 1, RecordID: 7, ChannelType: "pagerduty", AlertStatus: "firing", Status: models.DeliveryStatusPending, Attempts: 1}
+	resolvedRecord := &models.AlertRecord{ID: 7, Status: "resolved"}
+
+	tests := []struct {
+		name     string
+		delivery *models.NotificationDelivery
+		hasNewer bool
+		record   *models.AlertRecord
+		want     bool
+	}{
+		{name: "已有更新的恢复通知", delivery: firing, hasNewer: true, record: resolvedRecord, want: true},
+		{name: "告警已恢复但恢复通知尚未创建", delivery: firing, record: resolvedRecord, want: true},
+		{name: "告警仍在触发", delivery: firing, record: &models.AlertRecord{ID: 7, Status: "firing"}, want: false},
+		{name: "告警记录已删除", delivery: firing, want: false},
+		{name: "恢复通知自身重试", delivery: &models.NotificationDelivery{ID: 2, RecordID: 7, AlertStatus: "resolved"}, record: resolvedRecord, want: false},
+		{name: "抖动通知在告警触发时仍有效", delivery: &models.NotificationDelivery{ID: 3, RecordID: 7, AlertStatus: "flapping"}, record: &models.AlertRecord{ID: 7, Status: "firing"}, want: false},
+		{name: "info 事件与告警状态无关", delivery: &models.NotificationDelivery{ID: 4, RecordID: 8, AlertStatus: "info"}, record: &models.AlertRecord{ID: 8, Status: "resolved"}, want: false},
+		{name: "聚合通知不比较单条告警状态", delivery: &models.NotificationDelivery{ID: 5, RecordID: 7, GroupKey: "cpu", AlertStatus: "firing"}, record: resolvedRecord, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := deliverySupersededReason(tt.delivery, tt.hasNewer, tt.record)
+			if (reason != "") != tt.want {
+				t.Errorf("是否被取代 = %v（%q），期望 %v", reason != "", reason, tt.want)
+			}
+		})
+	}
+}
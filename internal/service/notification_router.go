@@ -0,0 +1,64 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// RouteResult 通知路由结果
+type RouteResult struct {
+	MatchedRoutes []string `json:"matchedRoutes"` // 命中的路由规则名称
+	Channels      []string `json:"channels"`      // 通知渠道类型，Fallback 为 true 时为 nil
+	Fallback      bool     `json:"fallback"`      // 未命中任何规则，发送到所有已启用渠道
+}
+
+// RouteNotification 按路由规则计算告警的通知渠道类型
+func RouteNotification(routes []models.NotificationRoute, record *models.AlertRecord, agent *models.Agent) RouteResult {
+	var result RouteResult
+	for i := range routes {
+		route := &routes[i]
+		if !route.Enabled || !matchNotificationRoute(route, record, agent) {
+			continue
+		}
+
+		result.MatchedRoutes = append(result.MatchedRoutes, route.Name)
+		for _, channel := range route.Channels {
+			if !slices.Contains(result.Channels, channel) {
+				result.Channels = append(result.Channels, channel)
+			}
+		}
+		if !route.Continue {
+			break
+		}
+	}
+
+	if len(result.MatchedRoutes) == 0 {
+		result.Fallback = true
+	} else if result.Channels == nil {
+		// 命中的规则未配置渠道，表示丢弃该告警的通知
+		result.Channels = []string{}
+	}
+	return result
+}
+
+// matchNotificationRoute 判断告警是否命中路由规则
+func matchNotificationRoute(route *models.NotificationRoute, record *models.AlertRecord, agent *models.Agent) bool {
+	if len(route.AlertTypes) > 0 && !slices.Contains(route.AlertTypes, record.AlertType) {
+		return false
+	}
+	if len(route.Levels) > 0 && !slices.Contains(route.Levels, record.Level) {
+		return false
+	}
+	if len(route.AgentIds) > 0 && !slices.Contains(route.AgentIds, record.AgentID) {
+		return false
+	}
+	if len(route.Tags) > 0 {
+		if agent == nil || !slices.ContainsFunc(route.Tags, func(tag string) bool {
+			return slices.Contains(agent.Tags, tag)
+		}) {
+			return false
+		}
+	}
+	return true
+}
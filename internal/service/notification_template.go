@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/valyala/fasttemplate"
+)
+
+// templateVariable 获取通知模板变量的值，消息模板与自定义 Webhook 共用同一套变量
+func templateVariable(agent *models.Agent, record *models.AlertRecord, message, tag string) (string, bool) {
+	switch tag {
+	case "message":
+		return message, true
+	case "agent.id":
+		return agent.ID, true
+	case "agent.name":
+		return agent.Name, true
+	case "agent.hostname":
+		return agent.Hostname, true
+	case "agent.ip":
+		return agent.IP, true
+	case "alert.type":
+		return record.AlertType, true
+	case "alert.level":
+		return record.Level, true
+	case "alert.status":
+		return record.Status, true
+	case "alert.message":
+		return record.Message, true
+	case "alert.threshold":
+		return fmt.Sprintf("%.2f", record.Threshold), true
+	case "alert.actualValue":
+		return fmt.Sprintf("%.2f", record.ActualValue), true
+	case "alert.firedAt":
+		// 格式化的触发时间 (使用系统时区，Docker 中设置为 Asia/Shanghai)
+		return utils.FormatTimestamp(record.FiredAt), true
+	case "alert.resolvedAt":
+		// 格式化的恢复时间 (使用系统时区，Docker 中设置为 Asia/Shanghai)
+		return utils.FormatTimestamp(record.ResolvedAt), true
+	case "alert.acknowledgedBy":
+		return record.AcknowledgedBy, true
+	case "alert.assignee":
+		return record.Assignee, true
+	default:
+		return "", false
+	}
+}
+
+// RenderMessageTemplate 渲染通知消息模板，未知变量原样保留
+func RenderMessageTemplate(template string, agent *models.Agent, record *models.AlertRecord, message string) string {
+	t := fasttemplate.New(template, "{{", "}}")
+	return t.ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
+		v, ok := templateVariable(agent, record, message, tag)
+		if !ok {
+			return w.Write([]byte("{{" + tag + "}}"))
+		}
+		return w.Write([]byte(v))
+	})
+}
+
+// FindNotificationTemplate 查找告警适用的消息模板，告警类型精确匹配优先，没有可用模板时返回空字符串
+func FindNotificationTemplate(templates []models.NotificationTemplate, alertType, status string) string {
+	var fallback string
+	for _, tpl := range templates {
+		var content string
+		switch status {
+		case "firing":
+			content = tpl.Firing
+		case "resolved":
+			content = tpl.Resolved
+		}
+		if content == "" {
+			continue
+		}
+		if tpl.AlertType == alertType {
+			return content
+		}
+		if tpl.AlertType == "" && fallback == "" {
+			fallback = content
+		}
+	}
+	return fallback
+}
+
+// buildChannelMessage 构建发送到指定渠道的消息，渠道配置了模板时按模板渲染
+func (n *Notifier) buildChannelMessage(channelConfig *models.NotificationChannelConfig, agent *models.Agent, record *models.AlertRecord, maskIP bool) string {
+	message := n.buildMessage(agent, record, maskIP)
+
+	template := FindNotificationTemplate(channelConfig.Templates, record.AlertType, record.Status)
+	if template == "" {
+		return message
+	}
+
+	displayAgent := *agent
+	if maskIP {
+		displayAgent.IP = maskIPAddress(agent.IP)
+	}
+	return RenderMessageTemplate(template, &displayAgent, record, message)
+}
+
+// PreviewTemplate 使用样例告警渲染消息模板，template 为空时返回内置消息
+func (n *Notifier) PreviewTemplate(template, alertType, status string) string {
+	agent, record := sampleAlert(alertType, status)
+	message := n.buildMessage(agent, record, false)
+	if template == "" {
+		return message
+	}
+	return RenderMessageTemplate(template, agent, record, message)
+}
+
+// sampleAlert 构造用于模板预览的样例探针和告警记录
+func sampleAlert(alertType, status string) (*models.Agent, *models.AlertRecord) {
+	if alertType == "" {
+		alertType = "cpu"
+	}
+	if status == "" {
+		status = "firing"
+	}
+
+	agent := &models.Agent{
+		ID:       "sample-agent",
+		Name:     i18n.T("notify.sample_agent"),
+		Hostname: "sample-host",
+		IP:       "192.168.1.100",
+	}
+
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   alertType,
+		Message:     i18n.T("alert.msg.metric", getAlertTypeMetadata(alertType).Name, "300", "80.00", "92.50"),
+		Threshold:   80,
+		ActualValue: 92.5,
+		Level:       "warning",
+		Status:      status,
+		FiredAt:     now - 10*time.Minute.Milliseconds(),
+	}
+	switch status {
+	case "acknowledged":
+		record.AcknowledgedBy = "admin"
+		record.AcknowledgedAt = now - 5*time.Minute.Milliseconds()
+		record.Assignee = "admin"
+	case "resolved":
+		record.ActualValue = 45
+		record.ResolvedAt = now
+	}
+	return agent, record
+}
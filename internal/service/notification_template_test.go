@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestFindNotificationTemplate(t *testing.T) {
+	templates := []models.NotificationTemplate{
+		{AlertType: "", Firing: "any firing"},
+		{AlertType: "cpu", Firing: "cpu firing", Resolved: "cpu resolved"},
+		{AlertType: "", Firing: "second any firing", Resolved: "any resolved"},
+		{AlertType: "memory", Resolved: "memory resolved"},
+	}
+
+	tests := []struct {
+		name      string
+		alertType string
+		status    string
+		want      string
+	}{
+		{name: "告警类型精确匹配优先于通用模板", alertType: "cpu", status: "firing", want: "cpu firing"},
+		{name: "精确匹配恢复模板", alertType: "cpu", status: "resolved", want: "cpu resolved"},
+		{name: "未匹配类型时使用第一个通用模板", alertType: "disk", status: "firing", want: "any firing"},
+		{name: "跳过该状态为空的通用模板", alertType: "disk", status: "resolved", want: "any resolved"},
+		{name: "精确匹配的模板缺少该状态时回退到通用模板", alertType: "memory", status: "firing", want: "any firing"},
+		{name: "精确匹配的恢复模板", alertType: "memory", status: "resolved", want: "memory resolved"},
+		{name: "其他状态没有模板", alertType: "cpu", status: "acknowledged", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindNotificationTemplate(templates, tt.alertType, tt.status); got != tt.want {
+				t.Errorf("FindNotificationTemplate(%q, %q) = %q，期望 %q", tt.alertType, tt.status, got, tt.want)
+			}
+		})
+	}
+
+	if got := FindNotificationTemplate(nil, "cpu", "firing"); got != "" {
+		t.Errorf("没有模板时应返回空字符串，实际 %q", got)
+	}
+}
+
+func TestPreviewTemplateAcknowledged(t *testing.T) {
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+
+	if got := notifier.PreviewTemplate("{{alert.status}} by {{alert.acknowledgedBy}}", "cpu", "acknowledged"); got != "acknowledged by admin" {
+		t.Errorf("确认状态的模板预览 = %q", got)
+	}
+	// 内置消息包含确认人
+	if got := notifier.PreviewTemplate("", "cpu", "acknowledged"); !strings.Contains(got, "admin") {
+		t.Errorf("确认状态的内置消息应包含确认人: %q", got)
+	}
+}
@@ -16,12 +16,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/utils"
 	"github.com/go-orz/cache"
 	"github.com/valyala/fasttemplate"
 	"go.uber.org/zap"
-	"gopkg.in/gomail.v2"
 )
 
 // AlertTypeMetadata 告警类型元数据
@@ -48,6 +49,26 @@ var alertTypeMetadataMap = map[string]AlertTypeMetadata{
 		ThresholdUnit: "%",
 		ValueUnit:     "%",
 	},
+	"load": {
+		Name:          "负载告警",
+		ThresholdUnit: "",
+		ValueUnit:     "",
+	},
+	"swap": {
+		Name:          "Swap告警",
+		ThresholdUnit: "%",
+		ValueUnit:     "%",
+	},
+	"steal": {
+		Name:          "CPU Steal告警",
+		ThresholdUnit: "%",
+		ValueUnit:     "%",
+	},
+	"inode": {
+		Name:          "Inode告警",
+		ThresholdUnit: "%",
+		ValueUnit:     "%",
+	},
 	"network": {
 		Name:          "网络告警",
 		ThresholdUnit: "MB/s",
@@ -68,6 +89,46 @@ var alertTypeMetadataMap = map[string]AlertTypeMetadata{
 		ThresholdUnit: "秒",
 		ValueUnit:     "秒",
 	},
+	"tamper_event": {
+		Name:          "防篡改事件告警",
+		ThresholdUnit: "次",
+		ValueUnit:     "次",
+	},
+	"tamper_attr": {
+		Name:          "防篡改属性告警",
+		ThresholdUnit: "次",
+		ValueUnit:     "次",
+	},
+	"ddns_failure": {
+		Name:          "DDNS更新失败告警",
+		ThresholdUnit: "次",
+		ValueUnit:     "次",
+	},
+	"ddns_ip_change": {
+		Name:          "公网IP变化通知",
+		ThresholdUnit: "",
+		ValueUnit:     "",
+	},
+	"anomaly": {
+		Name:          "异常检测告警",
+		ThresholdUnit: "σ",
+		ValueUnit:     "σ",
+	},
+	"disk_forecast": {
+		Name:          "磁盘写满预测告警",
+		ThresholdUnit: "小时",
+		ValueUnit:     "小时",
+	},
+	"expression": {
+		Name:          "表达式告警",
+		ThresholdUnit: "",
+		ValueUnit:     "",
+	},
+	"external": {
+		Name:          "外部告警",
+		ThresholdUnit: "",
+		ValueUnit:     "",
+	},
 }
 
 // 告警级别图标映射
@@ -79,13 +140,18 @@ var levelIconMap = map[string]string{
 
 // Notifier 告警通知服务
 type Notifier struct {
-	logger *zap.Logger
+	logger        *zap.Logger
+	execScriptDir string // exec 渠道允许执行的脚本目录，为空时禁用 exec 渠道
 }
 
-func NewNotifier(logger *zap.Logger) *Notifier {
-	return &Notifier{
+func NewNotifier(logger *zap.Logger, appCfg *config.AppConfig) *Notifier {
+	n := &Notifier{
 		logger: logger,
 	}
+	if appCfg.Notification != nil {
+		n.execScriptDir = appCfg.Notification.ExecScriptDir
+	}
+	return n
 }
 
 // maskIPAddress 打码 IP 地址 (例如: 192.168.1.100 -> 192.168.*.*）
@@ -104,15 +170,27 @@ func maskIPAddress(ip string) string {
 
 // getAlertTypeMetadata 获取告警类型元数据，如果不存在则返回默认值
 func getAlertTypeMetadata(alertType string) AlertTypeMetadata {
-	if metadata, ok := alertTypeMetadataMap[alertType]; ok {
-		return metadata
+	metadata, ok := alertTypeMetadataMap[alertType]
+	if !ok {
+		// 返回默认值
+		metadata = AlertTypeMetadata{
+			Name:          i18n.T("alert_type.unknown"),
+			ThresholdUnit: "",
+			ValueUnit:     "",
+		}
 	}
-	// 返回默认值
-	return AlertTypeMetadata{
-		Name:          "未知告警",
-		ThresholdUnit: "",
-		ValueUnit:     "",
+
+	// 非中文语言使用消息目录中的名称和单位，映射表中的中文作为默认值
+	if name, ok := i18n.Lookup("alert_type." + alertType); ok {
+		metadata.Name = name
 	}
+	if unit, ok := i18n.Lookup("unit." + metadata.ThresholdUnit); ok {
+		metadata.ThresholdUnit = unit
+	}
+	if unit, ok := i18n.Lookup("unit." + metadata.ValueUnit); ok {
+		metadata.ValueUnit = unit
+	}
+	return metadata
 }
 
 // getLevelIcon 获取告警级别图标，如果不存在则返回默认值
@@ -141,11 +219,17 @@ func (n *Notifier) buildMessage(agent *models.Agent, record *models.AlertRecord,
 	switch record.Status {
 	case "firing":
 		return n.buildFiringMessage(agent, record, displayIP, levelIcon, metadata)
+	case "acknowledged":
+		return n.buildAcknowledgedMessage(agent, record, displayIP, metadata)
 	case "resolved":
 		return n.buildResolvedMessage(agent, record, displayIP, metadata)
+	case "flapping":
+		return n.buildFlappingMessage(agent, record, displayIP, metadata)
+	case "info":
+		return n.buildInfoMessage(agent, record, displayIP, metadata)
 	default:
 		// 未知状态，返回基本信息
-		return fmt.Sprintf("⚠️ 未知告警状态: %s\n探针: %s (%s)", record.Status, agent.Name, agent.ID)
+		return i18n.T("notify.unknown_status", record.Status, agent.Name, agent.ID)
 	}
 }
 
@@ -157,16 +241,7 @@ func (n *Notifier) buildFiringMessage(
 	levelIcon string,
 	metadata AlertTypeMetadata,
 ) string {
-	return fmt.Sprintf(
-		"%s %s\n\n"+
-			"探针: %s (%s)\n"+
-			"主机: %s\n"+
-			"IP: %s\n"+
-			"告警类型: %s\n"+
-			"告警消息: %s\n"+
-			"阈值: %.2f%s\n"+
-			"当前值: %.2f%s\n"+
-			"触发时间: %s",
+	message := i18n.T("notify.firing",
 		levelIcon,
 		metadata.Name,
 		agent.Name,
@@ -175,10 +250,73 @@ func (n *Notifier) buildFiringMessage(
 		displayIP,
 		record.AlertType,
 		record.Message,
-		record.Threshold,
-		metadata.ThresholdUnit,
-		record.ActualValue,
-		metadata.ValueUnit,
+		fmt.Sprintf("%.2f%s", record.Threshold, metadata.ThresholdUnit),
+		fmt.Sprintf("%.2f%s", record.ActualValue, metadata.ValueUnit),
+		utils.FormatTimestamp(record.FiredAt),
+	)
+	if record.NotifyCount > 0 {
+		// 升级策略的重复提醒
+		message += i18n.T("notify.notify_count", strconv.Itoa(record.NotifyCount+1))
+	}
+	return message
+}
+
+// buildAcknowledgedMessage 构建告警确认消息
+func (n *Notifier) buildAcknowledgedMessage(
+	agent *models.Agent,
+	record *models.AlertRecord,
+	displayIP string,
+	metadata AlertTypeMetadata,
+) string {
+	return i18n.T("notify.acknowledged",
+		metadata.Name,
+		agent.Name,
+		agent.ID,
+		agent.Hostname,
+		displayIP,
+		record.AlertType,
+		record.Message,
+		record.AcknowledgedBy,
+		record.Assignee,
+		utils.FormatTimestamp(record.FiredAt),
+		utils.FormatTimestamp(record.AcknowledgedAt),
+	)
+}
+
+// buildFlappingMessage 构建告警抖动消息
+func (n *Notifier) buildFlappingMessage(
+	agent *models.Agent,
+	record *models.AlertRecord,
+	displayIP string,
+	metadata AlertTypeMetadata,
+) string {
+	return i18n.T("notify.flapping",
+		metadata.Name,
+		agent.Name,
+		agent.ID,
+		agent.Hostname,
+		displayIP,
+		record.AlertType,
+		record.Message,
+		fmt.Sprintf("%.2f%s", record.ActualValue, metadata.ValueUnit),
+		utils.FormatTimestamp(time.Now().UnixMilli()),
+	)
+}
+
+// buildInfoMessage 构建通知类消息，如公网 IP 变化
+func (n *Notifier) buildInfoMessage(
+	agent *models.Agent,
+	record *models.AlertRecord,
+	displayIP string,
+	metadata AlertTypeMetadata,
+) string {
+	return i18n.T("notify.info",
+		metadata.Name,
+		agent.Name,
+		agent.ID,
+		agent.Hostname,
+		displayIP,
+		record.Message,
 		utils.FormatTimestamp(record.FiredAt),
 	)
 }
@@ -197,26 +335,21 @@ func (n *Notifier) buildResolvedMessage(
 		durationStr = utils.FormatDuration(durationMs)
 	}
 
-	return fmt.Sprintf(
-		"✅ %s已恢复\n\n"+
-			"探针: %s (%s)\n"+
-			"主机: %s\n"+
-			"IP: %s\n"+
-			"告警类型: %s\n"+
-			"当前值: %.2f%s\n"+
-			"持续时间: %s\n"+
-			"恢复时间: %s",
+	message := i18n.T("notify.resolved",
 		metadata.Name,
 		agent.Name,
 		agent.ID,
 		agent.Hostname,
 		displayIP,
 		record.AlertType,
-		record.ActualValue,
-		metadata.ValueUnit,
+		fmt.Sprintf("%.2f%s", record.ActualValue, metadata.ValueUnit),
 		durationStr,
 		utils.FormatTimestamp(record.ResolvedAt),
 	)
+	if record.AcknowledgedBy != "" {
+		message += i18n.T("notify.acknowledged_by", record.AcknowledgedBy)
+	}
+	return message
 }
 
 // sendDingTalk 发送钉钉通知
@@ -281,6 +414,7 @@ func (n *Notifier) sendWeCom(ctx context.Context, webhook, message string) error
 }
 
 var wecomAppAccessTokenCache = cache.New[string, string](time.Minute)
+
 func (n *Notifier) getWecomAppToken(ctx context.Context, origin, corpId, corpSecret string) (string, error) {
 	key := fmt.Sprintf("%s#%s", corpId, corpSecret)
 	if token, found := wecomAppAccessTokenCache.Get(key); found {
@@ -395,81 +529,62 @@ func (n *Notifier) sendTelegram(ctx context.Context, botToken, chatID, message s
 	return nil
 }
 
-// sendEmail 发送邮件通知
-func (n *Notifier) sendEmail(ctx context.Context, smtpHost string, smtpPort int, fromEmail, password, toEmail, subject, message string) error {
-	// 创建邮件消息
-	m := gomail.NewMessage()
-	m.SetHeader("From", fromEmail)
-	m.SetHeader("To", toEmail)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/plain", message)
-
-	// 创建 SMTP 拨号器
-	d := gomail.NewDialer(smtpHost, smtpPort, fromEmail, password)
-
-	// 发送邮件
-	if err := d.DialAndSend(m); err != nil {
-		return fmt.Errorf("发送邮件失败: %w", err)
-	}
-
-	n.logger.Info("邮件发送成功",
-		zap.String("from", fromEmail),
-		zap.String("to", toEmail),
-		zap.String("subject", subject),
-	)
-
-	return nil
+// webhookChannel 自定义 Webhook 渠道
+type webhookChannel struct {
+	URL          string            `json:"url"`
+	Method       string            `json:"method"`       // 请求方法，默认 POST
+	Headers      map[string]string `json:"headers"`      // 自定义请求头
+	BodyTemplate string            `json:"bodyTemplate"` // 请求体模板: json(默认), form, custom
+	CustomBody   string            `json:"customBody"`   // bodyTemplate 为 custom 时使用，支持变量替换
 }
 
-// webhookConfig Webhook 配置
-type webhookConfig struct {
-	URL          string
-	Method       string
-	Headers      map[string]string
-	BodyTemplate string
-	CustomBody   string
-}
-
-// parseWebhookConfig 解析 Webhook 配置
-func parseWebhookConfig(config map[string]interface{}) (*webhookConfig, error) {
-	// 解析 URL
-	webhookURL, ok := config["url"].(string)
-	if !ok || webhookURL == "" {
-		return nil, fmt.Errorf("自定义Webhook配置缺少 url")
+func (c *webhookChannel) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("自定义Webhook配置缺少 url")
 	}
-
-	// 获取请求方法，默认 POST
-	method := "POST"
-	if m, ok := config["method"].(string); ok && m != "" {
-		method = strings.ToUpper(m)
+	switch c.bodyTemplate() {
+	case "json", "form":
+		return nil
+	case "custom":
+		if c.CustomBody == "" {
+			return fmt.Errorf("使用 custom 模板时必须提供 customBody")
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的 bodyTemplate: %s", c.BodyTemplate)
 	}
+}
 
-	// 获取自定义请求头
-	headers := make(map[string]string)
-	if h, ok := config["headers"].(map[string]interface{}); ok {
-		for k, v := range h {
-			if strVal, ok := v.(string); ok {
-				headers[k] = strVal
-			}
-		}
+// method 请求方法，默认 POST
+func (c *webhookChannel) method() string {
+	if c.Method == "" {
+		return http.MethodPost
 	}
+	return strings.ToUpper(c.Method)
+}
 
-	// 获取请求体模板类型，默认 json
-	bodyTemplate := "json"
-	if bt, ok := config["bodyTemplate"].(string); ok && bt != "" {
-		bodyTemplate = bt
+// bodyTemplate 请求体模板类型，默认 json
+func (c *webhookChannel) bodyTemplate() string {
+	if c.BodyTemplate == "" {
+		return "json"
 	}
+	return c.BodyTemplate
+}
 
-	// 获取自定义请求体
-	customBody, _ := config["customBody"].(string)
+func (c *webhookChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	message := n.buildChannelMessage(notification.Channel, notification.Agent, notification.Record, notification.MaskIP)
+	return n.sendCustomWebhook(ctx, c, notification.Agent, notification.Record, message)
+}
+
+func (c *webhookChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	message := n.buildGroupMessage(notification.Group, notification.MaskIP)
+	return n.sendGroupWebhook(ctx, c, notification.Group, message)
+}
 
-	return &webhookConfig{
-		URL:          webhookURL,
-		Method:       method,
-		Headers:      headers,
-		BodyTemplate: bodyTemplate,
-		CustomBody:   customBody,
-	}, nil
+// Test Webhook 需要 agent 和 record，使用测试数据构建完整的告警消息
+func (c *webhookChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendCustomWebhook(ctx, c, agent, record, n.buildMessage(agent, record, false))
 }
 
 // buildJSONBody 构建 JSON 格式的请求体
@@ -486,14 +601,17 @@ func (n *Notifier) buildJSONBody(agent *models.Agent, record *models.AlertRecord
 			"ip":       agent.IP,
 		},
 		"alert": map[string]interface{}{
-			"type":        record.AlertType,
-			"level":       record.Level,
-			"status":      record.Status,
-			"message":     record.Message,
-			"threshold":   record.Threshold,
-			"actualValue": record.ActualValue,
-			"firedAt":     record.FiredAt,
-			"resolvedAt":  record.ResolvedAt,
+			"type":           record.AlertType,
+			"level":          record.Level,
+			"status":         record.Status,
+			"message":        record.Message,
+			"threshold":      record.Threshold,
+			"actualValue":    record.ActualValue,
+			"firedAt":        record.FiredAt,
+			"resolvedAt":     record.ResolvedAt,
+			"acknowledgedBy": record.AcknowledgedBy,
+			"acknowledgedAt": record.AcknowledgedAt,
+			"assignee":       record.Assignee,
 		},
 	}
 	data, err := json.Marshal(body)
@@ -521,6 +639,13 @@ func (n *Notifier) buildFormBody(agent *models.Agent, record *models.AlertRecord
 	if record.ResolvedAt > 0 {
 		formData.Set("resolved_at", fmt.Sprintf("%d", record.ResolvedAt))
 	}
+	if record.AcknowledgedBy != "" {
+		formData.Set("acknowledged_by", record.AcknowledgedBy)
+		formData.Set("acknowledged_at", fmt.Sprintf("%d", record.AcknowledgedAt))
+	}
+	if record.Assignee != "" {
+		formData.Set("assignee", record.Assignee)
+	}
 	return strings.NewReader(formData.Encode())
 }
 
@@ -540,38 +665,8 @@ func (n *Notifier) buildCustomBody(agent *models.Agent, record *models.AlertReco
 	}
 
 	bodyStr := t.ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
-		var v string
-
-		switch tag {
-		case "message":
-			v = message
-		case "agent.id":
-			v = agent.ID
-		case "agent.name":
-			v = agent.Name
-		case "agent.hostname":
-			v = agent.Hostname
-		case "agent.ip":
-			v = agent.IP
-		case "alert.type":
-			v = record.AlertType
-		case "alert.level":
-			v = record.Level
-		case "alert.status":
-			v = record.Status
-		case "alert.message":
-			v = record.Message
-		case "alert.threshold":
-			v = fmt.Sprintf("%.2f", record.Threshold)
-		case "alert.actualValue":
-			v = fmt.Sprintf("%.2f", record.ActualValue)
-		case "alert.firedAt":
-			// 格式化的触发时间 (使用系统时区，Docker 中设置为 Asia/Shanghai)
-			v = utils.FormatTimestamp(record.FiredAt)
-		case "alert.resolvedAt":
-			// 格式化的恢复时间 (使用系统时区，Docker 中设置为 Asia/Shanghai)
-			v = utils.FormatTimestamp(record.ResolvedAt)
-		default:
+		v, ok := templateVariable(agent, record, message, tag)
+		if !ok {
 			return w.Write([]byte("{{" + tag + "}}"))
 		}
 
@@ -627,21 +722,13 @@ func (n *Notifier) sendHTTPRequest(ctx context.Context, method, webhookURL strin
 }
 
 // sendCustomWebhook 发送自定义Webhook
-func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord, maskIP bool) error {
-	// 解析配置
-	cfg, err := parseWebhookConfig(config)
-	if err != nil {
-		return err
-	}
-
-	// 构建消息内容
-	message := n.buildMessage(agent, record, maskIP)
-
+func (n *Notifier) sendCustomWebhook(ctx context.Context, cfg *webhookChannel, agent *models.Agent, record *models.AlertRecord, message string) error {
 	// 根据模板类型构建请求体
 	var reqBody io.Reader
 	var contentType string
+	var err error
 
-	switch cfg.BodyTemplate {
+	switch cfg.bodyTemplate() {
 	case "json":
 		reqBody, err = n.buildJSONBody(agent, record, message)
 		if err != nil {
@@ -665,22 +752,30 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 	}
 
 	// 发送 HTTP 请求
-	return n.sendHTTPRequest(ctx, cfg.Method, cfg.URL, reqBody, cfg.Headers, contentType)
+	return n.sendHTTPRequest(ctx, cfg.method(), cfg.URL, reqBody, cfg.Headers, contentType)
 }
 
 // sendJSONRequest 发送JSON请求
 func (n *Notifier) sendJSONRequest(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	return n.doJSONRequest(ctx, http.MethodPost, url, nil, body)
+}
+
+// doJSONRequest 使用指定方法和请求头发送JSON请求
+func (n *Notifier) doJSONRequest(ctx context.Context, method, url string, headers map[string]string, body interface{}) ([]byte, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求体失败: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	client := &http.Client{
 		Timeout: 10 * time.Second,
@@ -703,234 +798,279 @@ func (n *Notifier) sendJSONRequest(ctx context.Context, url string, body interfa
 	return respBody, nil
 }
 
-// sendDingTalkByConfig 根据配置发送钉钉通知
-func (n *Notifier) sendDingTalkByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	secretKey, ok := config["secretKey"].(string)
-	if !ok || secretKey == "" {
+// dingTalkChannel 钉钉机器人渠道
+type dingTalkChannel struct {
+	SecretKey  string `json:"secretKey"`  // 机器人 access_token
+	SignSecret string `json:"signSecret"` // 加签密钥，可选
+}
+
+func (c *dingTalkChannel) Validate() error {
+	if c.SecretKey == "" {
 		return fmt.Errorf("钉钉配置缺少 secretKey")
 	}
+	return nil
+}
 
-	// 构造 Webhook URL
-	webhook := fmt.Sprintf("https://oapi.dingtalk.com/robot/send?access_token=%s", secretKey)
-
-	// 检查是否有加签密钥
-	signSecret, _ := config["signSecret"].(string)
+func (c *dingTalkChannel) sendText(ctx context.Context, n *Notifier, message string) error {
+	webhook := fmt.Sprintf("https://oapi.dingtalk.com/robot/send?access_token=%s", c.SecretKey)
+	return n.sendDingTalk(ctx, webhook, c.SignSecret, message)
+}
 
-	return n.sendDingTalk(ctx, webhook, signSecret, message)
+// weComChannel 企业微信群机器人渠道
+type weComChannel struct {
+	SecretKey string `json:"secretKey"` // 机器人 key
 }
 
-// sendWeComByConfig 根据配置发送企业微信通知
-func (n *Notifier) sendWeComByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	secretKey, ok := config["secretKey"].(string)
-	if !ok || secretKey == "" {
+func (c *weComChannel) Validate() error {
+	if c.SecretKey == "" {
 		return fmt.Errorf("企业微信配置缺少 secretKey")
 	}
+	return nil
+}
 
-	// 构造 Webhook URL
-	webhook := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=%s", secretKey)
-
+func (c *weComChannel) sendText(ctx context.Context, n *Notifier, message string) error {
+	webhook := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=%s", c.SecretKey)
 	return n.sendWeCom(ctx, webhook, message)
 }
 
-// sendWeComAppByConfig 根据配置发送企业微信应用通知
-func (n *Notifier) sendWeComAppByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	origin := "https://qyapi.weixin.qq.com"
-	if v, ok := config["origin"].(string); ok && v != "" {
-		origin = v
-	}
-
-	toUser := "@all"
-	if v, ok := config["toUser"].(string); ok && v != "" {
-		toUser = v
-	}
+// weComAppChannel 企业微信应用消息渠道
+type weComAppChannel struct {
+	Origin     string    `json:"origin"`     // 接口地址，默认 https://qyapi.weixin.qq.com
+	CorpID     string    `json:"corpId"`     // 企业ID
+	CorpSecret string    `json:"corpSecret"` // 应用 Secret
+	AgentID    configInt `json:"agentId"`    // 应用 AgentId
+	ToUser     string    `json:"toUser"`     // 接收人，默认 @all
+}
 
-	corpId, ok := config["corpId"].(string)
-	if !ok || corpId == "" {
+func (c *weComAppChannel) Validate() error {
+	if c.CorpID == "" {
 		return fmt.Errorf("企业微信应用配置缺少 corpid")
 	}
-
-	corpSecret, ok := config["corpSecret"].(string)
-	if !ok || corpSecret == "" {
+	if c.CorpSecret == "" {
 		return fmt.Errorf("企业微信应用配置缺少 corpsecret")
 	}
-
-	agentIdf, ok := config["agentId"].(float64)
-	if !ok || agentIdf <= 0 {
+	if c.AgentID <= 0 {
 		return fmt.Errorf("企业微信应用配置缺少 agentid")
 	}
+	return nil
+}
+
+func (c *weComAppChannel) sendText(ctx context.Context, n *Notifier, message string) error {
+	origin := c.Origin
+	if origin == "" {
+		origin = "https://qyapi.weixin.qq.com"
+	}
+	toUser := c.ToUser
+	if toUser == "" {
+		toUser = "@all"
+	}
+	return n.sendWeComApp(ctx, origin, c.CorpID, c.CorpSecret, int(c.AgentID), toUser, message)
+}
 
-	return n.sendWeComApp(ctx, origin, corpId, corpSecret, int(agentIdf), toUser, message)
+// feishuChannel 飞书机器人渠道
+type feishuChannel struct {
+	SecretKey string `json:"secretKey"` // 机器人 hook ID
 }
 
-// sendFeishuByConfig 根据配置发送飞书通知
-func (n *Notifier) sendFeishuByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	secretKey, ok := config["secretKey"].(string)
-	if !ok || secretKey == "" {
+func (c *feishuChannel) Validate() error {
+	if c.SecretKey == "" {
 		return fmt.Errorf("飞书配置缺少 secretKey")
 	}
+	return nil
+}
 
-	// 构造 Webhook URL
-	webhook := fmt.Sprintf("https://open.feishu.cn/open-apis/bot/v2/hook/%s", secretKey)
-
+func (c *feishuChannel) sendText(ctx context.Context, n *Notifier, message string) error {
+	webhook := fmt.Sprintf("https://open.feishu.cn/open-apis/bot/v2/hook/%s", c.SecretKey)
 	return n.sendFeishu(ctx, webhook, message)
 }
 
-// sendTelegramByConfig 根据配置发送 Telegram 通知
-func (n *Notifier) sendTelegramByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	botToken, ok := config["botToken"].(string)
-	if !ok || botToken == "" {
+// telegramChannel Telegram 机器人渠道
+type telegramChannel struct {
+	BotToken string `json:"botToken"`
+	ChatID   string `json:"chatID"`
+}
+
+func (c *telegramChannel) Validate() error {
+	if c.BotToken == "" {
 		return fmt.Errorf("Telegram 配置缺少 botToken")
 	}
-
-	chatID, ok := config["chatID"].(string)
-	if !ok || chatID == "" {
+	if c.ChatID == "" {
 		return fmt.Errorf("Telegram 配置缺少 chatID")
 	}
-
-	return n.sendTelegram(ctx, botToken, chatID, message)
+	return nil
 }
 
-// sendEmailByConfig 根据配置发送邮件通知
-func (n *Notifier) sendEmailByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	smtpHost, ok := config["smtpHost"].(string)
-	if !ok || smtpHost == "" {
-		return fmt.Errorf("邮件配置缺少 smtpHost")
-	}
-
-	// 端口可能是 float64 或 string
-	var smtpPort int
-	switch v := config["smtpPort"].(type) {
-	case float64:
-		smtpPort = int(v)
-	case string:
-		port, err := strconv.Atoi(v)
-		if err != nil {
-			return fmt.Errorf("邮件配置 smtpPort 格式错误: %w", err)
-		}
-		smtpPort = port
-	default:
-		return fmt.Errorf("邮件配置缺少 smtpPort")
-	}
-
-	fromEmail, ok := config["fromEmail"].(string)
-	if !ok || fromEmail == "" {
-		return fmt.Errorf("邮件配置缺少 fromEmail")
-	}
+func (c *telegramChannel) sendText(ctx context.Context, n *Notifier, message string) error {
+	return n.sendTelegram(ctx, c.BotToken, c.ChatID, message)
+}
 
-	password, ok := config["password"].(string)
-	if !ok || password == "" {
-		return fmt.Errorf("邮件配置缺少 password")
+// SendNotificationByConfig 根据渠道配置发送单条告警通知
+func (n *Notifier) SendNotificationByConfig(ctx context.Context, channelConfig *models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent, maskIP bool) error {
+	if !channelConfig.Enabled {
+		return fmt.Errorf("通知渠道已禁用")
 	}
 
-	toEmail, ok := config["toEmail"].(string)
-	if !ok || toEmail == "" {
-		return fmt.Errorf("邮件配置缺少 toEmail")
-	}
+	n.logger.Info("发送通知",
+		zap.String("channelType", channelConfig.Type),
+	)
 
-	// 邮件主题，默认为"Pika 告警通知"
-	subject, ok := config["subject"].(string)
-	if !ok || subject == "" {
-		subject = "Pika 告警通知"
+	channel, err := NewChannel(channelConfig.Type, channelConfig.Config)
+	if err != nil {
+		return err
 	}
-
-	return n.sendEmail(ctx, smtpHost, smtpPort, fromEmail, password, toEmail, subject, message)
-}
-
-// sendWebhookByConfig 根据配置发送自定义Webhook
-func (n *Notifier) sendWebhookByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord, maskIP bool) error {
-	return n.sendCustomWebhook(ctx, config, agent, record, maskIP)
+	return channel.Send(ctx, n, &Notification{
+		Channel: channelConfig,
+		Record:  record,
+		Agent:   agent,
+		MaskIP:  maskIP,
+	})
 }
 
-// SendNotificationByConfig 根据新的配置结构发送通知
-func (n *Notifier) SendNotificationByConfig(ctx context.Context, channelConfig *models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent, maskIP bool) error {
+// SendGroupNotificationByConfig 根据渠道配置发送告警聚合通知
+func (n *Notifier) SendGroupNotificationByConfig(ctx context.Context, channelConfig *models.NotificationChannelConfig, group *AlertGroup, maskIP bool) error {
 	if !channelConfig.Enabled {
 		return fmt.Errorf("通知渠道已禁用")
 	}
 
-	n.logger.Info("发送通知",
+	n.logger.Info("发送聚合通知",
 		zap.String("channelType", channelConfig.Type),
+		zap.Int("count", len(group.Items)),
 	)
 
-	// 构造通知消息内容
-	message := n.buildMessage(agent, record, maskIP)
-
-	switch channelConfig.Type {
-	case "dingtalk":
-		return n.sendDingTalkByConfig(ctx, channelConfig.Config, message)
-	case "wecom":
-		return n.sendWeComByConfig(ctx, channelConfig.Config, message)
-	case "wecomApp":
-		return n.sendWeComAppByConfig(ctx, channelConfig.Config, message)
-	case "feishu":
-		return n.sendFeishuByConfig(ctx, channelConfig.Config, message)
-	case "telegram":
-		return n.sendTelegramByConfig(ctx, channelConfig.Config, message)
-	case "email":
-		return n.sendEmailByConfig(ctx, channelConfig.Config, message)
-	case "webhook":
-		return n.sendWebhookByConfig(ctx, channelConfig.Config, agent, record, maskIP)
-	default:
-		return fmt.Errorf("不支持的通知渠道类型: %s", channelConfig.Type)
+	channel, err := NewChannel(channelConfig.Type, channelConfig.Config)
+	if err != nil {
+		return err
 	}
+	return channel.SendGroup(ctx, n, &GroupNotification{
+		Channel: channelConfig,
+		Group:   group,
+		MaskIP:  maskIP,
+	})
 }
 
-// SendNotificationByConfigs 根据新的配置结构向多个渠道发送通知
-func (n *Notifier) SendNotificationByConfigs(ctx context.Context, channelConfigs []models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent, maskIP bool) error {
-	var errs []error
+// maxGroupMessageItems 聚合消息中最多列出的告警条数
+const maxGroupMessageItems = 20
 
-	for _, channelConfig := range channelConfigs {
-		if err := n.SendNotificationByConfig(ctx, &channelConfig, record, agent, maskIP); err != nil {
-			n.logger.Error("发送通知失败",
-				zap.String("channelType", channelConfig.Type),
-				zap.Error(err),
-			)
-			errs = append(errs, err)
-		}
+// buildGroupMessage 构建告警聚合消息文本
+func (n *Notifier) buildGroupMessage(group *AlertGroup, maskIP bool) string {
+	first := group.Items[0].Record
+
+	// 分组内告警类型相同时使用类型名称作为标题
+	title := i18n.T("notify.group.default_title")
+	if alertType, ok := group.Labels[models.AlertGroupByAlertType]; ok {
+		title = getAlertTypeMetadata(alertType).Name
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("部分通知发送失败: %v", errs)
+	var sb strings.Builder
+	if group.Status == "resolved" {
+		sb.WriteString(i18n.T("notify.group.resolved_title", title))
+	} else {
+		sb.WriteString(i18n.T("notify.group.firing_title", getLevelIcon(first.Level), title))
 	}
+	sb.WriteString("\n\n")
 
-	return nil
-}
+	agentIDs := make(map[string]struct{}, len(group.Items))
+	for _, item := range group.Items {
+		agentIDs[item.Record.AgentID] = struct{}{}
+	}
+	sb.WriteString(i18n.T("notify.group.count", strconv.Itoa(len(group.Items))) + "\n")
+	sb.WriteString(i18n.T("notify.group.agents", strconv.Itoa(len(agentIDs))) + "\n")
+	if tags := group.Labels[models.AlertGroupByTag]; tags != "" {
+		sb.WriteString(i18n.T("notify.group.tags", tags) + "\n")
+	}
+	sb.WriteString("\n")
 
-// SendDingTalkByConfig 导出方法供外部调用
-func (n *Notifier) SendDingTalkByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendDingTalkByConfig(ctx, config, message)
-}
+	for i, item := range group.Items {
+		if i >= maxGroupMessageItems {
+			sb.WriteString(i18n.T("notify.group.more", strconv.Itoa(len(group.Items))) + "\n")
+			break
+		}
+		displayIP := item.Agent.IP
+		if maskIP {
+			displayIP = maskIPAddress(item.Agent.IP)
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%s) [%s] %s\n", item.Agent.Name, displayIP, item.Record.Level, item.Record.Message))
+	}
 
-// SendWeComByConfig 导出方法供外部调用
-func (n *Notifier) SendWeComByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendWeComByConfig(ctx, config, message)
+	sb.WriteString("\n")
+	if group.Status == "resolved" {
+		sb.WriteString(i18n.T("notify.group.resolved_at", utils.FormatTimestamp(first.ResolvedAt)))
+	} else {
+		sb.WriteString(i18n.T("notify.group.fired_at", utils.FormatTimestamp(first.FiredAt)))
+	}
+	return sb.String()
 }
 
-// SendWeComAppByConfig 导出方法供外部调用
-func (n *Notifier) SendWeComAppByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendWeComAppByConfig(ctx, config, message)
-}
+// sendGroupWebhook 发送告警聚合通知到自定义Webhook
+func (n *Notifier) sendGroupWebhook(ctx context.Context, cfg *webhookChannel, group *AlertGroup, message string) error {
+	var reqBody io.Reader
+	var contentType string
+	var err error
 
-// SendFeishuByConfig 导出方法供外部调用
-func (n *Notifier) SendFeishuByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendFeishuByConfig(ctx, config, message)
-}
+	switch cfg.bodyTemplate() {
+	case "json":
+		alerts := make([]map[string]interface{}, 0, len(group.Items))
+		for _, item := range group.Items {
+			alerts = append(alerts, map[string]interface{}{
+				"agentId":     item.Agent.ID,
+				"agentName":   item.Agent.Name,
+				"type":        item.Record.AlertType,
+				"level":       item.Record.Level,
+				"message":     item.Record.Message,
+				"actualValue": item.Record.ActualValue,
+				"firedAt":     item.Record.FiredAt,
+				"resolvedAt":  item.Record.ResolvedAt,
+			})
+		}
+		data, err := json.Marshal(map[string]interface{}{
+			"msg_type": "text",
+			"text": map[string]string{
+				"content": message,
+			},
+			"group": map[string]interface{}{
+				"key":    group.Key,
+				"status": group.Status,
+				"labels": group.Labels,
+				"count":  len(group.Items),
+				"alerts": alerts,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("序列化 JSON 失败: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+		contentType = "application/json"
 
-// SendTelegramByConfig 导出方法供外部调用
-func (n *Notifier) SendTelegramByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendTelegramByConfig(ctx, config, message)
-}
+	case "form":
+		formData := url.Values{}
+		formData.Set("message", message)
+		formData.Set("group_key", group.Key)
+		formData.Set("group_status", group.Status)
+		formData.Set("group_count", strconv.Itoa(len(group.Items)))
+		reqBody = strings.NewReader(formData.Encode())
+		contentType = "application/x-www-form-urlencoded"
+
+	case "custom":
+		// 自定义模板使用分组内第一条告警的字段，消息内容为聚合消息
+		first := group.Items[0]
+		reqBody, err = n.buildCustomBody(first.Agent, first.Record, message, cfg.CustomBody)
+		if err != nil {
+			return err
+		}
+		contentType = "text/plain"
 
-// SendEmailByConfig 导出方法供外部调用
-func (n *Notifier) SendEmailByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendEmailByConfig(ctx, config, message)
+	default:
+		return fmt.Errorf("不支持的 bodyTemplate: %s", cfg.BodyTemplate)
+	}
+
+	return n.sendHTTPRequest(ctx, cfg.method(), cfg.URL, reqBody, cfg.Headers, contentType)
 }
 
-// SendWebhookByConfig 导出方法供外部调用（测试用）
-func (n *Notifier) SendWebhookByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	// 为了测试，创建一个临时的 agent 和 record
+// newTestAlert 构建测试通知使用的探针和告警记录
+func newTestAlert(message string) (*models.Agent, *models.AlertRecord) {
 	agent := &models.Agent{
 		ID:       "test-agent",
-		Name:     "测试探针",
+		Name:     i18n.T("notify.test_agent"),
 		Hostname: "test-host",
 		IP:       "127.0.0.1",
 	}
@@ -943,43 +1083,14 @@ func (n *Notifier) SendWebhookByConfig(ctx context.Context, config map[string]in
 		ActualValue: 0,
 		FiredAt:     time.Now().UnixMilli(),
 	}
-	return n.sendWebhookByConfig(ctx, config, agent, record, false)
+	return agent, record
 }
 
-// SendTestNotification 发送测试通知（动态匹配通知渠道类型）
+// SendTestNotification 发送测试通知
 func (n *Notifier) SendTestNotification(ctx context.Context, channelType string, config map[string]interface{}, message string) error {
-	switch channelType {
-	case "dingtalk":
-		return n.sendDingTalkByConfig(ctx, config, message)
-	case "wecom":
-		return n.sendWeComByConfig(ctx, config, message)
-	case "wecomApp":
-		return n.sendWeComAppByConfig(ctx, config, message)
-	case "feishu":
-		return n.sendFeishuByConfig(ctx, config, message)
-	case "telegram":
-		return n.sendTelegramByConfig(ctx, config, message)
-	case "email":
-		return n.sendEmailByConfig(ctx, config, message)
-	case "webhook":
-		// Webhook 需要 agent 和 record，创建测试数据
-		agent := &models.Agent{
-			ID:       "test-agent",
-			Name:     "测试探针",
-			Hostname: "test-host",
-			IP:       "127.0.0.1",
-		}
-		record := &models.AlertRecord{
-			AlertType:   "test",
-			Level:       "info",
-			Status:      "firing",
-			Message:     message,
-			Threshold:   0,
-			ActualValue: 0,
-			FiredAt:     time.Now().UnixMilli(),
-		}
-		return n.sendWebhookByConfig(ctx, config, agent, record, false)
-	default:
-		return fmt.Errorf("不支持的通知渠道类型: %s", channelType)
+	channel, err := NewChannel(channelType, config)
+	if err != nil {
+		return err
 	}
+	return channel.Test(ctx, n, message)
 }
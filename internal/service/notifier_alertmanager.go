@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// alertmanagerGeneratorLabel 标记由 Pika 生成的告警，接收 Alertmanager 推送时跳过，避免循环
+const alertmanagerGeneratorLabel = "generator"
+
+// alertmanagerAlertTTL api 模式下触发中告警的有效期（EndsAt），告警检查任务每 30 秒重新推送一次，
+// Pika 停止推送后 Alertmanager 在有效期结束时自动恢复告警
+const alertmanagerAlertTTL = 2 * time.Minute
+
+// AlertmanagerMessage Alertmanager Webhook 消息（version 4）
+type AlertmanagerMessage struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert Alertmanager 告警
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// alertmanagerPostableAlert Alertmanager API v2 /api/v2/alerts 的请求格式
+type alertmanagerPostableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     string            `json:"startsAt,omitempty"`
+	EndsAt       string            `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// alertmanagerFingerprint 计算标签集合的指纹，算法与 Prometheus 一致（按标签名排序后做 FNV-1a）
+func alertmanagerFingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := fnv.New64a()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0xff})
+		h.Write([]byte(labels[name]))
+		h.Write([]byte{0xff})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// buildAlertmanagerAlert 将告警记录转换为 Alertmanager 告警
+func buildAlertmanagerAlert(agent *models.Agent, record *models.AlertRecord, message string, maskIP bool) AlertmanagerAlert {
+	displayIP := agent.IP
+	if maskIP {
+		displayIP = maskIPAddress(agent.IP)
+	}
+
+	// 标签决定 Alertmanager 中告警的身份，只使用告警存续期间不变的值，
+	// 探针名称、主机名等可修改的信息放在注解中，避免改名后恢复通知无法匹配原告警
+	labels := map[string]string{}
+	for k, v := range record.Labels.Data() {
+		labels[k] = v
+	}
+	labels["alertname"] = record.AlertType
+	labels["severity"] = record.Level
+	labels["agent_id"] = agent.ID
+	labels[alertmanagerGeneratorLabel] = "pika"
+	if record.ConfigID != "" {
+		labels["config_id"] = record.ConfigID
+	}
+	if record.MonitorID != "" {
+		labels["monitor_id"] = record.MonitorID
+	}
+
+	alert := AlertmanagerAlert{
+		Status: "firing",
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     record.Message,
+			"description": message,
+			"agent_name":  agent.Name,
+			"hostname":    agent.Hostname,
+			"ip":          displayIP,
+			"value":       strconv.FormatFloat(record.ActualValue, 'f', 2, 64),
+			"threshold":   strconv.FormatFloat(record.Threshold, 'f', 2, 64),
+		},
+		StartsAt:    time.UnixMilli(record.FiredAt).UTC(),
+		Fingerprint: alertmanagerFingerprint(labels),
+	}
+	if record.Status == "resolved" {
+		alert.Status = "resolved"
+		alert.EndsAt = time.UnixMilli(record.ResolvedAt).UTC()
+	}
+	return alert
+}
+
+// buildAlertmanagerMessage 构建 Alertmanager Webhook 消息，公共标签和注解取所有告警的交集
+func buildAlertmanagerMessage(alerts []AlertmanagerAlert) *AlertmanagerMessage {
+	msg := &AlertmanagerMessage{
+		Version:           "4",
+		Status:            "resolved",
+		Receiver:          "pika",
+		GroupLabels:       map[string]string{},
+		CommonLabels:      commonAlertmanagerValues(alerts, func(a AlertmanagerAlert) map[string]string { return a.Labels }),
+		CommonAnnotations: commonAlertmanagerValues(alerts, func(a AlertmanagerAlert) map[string]string { return a.Annotations }),
+		Alerts:            alerts,
+	}
+	for _, alert := range alerts {
+		if alert.Status == "firing" {
+			msg.Status = "firing"
+			break
+		}
+	}
+	if alertname, ok := msg.CommonLabels["alertname"]; ok {
+		msg.GroupLabels["alertname"] = alertname
+	}
+
+	pairs := make([]string, 0, len(msg.GroupLabels))
+	for k, v := range msg.GroupLabels {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(pairs)
+	msg.GroupKey = "{}:{" + strings.Join(pairs, ", ") + "}"
+	return msg
+}
+
+// commonAlertmanagerValues 计算所有告警中取值相同的键值对
+func commonAlertmanagerValues(alerts []AlertmanagerAlert, get func(AlertmanagerAlert) map[string]string) map[string]string {
+	common := map[string]string{}
+	if len(alerts) == 0 {
+		return common
+	}
+	for k, v := range get(alerts[0]) {
+		common[k] = v
+	}
+	for _, alert := range alerts[1:] {
+		values := get(alert)
+		for k, v := range common {
+			if values[k] != v {
+				delete(common, k)
+			}
+		}
+	}
+	return common
+}
+
+// alertmanagerChannel Alertmanager 渠道
+//
+// mode 为 webhook（默认）时向 url 发送 Webhook 消息；为 api 时向 Alertmanager 的 /api/v2/alerts 推送告警。
+// api 模式下触发中的告警带有 EndsAt，由告警检查任务定期重新推送（见 RefreshAlertmanagerAlerts），
+// 不依赖 Alertmanager 的 resolve_timeout。
+type alertmanagerChannel struct {
+	URL         string `json:"url"`
+	Mode        string `json:"mode"`        // webhook 或 api
+	BearerToken string `json:"bearerToken"` // 可选
+}
+
+func (c *alertmanagerChannel) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("Alertmanager 配置缺少 url")
+	}
+	switch c.Mode {
+	case "", "webhook", "api":
+		return nil
+	default:
+		return fmt.Errorf("不支持的 Alertmanager 模式: %s", c.Mode)
+	}
+}
+
+// Send 发送单条告警，只有触发、确认和恢复状态会推送，确认后的告警仍视为 firing
+func (c *alertmanagerChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	record := notification.Record
+	switch record.Status {
+	case "firing", "acknowledged", "resolved":
+	default:
+		return nil
+	}
+	message := n.buildChannelMessage(notification.Channel, notification.Agent, record, notification.MaskIP)
+	alert := buildAlertmanagerAlert(notification.Agent, record, message, notification.MaskIP)
+	return c.send(ctx, n, []AlertmanagerAlert{alert})
+}
+
+// SendGroup 将聚合通知中的告警放在同一条 Alertmanager 消息中发送
+func (c *alertmanagerChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	alerts := make([]AlertmanagerAlert, 0, len(notification.Group.Items))
+	for _, item := range notification.Group.Items {
+		message := n.buildChannelMessage(notification.Channel, item.Agent, item.Record, notification.MaskIP)
+		alerts = append(alerts, buildAlertmanagerAlert(item.Agent, item.Record, message, notification.MaskIP))
+	}
+	return c.send(ctx, n, alerts)
+}
+
+func (c *alertmanagerChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	return sendLifecycleTest(ctx, n, c, message)
+}
+
+// send 按配置的模式发送 Alertmanager 格式的告警
+func (c *alertmanagerChannel) send(ctx context.Context, n *Notifier, alerts []AlertmanagerAlert) error {
+	headers := map[string]string{}
+	if c.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + c.BearerToken
+	}
+
+	if c.Mode != "api" {
+		_, err := n.doJSONRequest(ctx, http.MethodPost, c.URL, headers, buildAlertmanagerMessage(alerts))
+		return err
+	}
+
+	expiresAt := time.Now().Add(alertmanagerAlertTTL).UTC()
+	postable := make([]alertmanagerPostableAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		endsAt := alert.EndsAt
+		if alert.Status == "firing" {
+			endsAt = expiresAt
+		}
+		postable = append(postable, alertmanagerPostableAlert{
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			StartsAt:     alert.StartsAt.Format(time.RFC3339),
+			EndsAt:       endsAt.Format(time.RFC3339),
+			GeneratorURL: alert.GeneratorURL,
+		})
+	}
+	apiURL := strings.TrimSuffix(c.URL, "/") + "/api/v2/alerts"
+	_, err := n.doJSONRequest(ctx, http.MethodPost, apiURL, headers, postable)
+	return err
+}
+
+// RefreshAlertmanagerAlerts 向 api 模式的 Alertmanager 渠道重新推送触发中的告警，延长告警的有效期，
+// 渠道为 webhook 模式时不做处理
+func (n *Notifier) RefreshAlertmanagerAlerts(ctx context.Context, channelConfig *models.NotificationChannelConfig, items []AlertGroupItem, maskIP bool) error {
+	channel, err := NewChannel(channelConfig.Type, channelConfig.Config)
+	if err != nil {
+		return err
+	}
+	am, ok := channel.(*alertmanagerChannel)
+	if !ok || am.Mode != "api" || len(items) == 0 {
+		return nil
+	}
+
+	alerts := make([]AlertmanagerAlert, 0, len(items))
+	for _, item := range items {
+		message := n.buildChannelMessage(channelConfig, item.Agent, item.Record, maskIP)
+		alerts = append(alerts, buildAlertmanagerAlert(item.Agent, item.Record, message, maskIP))
+	}
+	return am.send(ctx, n, alerts)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// Channel 通知渠道
+//
+// 每种渠道类型注册一个 ChannelFactory，发送前将渠道配置解码为渠道自己的配置结构并校验。
+type Channel interface {
+	// Validate 校验渠道配置
+	Validate() error
+	// Send 发送单条告警通知
+	Send(ctx context.Context, n *Notifier, notification *Notification) error
+	// SendGroup 发送告警聚合通知
+	SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error
+	// Test 发送测试通知
+	Test(ctx context.Context, n *Notifier, message string) error
+}
+
+// Notification 单条告警通知
+type Notification struct {
+	Channel *models.NotificationChannelConfig // 渠道配置，用于查找自定义消息模板
+	Record  *models.AlertRecord
+	Agent   *models.Agent
+	MaskIP  bool // 是否打码 IP
+}
+
+// GroupNotification 告警聚合通知
+type GroupNotification struct {
+	Channel *models.NotificationChannelConfig
+	Group   *AlertGroup
+	MaskIP  bool
+}
+
+// ChannelFactory 根据渠道配置创建通知渠道
+type ChannelFactory func(config map[string]interface{}) (Channel, error)
+
+var (
+	channelFactoriesMu sync.RWMutex
+	channelFactories   = map[string]ChannelFactory{}
+)
+
+// RegisterChannel 注册通知渠道类型，重复注册时覆盖已有的实现
+func RegisterChannel(channelType string, factory ChannelFactory) {
+	channelFactoriesMu.Lock()
+	defer channelFactoriesMu.Unlock()
+	channelFactories[channelType] = factory
+}
+
+// ChannelTypes 已注册的通知渠道类型
+func ChannelTypes() []string {
+	channelFactoriesMu.RLock()
+	defer channelFactoriesMu.RUnlock()
+	types := make([]string, 0, len(channelFactories))
+	for channelType := range channelFactories {
+		types = append(types, channelType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// lifecycleChannelTypes 按告警生命周期创建和关闭事件的渠道，这类渠道必须收到每一次触发和恢复
+var lifecycleChannelTypes = map[string]bool{
+	"pagerduty":    true,
+	"opsgenie":     true,
+	"alertmanager": true,
+}
+
+// IsLifecycleChannel 是否为按告警生命周期处理的事件管理渠道
+func IsLifecycleChannel(channelType string) bool {
+	return lifecycleChannelTypes[channelType]
+}
+
+// NewChannel 根据渠道类型和配置创建通知渠道，配置不合法时返回错误
+func NewChannel(channelType string, config map[string]interface{}) (Channel, error) {
+	channelFactoriesMu.RLock()
+	factory, ok := channelFactories[channelType]
+	channelFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的通知渠道类型: %s", channelType)
+	}
+
+	channel, err := factory(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := channel.Validate(); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// ValidateChannelConfigs 校验已启用的通知渠道配置
+func ValidateChannelConfigs(channels []models.NotificationChannelConfig) error {
+	for _, channel := range channels {
+		if !channel.Enabled {
+			continue
+		}
+		if _, err := NewChannel(channel.Type, channel.Config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeChannelConfig 将渠道配置解码为渠道的配置结构
+func decodeChannelConfig(config map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("通知渠道配置格式错误: %w", err)
+	}
+	return nil
+}
+
+// typedChannel 创建将配置解码为 T 后由 wrap 转换为渠道的 ChannelFactory
+func typedChannel[T any](wrap func(*T) Channel) ChannelFactory {
+	return func(config map[string]interface{}) (Channel, error) {
+		c := new(T)
+		if err := decodeChannelConfig(config, c); err != nil {
+			return nil, err
+		}
+		return wrap(c), nil
+	}
+}
+
+// channelOf 配置结构本身实现了 Channel 的渠道
+func channelOf[T any, P interface {
+	*T
+	Channel
+}]() ChannelFactory {
+	return typedChannel(func(c *T) Channel { return P(c) })
+}
+
+// configInt 兼容数字和字符串两种写法的整数配置，如 587 和 "587"
+type configInt int
+
+func (v *configInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*v = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("无效的数字: %s", s)
+	}
+	*v = configInt(f)
+	return nil
+}
+
+// textSender 只发送文本消息的渠道
+type textSender interface {
+	Validate() error
+	sendText(ctx context.Context, n *Notifier, message string) error
+}
+
+// textChannel 将文本渠道适配为 Channel，单条通知使用内置消息或自定义模板，聚合通知使用聚合消息
+type textChannel struct {
+	textSender
+}
+
+func (c textChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	return c.sendText(ctx, n, n.buildChannelMessage(notification.Channel, notification.Agent, notification.Record, notification.MaskIP))
+}
+
+func (c textChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	return c.sendText(ctx, n, n.buildGroupMessage(notification.Group, notification.MaskIP))
+}
+
+func (c textChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	return c.sendText(ctx, n, message)
+}
+
+// textChannelOf 文本渠道的 ChannelFactory
+func textChannelOf[T any, P interface {
+	*T
+	textSender
+}]() ChannelFactory {
+	return typedChannel(func(c *T) Channel { return textChannel{P(c)} })
+}
+
+func init() {
+	RegisterChannel("dingtalk", textChannelOf[dingTalkChannel]())
+	RegisterChannel("wecom", textChannelOf[weComChannel]())
+	RegisterChannel("wecomApp", textChannelOf[weComAppChannel]())
+	RegisterChannel("feishu", textChannelOf[feishuChannel]())
+	RegisterChannel("telegram", textChannelOf[telegramChannel]())
+	RegisterChannel("email", channelOf[emailChannel]())
+	RegisterChannel("slack", chatChannelOf[slackChannel]())
+	RegisterChannel("discord", chatChannelOf[discordChannel]())
+	RegisterChannel("teams", chatChannelOf[teamsChannel]())
+	RegisterChannel("ntfy", pushChannelOf[ntfyChannel]())
+	RegisterChannel("gotify", pushChannelOf[gotifyChannel]())
+	RegisterChannel("bark", pushChannelOf[barkChannel]())
+	RegisterChannel("pushover", pushChannelOf[pushoverChannel]())
+	RegisterChannel("matrix", pushChannelOf[matrixChannel]())
+	RegisterChannel("pagerduty", incidentChannelOf[pagerDutyChannel]())
+	RegisterChannel("opsgenie", incidentChannelOf[opsgenieChannel]())
+	RegisterChannel("alertmanager", channelOf[alertmanagerChannel]())
+	RegisterChannel("webhook", channelOf[webhookChannel]())
+	RegisterChannel("exec", channelOf[execChannel]())
+}
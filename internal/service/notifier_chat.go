@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/go-orz/cache"
+	"go.uber.org/zap"
+)
+
+// 消息卡片色调，决定各渠道卡片的颜色
+const (
+	chatToneCritical = "critical"
+	chatToneWarning  = "warning"
+	chatToneInfo     = "info"
+	chatToneResolved = "resolved"
+	chatToneMuted    = "muted"
+	chatToneFlapping = "flapping"
+)
+
+// chatToneColors 色调对应的卡片颜色（RGB）
+var chatToneColors = map[string]int{
+	chatToneCritical: 0xD32F2F,
+	chatToneWarning:  0xF9A825,
+	chatToneInfo:     0x1E88E5,
+	chatToneResolved: 0x43A047,
+	chatToneMuted:    0x9E9E9E,
+	chatToneFlapping: 0x8E24AA,
+}
+
+// teamsToneStyles 色调对应的 Adaptive Card 容器样式
+var teamsToneStyles = map[string]string{
+	chatToneCritical: "attention",
+	chatToneWarning:  "warning",
+	chatToneInfo:     "accent",
+	chatToneResolved: "good",
+	chatToneMuted:    "default",
+	chatToneFlapping: "emphasis",
+}
+
+// slackAPIURL Slack Web API 地址
+var slackAPIURL = "https://slack.com/api"
+
+// chatMessageRefCache 记录已发送的告警消息标识，告警确认或恢复时就地更新原消息
+var chatMessageRefCache = cache.New[string, string](time.Minute)
+
+// chatMessageRefTTL 告警消息标识的保留时间，超过后恢复通知作为新消息发送
+const chatMessageRefTTL = 7 * 24 * time.Hour
+
+// chatCard 聊天类渠道（Slack、Discord、Teams）的消息卡片
+type chatCard struct {
+	Title  string      // 标题，可为空
+	Text   string      // 正文
+	Tone   string      // 色调
+	Status string      // 告警状态，用于决定是否更新原消息，为空时总是发送新消息
+	Fields []chatField // 字段
+}
+
+// chatField 消息卡片字段
+type chatField struct {
+	Name  string
+	Value string
+}
+
+// chatSender 发送消息卡片的聊天类渠道
+type chatSender interface {
+	Validate() error
+	// sendCard 发送消息卡片，recordID 不为 0 时告警确认或恢复会更新原消息
+	sendCard(ctx context.Context, n *Notifier, card *chatCard, recordID int64) error
+}
+
+// chatChannel 将聊天类渠道适配为 Channel
+type chatChannel struct {
+	chatSender
+}
+
+func (c chatChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	card := n.buildChatCard(notification.Channel, notification.Agent, notification.Record, notification.MaskIP)
+	return c.sendCard(ctx, n, card, notification.Record.ID)
+}
+
+func (c chatChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	message := n.buildGroupMessage(notification.Group, notification.MaskIP)
+	return c.sendCard(ctx, n, buildGroupChatCard(notification.Group, message), 0)
+}
+
+func (c chatChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	return c.sendCard(ctx, n, &chatCard{Text: message, Tone: chatToneInfo}, 0)
+}
+
+// chatChannelOf 聊天类渠道的 ChannelFactory
+func chatChannelOf[T any, P interface {
+	*T
+	chatSender
+}]() ChannelFactory {
+	return typedChannel(func(c *T) Channel { return chatChannel{P(c)} })
+}
+
+// chatToneColor 获取色调对应的颜色
+func chatToneColor(tone string) int {
+	if color, ok := chatToneColors[tone]; ok {
+		return color
+	}
+	return chatToneColors[chatToneInfo]
+}
+
+// levelTone 告警级别对应的色调
+func levelTone(level string) string {
+	switch level {
+	case "critical":
+		return chatToneCritical
+	case "warning":
+		return chatToneWarning
+	default:
+		return chatToneInfo
+	}
+}
+
+// buildChatCard 根据告警记录构建消息卡片，渠道配置了模板时正文使用模板渲染结果
+func (n *Notifier) buildChatCard(channelConfig *models.NotificationChannelConfig, agent *models.Agent, record *models.AlertRecord, maskIP bool) *chatCard {
+	metadata := getAlertTypeMetadata(record.AlertType)
+	displayIP := agent.IP
+	if maskIP {
+		displayIP = maskIPAddress(agent.IP)
+	}
+
+	card := &chatCard{
+		Text:   record.Message,
+		Status: record.Status,
+		Fields: []chatField{
+			{Name: i18n.T("notify.field.agent"), Value: fmt.Sprintf("%s (%s)", agent.Name, displayIP)},
+			{Name: i18n.T("notify.field.host"), Value: agent.Hostname},
+		},
+	}
+	if FindNotificationTemplate(channelConfig.Templates, record.AlertType, record.Status) != "" {
+		card.Text = n.buildChannelMessage(channelConfig, agent, record, maskIP)
+	}
+
+	currentValue := fmt.Sprintf("%.2f%s", record.ActualValue, metadata.ValueUnit)
+	switch record.Status {
+	case "resolved":
+		card.Title = i18n.T("notify.chat.resolved", metadata.Name)
+		card.Tone = chatToneResolved
+		card.Fields = append(card.Fields,
+			chatField{Name: i18n.T("notify.field.value"), Value: currentValue},
+			chatField{Name: i18n.T("notify.field.resolved_at"), Value: utils.FormatTimestamp(record.ResolvedAt)},
+		)
+		if record.FiredAt > 0 && record.ResolvedAt > record.FiredAt {
+			card.Fields = append(card.Fields, chatField{Name: i18n.T("notify.field.duration"), Value: utils.FormatDuration(record.ResolvedAt - record.FiredAt)})
+		}
+	case "acknowledged":
+		card.Title = i18n.T("notify.chat.acknowledged", metadata.Name)
+		card.Tone = chatToneMuted
+		card.Fields = append(card.Fields,
+			chatField{Name: i18n.T("notify.field.acknowledged_by"), Value: record.AcknowledgedBy},
+			chatField{Name: i18n.T("notify.field.fired_at"), Value: utils.FormatTimestamp(record.FiredAt)},
+		)
+		if record.Assignee != "" {
+			card.Fields = append(card.Fields, chatField{Name: i18n.T("notify.field.assignee"), Value: record.Assignee})
+		}
+	case "flapping":
+		card.Title = i18n.T("notify.chat.flapping", metadata.Name)
+		card.Tone = chatToneFlapping
+		card.Fields = append(card.Fields, chatField{Name: i18n.T("notify.field.value"), Value: currentValue})
+	case "info":
+		card.Title = "ℹ️ " + metadata.Name
+		card.Tone = chatToneInfo
+	default:
+		card.Title = getLevelIcon(record.Level) + " " + metadata.Name
+		card.Tone = levelTone(record.Level)
+		card.Fields = append(card.Fields,
+			chatField{Name: i18n.T("notify.field.level"), Value: record.Level},
+			chatField{Name: i18n.T("notify.field.value"), Value: currentValue},
+			chatField{Name: i18n.T("notify.field.threshold"), Value: fmt.Sprintf("%.2f%s", record.Threshold, metadata.ThresholdUnit)},
+			chatField{Name: i18n.T("notify.field.fired_at"), Value: utils.FormatTimestamp(record.FiredAt)},
+		)
+	}
+	return card
+}
+
+// buildGroupChatCard 构建告警聚合消息卡片
+func buildGroupChatCard(group *AlertGroup, message string) *chatCard {
+	tone := levelTone(group.Items[0].Record.Level)
+	if group.Status == "resolved" {
+		tone = chatToneResolved
+	}
+	return &chatCard{Text: message, Tone: tone}
+}
+
+// chatMessageKey 告警消息标识的缓存 key，recordID 为 0 时不记录
+func chatMessageKey(channelType, target string, recordID int64) string {
+	if recordID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%d", channelType, target, recordID)
+}
+
+// findChatMessageRef 查找需要就地更新的原消息，仅告警确认和恢复时更新
+func findChatMessageRef(key string, card *chatCard) (string, bool) {
+	if key == "" || (card.Status != "acknowledged" && card.Status != "resolved") {
+		return "", false
+	}
+	return chatMessageRefCache.Get(key)
+}
+
+// saveChatMessageRef 记录告警消息标识，告警恢复后不再需要更新
+func saveChatMessageRef(key string, card *chatCard, ref string) {
+	if key == "" {
+		return
+	}
+	if card.Status == "resolved" {
+		chatMessageRefCache.Delete(key)
+		return
+	}
+	if ref != "" {
+		chatMessageRefCache.Set(key, ref, chatMessageRefTTL)
+	}
+}
+
+// truncateText 按字符数截断文本，避免超过渠道的长度限制
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// escapeSlackText 转义 Slack mrkdwn 中的控制字符
+func escapeSlackText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// buildSlackPayload 构建 Slack 消息，使用带颜色的 attachment 承载 Block Kit 内容
+func buildSlackPayload(card *chatCard) map[string]interface{} {
+	var blocks []map[string]interface{}
+	if card.Title != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": "*" + escapeSlackText(card.Title) + "*"},
+		})
+	}
+	if card.Text != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": truncateText(escapeSlackText(card.Text), 3000)},
+		})
+	}
+	if len(card.Fields) > 0 {
+		// section 最多 10 个字段
+		fields := make([]map[string]string, 0, len(card.Fields))
+		for i, field := range card.Fields {
+			if i >= 10 {
+				break
+			}
+			fields = append(fields, map[string]string{
+				"type": "mrkdwn",
+				"text": "*" + escapeSlackText(field.Name) + "*\n" + truncateText(escapeSlackText(field.Value), 1900),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type":   "section",
+			"fields": fields,
+		})
+	}
+
+	fallback := card.Title
+	if fallback == "" {
+		fallback = card.Text
+	}
+	return map[string]interface{}{
+		"text": truncateText(fallback, 3000),
+		"attachments": []map[string]interface{}{
+			{
+				"color":  fmt.Sprintf("#%06X", chatToneColor(card.Tone)),
+				"blocks": blocks,
+			},
+		},
+	}
+}
+
+// slackChannel Slack 渠道，支持 Incoming Webhook 和 Bot Token 两种方式
+type slackChannel struct {
+	WebhookURL string `json:"webhookUrl"`
+	BotToken   string `json:"botToken"` // 配置后使用 Web API 发送，可以更新原消息
+	Channel    string `json:"channel"`  // Bot Token 方式发送的频道ID
+}
+
+func (c *slackChannel) Validate() error {
+	if c.BotToken != "" {
+		if c.Channel == "" {
+			return fmt.Errorf("Slack 配置缺少 channel")
+		}
+		return nil
+	}
+	if c.WebhookURL == "" {
+		return fmt.Errorf("Slack 配置缺少 webhookUrl 或 botToken")
+	}
+	return nil
+}
+
+func (c *slackChannel) sendCard(ctx context.Context, n *Notifier, card *chatCard, recordID int64) error {
+	payload := buildSlackPayload(card)
+	if c.BotToken != "" {
+		return n.sendSlackBot(ctx, c.BotToken, c.Channel, payload, card, recordID)
+	}
+	_, err := n.sendJSONRequest(ctx, c.WebhookURL, payload)
+	return err
+}
+
+// slackAPIResponse Slack Web API 响应
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+// sendSlackBot 使用 Bot Token 发送 Slack 消息，告警确认或恢复时通过 chat.update 更新原消息
+func (n *Notifier) sendSlackBot(ctx context.Context, botToken, channel string, payload map[string]interface{}, card *chatCard, recordID int64) error {
+	headers := map[string]string{"Authorization": "Bearer " + botToken}
+	payload["channel"] = channel
+
+	key := chatMessageKey("slack", channel, recordID)
+	if ts, ok := findChatMessageRef(key, card); ok {
+		payload["ts"] = ts
+		_, err := n.callSlackAPI(ctx, "chat.update", headers, payload)
+		if err == nil {
+			saveChatMessageRef(key, card, ts)
+			return nil
+		}
+		n.logger.Warn("更新 Slack 消息失败，改为发送新消息", zap.Error(err))
+		delete(payload, "ts")
+	}
+
+	resp, err := n.callSlackAPI(ctx, "chat.postMessage", headers, payload)
+	if err != nil {
+		return err
+	}
+	saveChatMessageRef(key, card, resp.TS)
+	return nil
+}
+
+// callSlackAPI 调用 Slack Web API，HTTP 状态码正常但 ok 为 false 时返回错误
+func (n *Notifier) callSlackAPI(ctx context.Context, method string, headers map[string]string, payload map[string]interface{}) (*slackAPIResponse, error) {
+	respBody, err := n.doJSONRequest(ctx, http.MethodPost, slackAPIURL+"/"+method, headers, payload)
+	if err != nil {
+		return nil, err
+	}
+	var resp slackAPIResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("解析 Slack 响应失败: %w", err)
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("Slack 返回错误: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// buildDiscordPayload 构建 Discord Webhook 消息，使用 embed 展示颜色和字段
+func buildDiscordPayload(card *chatCard) map[string]interface{} {
+	embed := map[string]interface{}{
+		"color":     chatToneColor(card.Tone),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+	if card.Title != "" {
+		embed["title"] = truncateText(card.Title, 256)
+	}
+	if card.Text != "" {
+		embed["description"] = truncateText(card.Text, 4096)
+	}
+	if len(card.Fields) > 0 {
+		fields := make([]map[string]interface{}, 0, len(card.Fields))
+		for i, field := range card.Fields {
+			if i >= 25 {
+				break
+			}
+			value := field.Value
+			if value == "" {
+				value = "-"
+			}
+			fields = append(fields, map[string]interface{}{
+				"name":   truncateText(field.Name, 256),
+				"value":  truncateText(value, 1024),
+				"inline": true,
+			})
+		}
+		embed["fields"] = fields
+	}
+	return map[string]interface{}{
+		"embeds": []map[string]interface{}{embed},
+	}
+}
+
+// discordChannel Discord Webhook 渠道
+type discordChannel struct {
+	WebhookURL string `json:"webhookUrl"`
+}
+
+func (c *discordChannel) Validate() error {
+	if c.WebhookURL == "" {
+		return fmt.Errorf("Discord 配置缺少 webhookUrl")
+	}
+	return nil
+}
+
+// sendCard 发送 Discord 消息，告警确认或恢复时编辑原消息
+func (c *discordChannel) sendCard(ctx context.Context, n *Notifier, card *chatCard, recordID int64) error {
+	webhookURL := c.WebhookURL
+	payload := buildDiscordPayload(card)
+
+	key := chatMessageKey("discord", webhookURL, recordID)
+	if messageID, ok := findChatMessageRef(key, card); ok {
+		editURL, err := discordWebhookURL(webhookURL, "/messages/"+messageID, nil)
+		if err != nil {
+			return err
+		}
+		if _, err = n.doJSONRequest(ctx, http.MethodPatch, editURL, nil, payload); err == nil {
+			saveChatMessageRef(key, card, messageID)
+			return nil
+		}
+		n.logger.Warn("更新 Discord 消息失败，改为发送新消息", zap.Error(err))
+	}
+
+	// wait=true 时 Discord 返回创建的消息，用于后续编辑
+	postURL, err := discordWebhookURL(webhookURL, "", url.Values{"wait": {"true"}})
+	if err != nil {
+		return err
+	}
+	respBody, err := n.sendJSONRequest(ctx, postURL, payload)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err == nil {
+		saveChatMessageRef(key, card, resp.ID)
+	}
+	return nil
+}
+
+// discordWebhookURL 在 Webhook 地址上追加路径和查询参数，保留原有的 thread_id 等参数
+func discordWebhookURL(webhookURL, path string, query url.Values) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("Discord webhookUrl 格式错误: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	q := u.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// buildTeamsPayload 构建 Teams Workflows Webhook 消息，使用 Adaptive Card 展示
+func buildTeamsPayload(card *chatCard) map[string]interface{} {
+	var body []map[string]interface{}
+
+	style, ok := teamsToneStyles[card.Tone]
+	if !ok {
+		style = "default"
+	}
+	if card.Title != "" {
+		body = append(body, map[string]interface{}{
+			"type":  "Container",
+			"style": style,
+			"bleed": true,
+			"items": []map[string]interface{}{
+				{
+					"type":   "TextBlock",
+					"text":   card.Title,
+					"weight": "Bolder",
+					"size":   "Medium",
+					"wrap":   true,
+				},
+			},
+		})
+	}
+	if card.Text != "" {
+		// Teams 中单个换行不生效，需要使用空行分段
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock",
+			"text": strings.ReplaceAll(card.Text, "\n", "\n\n"),
+			"wrap": true,
+		})
+	}
+	if len(card.Fields) > 0 {
+		facts := make([]map[string]string, 0, len(card.Fields))
+		for _, field := range card.Fields {
+			facts = append(facts, map[string]string{
+				"title": field.Name,
+				"value": field.Value,
+			})
+		}
+		body = append(body, map[string]interface{}{
+			"type":  "FactSet",
+			"facts": facts,
+		})
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"msteams": map[string]string{"width": "Full"},
+					"body":    body,
+				},
+			},
+		},
+	}
+}
+
+// teamsChannel Microsoft Teams Workflows Webhook 渠道
+type teamsChannel struct {
+	WebhookURL string `json:"webhookUrl"`
+}
+
+func (c *teamsChannel) Validate() error {
+	if c.WebhookURL == "" {
+		return fmt.Errorf("Teams 配置缺少 webhookUrl")
+	}
+	return nil
+}
+
+// sendCard 发送 Teams 消息，Workflows Webhook 不支持更新消息，状态变化时发送新卡片
+func (c *teamsChannel) sendCard(ctx context.Context, n *Notifier, card *chatCard, _ int64) error {
+	_, err := n.sendJSONRequest(ctx, c.WebhookURL, buildTeamsPayload(card))
+	return err
+}
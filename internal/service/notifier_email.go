@@ -0,0 +1,466 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+	"gopkg.in/gomail.v2"
+)
+
+// 邮件连接加密方式
+const (
+	emailSecuritySSL      = "ssl"      // 建立连接即使用 TLS（通常为 465 端口）
+	emailSecurityStartTLS = "starttls" // 明文连接后必须升级为 TLS（通常为 587 端口）
+	emailSecurityNone     = "none"     // 不加密
+)
+
+const (
+	// emailTimeout 未指定超时时间时，单封邮件的发送超时时间
+	emailTimeout = 30 * time.Second
+	// maxEmailDigestItems 汇总邮件中最多列出的告警条数
+	maxEmailDigestItems = 100
+)
+
+// emailAddresses 邮件地址列表，兼容逗号或分号分隔的字符串和字符串数组两种写法
+type emailAddresses []string
+
+func (a *emailAddresses) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("无效的邮件地址列表: %s", string(data))
+		}
+		list = strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' })
+	}
+
+	*a = (*a)[:0]
+	for _, addr := range list {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			*a = append(*a, addr)
+		}
+	}
+	return nil
+}
+
+// emailChannel 邮件渠道
+type emailChannel struct {
+	SMTPHost     string         `json:"smtpHost"`
+	SMTPPort     configInt      `json:"smtpPort"`
+	Security     string         `json:"security"` // 加密方式: ssl, starttls, none，为空时 465 端口使用 ssl，其他端口在服务器支持时使用 STARTTLS
+	FromEmail    string         `json:"fromEmail"`
+	Password     string         `json:"password"` // security 为 none 时可为空（不认证）
+	ToEmail      emailAddresses `json:"toEmail"`
+	CC           emailAddresses `json:"cc"`
+	BCC          emailAddresses `json:"bcc"`
+	Subject      string         `json:"subject"`      // 邮件主题模板，支持消息模板变量，聚合通知额外支持 {{count}}
+	Format       string         `json:"format"`       // 邮件格式: html(默认，附带纯文本), text
+	HTMLTemplate string         `json:"htmlTemplate"` // 自定义 HTML 模板（html/template 语法），为空时使用内置模板
+}
+
+func (c *emailChannel) Validate() error {
+	if c.SMTPHost == "" {
+		return fmt.Errorf("邮件配置缺少 smtpHost")
+	}
+	if c.SMTPPort <= 0 {
+		return fmt.Errorf("邮件配置缺少 smtpPort")
+	}
+	switch c.Security {
+	case "", emailSecuritySSL, emailSecurityStartTLS, emailSecurityNone:
+	default:
+		return fmt.Errorf("不支持的邮件加密方式: %s", c.Security)
+	}
+	if c.FromEmail == "" {
+		return fmt.Errorf("邮件配置缺少 fromEmail")
+	}
+	if c.Password == "" && c.Security != emailSecurityNone {
+		return fmt.Errorf("邮件配置缺少 password")
+	}
+	if len(c.ToEmail) == 0 {
+		return fmt.Errorf("邮件配置缺少 toEmail")
+	}
+	for _, addr := range append(append(append([]string{c.FromEmail}, c.ToEmail...), c.CC...), c.BCC...) {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("邮件地址格式错误: %s", addr)
+		}
+	}
+	switch c.Format {
+	case "", "html", "text":
+	default:
+		return fmt.Errorf("不支持的邮件格式: %s", c.Format)
+	}
+	_, err := c.template()
+	return err
+}
+
+// template 邮件 HTML 模板，未配置自定义模板时使用内置模板
+func (c *emailChannel) template() (*template.Template, error) {
+	if c.HTMLTemplate == "" {
+		return emailHTMLTemplate, nil
+	}
+	tpl, err := template.New("email").Parse(c.HTMLTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("邮件 HTML 模板格式错误: %w", err)
+	}
+	return tpl, nil
+}
+
+// Send 发送单条告警邮件，主题模板使用告警的变量渲染
+func (c *emailChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	agent, record := notification.Agent, notification.Record
+	message := n.buildChannelMessage(notification.Channel, agent, record, notification.MaskIP)
+	card := n.buildChatCard(notification.Channel, agent, record, notification.MaskIP)
+
+	displayAgent := *agent
+	if notification.MaskIP {
+		displayAgent.IP = maskIPAddress(agent.IP)
+	}
+	subject := i18n.T("notify.email.subject", record.Level, agent.Name, card.Title)
+	if c.Subject != "" {
+		subject = RenderMessageTemplate(c.Subject, &displayAgent, record, message)
+	}
+
+	view := &emailView{
+		Title: card.Title,
+		Color: emailToneColor(card.Tone),
+		Cards: []emailCard{newEmailCard(card)},
+	}
+	return c.send(ctx, n, subject, message, view)
+}
+
+// SendGroup 发送告警汇总邮件，一封邮件列出分组内的所有告警
+func (c *emailChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	group := notification.Group
+	first := group.Items[0]
+	title, _, _ := strings.Cut(n.buildGroupMessage(group, notification.MaskIP), "\n")
+
+	subject := i18n.T("notify.email.digest_subject", title, strconv.Itoa(len(group.Items)))
+	if c.Subject != "" {
+		displayAgent := *first.Agent
+		if notification.MaskIP {
+			displayAgent.IP = maskIPAddress(first.Agent.IP)
+		}
+		tpl := strings.ReplaceAll(c.Subject, "{{count}}", strconv.Itoa(len(group.Items)))
+		subject = RenderMessageTemplate(tpl, &displayAgent, first.Record, title)
+	}
+
+	view := &emailView{
+		Title:   title,
+		Color:   emailToneColor(buildGroupChatCard(group, title).Tone),
+		Summary: groupEmailSummary(group),
+	}
+	for i, item := range group.Items {
+		if i >= maxEmailDigestItems {
+			view.More = i18n.T("notify.group.more", strconv.Itoa(len(group.Items)))
+			break
+		}
+		card := n.buildChatCard(notification.Channel, item.Agent, item.Record, notification.MaskIP)
+		view.Cards = append(view.Cards, newEmailCard(card))
+	}
+	return c.send(ctx, n, subject, view.text(), view)
+}
+
+func (c *emailChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	subject := i18n.T("notify.email_subject")
+	view := &emailView{
+		Title: subject,
+		Color: emailToneColor(chatToneInfo),
+		Cards: []emailCard{{Text: message, Color: emailToneColor(chatToneInfo)}},
+	}
+	return c.send(ctx, n, subject, message, view)
+}
+
+// groupEmailSummary 汇总邮件的概要信息
+func groupEmailSummary(group *AlertGroup) []string {
+	agentIDs := make(map[string]struct{}, len(group.Items))
+	for _, item := range group.Items {
+		agentIDs[item.Record.AgentID] = struct{}{}
+	}
+	summary := []string{
+		i18n.T("notify.group.count", strconv.Itoa(len(group.Items))),
+		i18n.T("notify.group.agents", strconv.Itoa(len(agentIDs))),
+	}
+	if tags := group.Labels[models.AlertGroupByTag]; tags != "" {
+		summary = append(summary, i18n.T("notify.group.tags", tags))
+	}
+	return summary
+}
+
+// send 发送邮件，text 为纯文本正文，HTML 格式时 view 渲染为 HTML 正文
+func (c *emailChannel) send(ctx context.Context, n *Notifier, subject, text string, view *emailView) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", c.FromEmail)
+	m.SetHeader("To", c.ToEmail...)
+	if len(c.CC) > 0 {
+		m.SetHeader("Cc", c.CC...)
+	}
+	if len(c.BCC) > 0 {
+		m.SetHeader("Bcc", c.BCC...)
+	}
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/plain", text)
+	if c.Format != "text" {
+		tpl, err := c.template()
+		if err != nil {
+			return err
+		}
+		view.Footer = i18n.T("notify.email.footer")
+		var html bytes.Buffer
+		if err := tpl.Execute(&html, view); err != nil {
+			return fmt.Errorf("渲染邮件 HTML 模板失败: %w", err)
+		}
+		m.AddAlternative("text/html", html.String())
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, emailTimeout)
+		defer cancel()
+	}
+	client, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	err = gomail.Send(gomail.SendFunc(func(from string, to []string, msg io.WriterTo) error {
+		if err := client.Mail(from); err != nil {
+			return err
+		}
+		for _, addr := range to {
+			if err := client.Rcpt(addr); err != nil {
+				return err
+			}
+		}
+		w, err := client.Data()
+		if err != nil {
+			return err
+		}
+		if _, err := msg.WriteTo(w); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	}), m)
+	if err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	_ = client.Quit()
+
+	n.logger.Info("邮件发送成功",
+		zap.String("from", c.FromEmail),
+		zap.Strings("to", c.ToEmail),
+		zap.Strings("cc", c.CC),
+		zap.Int("bcc", len(c.BCC)),
+		zap.String("subject", subject),
+	)
+	return nil
+}
+
+// security 实际使用的加密方式，为空时 465 端口使用 ssl
+func (c *emailChannel) security() string {
+	if c.Security == "" && c.SMTPPort == 465 {
+		return emailSecuritySSL
+	}
+	return c.Security
+}
+
+// dial 按加密方式连接 SMTP 服务器并完成认证
+func (c *emailChannel) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(c.SMTPHost, strconv.Itoa(int(c.SMTPPort)))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	// smtp.Client 不支持 context，通过连接的截止时间限制整个发送过程
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	security := c.security()
+	tlsConfig := &tls.Config{ServerName: c.SMTPHost}
+	if security == emailSecuritySSL {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, c.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if security != emailSecuritySSL && security != emailSecurityNone {
+		ok, _ := client.Extension("STARTTLS")
+		if !ok && security == emailSecurityStartTLS {
+			client.Close()
+			return nil, fmt.Errorf("SMTP 服务器不支持 STARTTLS")
+		}
+		if ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if c.Password != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := &smtpAuth{
+				username:      c.FromEmail,
+				password:      c.Password,
+				allowInsecure: security == emailSecurityNone,
+			}
+			if err := client.Auth(auth); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// smtpAuth SMTP 认证，服务器支持 PLAIN 时使用 PLAIN，否则使用 LOGIN
+//
+// 默认只在 TLS 连接上发送密码，加密方式为 none 时允许明文认证。
+type smtpAuth struct {
+	username      string
+	password      string
+	allowInsecure bool
+	login         bool
+}
+
+func (a *smtpAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !a.allowInsecure {
+		return "", nil, errors.New("SMTP 连接未加密，拒绝发送密码，如需明文认证请将 security 设置为 none")
+	}
+	plain, login := false, false
+	for _, mechanism := range server.Auth {
+		switch strings.ToUpper(mechanism) {
+		case "PLAIN":
+			plain = true
+		case "LOGIN":
+			login = true
+		}
+	}
+	if login && !plain {
+		a.login = true
+		return "LOGIN", nil, nil
+	}
+	return "PLAIN", []byte("\x00" + a.username + "\x00" + a.password), nil
+}
+
+func (a *smtpAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	if !a.login {
+		return nil, errors.New("SMTP 服务器返回了意外的认证质询")
+	}
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("SMTP 服务器返回了意外的认证质询: %s", fromServer)
+	}
+}
+
+// emailView 邮件 HTML 模板的数据，单条告警只有一张卡片，汇总邮件每条告警一张卡片
+type emailView struct {
+	Title   string      // 标题
+	Color   string      // 主色调，如 #D32F2F
+	Summary []string    // 概要信息，汇总邮件使用
+	Cards   []emailCard // 告警卡片
+	More    string      // 告警过多被省略时的提示
+	Footer  string      // 页脚
+}
+
+// emailCard 邮件中的告警卡片
+type emailCard struct {
+	Title  string
+	Color  string
+	Text   string
+	Fields []chatField
+}
+
+func newEmailCard(card *chatCard) emailCard {
+	return emailCard{
+		Title:  card.Title,
+		Color:  emailToneColor(card.Tone),
+		Text:   card.Text,
+		Fields: card.Fields,
+	}
+}
+
+// emailToneColor 色调对应的 HTML 颜色
+func emailToneColor(tone string) string {
+	return fmt.Sprintf("#%06X", chatToneColor(tone))
+}
+
+// text 汇总邮件的纯文本正文
+func (v *emailView) text() string {
+	var sb strings.Builder
+	sb.WriteString(v.Title + "\n\n")
+	for _, line := range v.Summary {
+		sb.WriteString(line + "\n")
+	}
+	for _, card := range v.Cards {
+		sb.WriteString("\n")
+		if card.Title != "" {
+			sb.WriteString(card.Title + "\n")
+		}
+		sb.WriteString(card.Text + "\n")
+		for _, field := range card.Fields {
+			sb.WriteString(field.Name + ": " + field.Value + "\n")
+		}
+	}
+	if v.More != "" {
+		sb.WriteString("\n" + v.More + "\n")
+	}
+	return sb.String()
+}
+
+// emailHTMLTemplate 内置的邮件 HTML 模板，使用表格布局和内联样式以兼容常见邮件客户端
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body style="margin:0;padding:0;background:#f4f5f7;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background:#f4f5f7;padding:24px 0;">
+<tr><td align="center">
+<table role="presentation" width="640" cellpadding="0" cellspacing="0" style="max-width:640px;width:100%;background:#ffffff;border-radius:6px;overflow:hidden;font-family:-apple-system,'Segoe UI',Roboto,'Helvetica Neue',Arial,'PingFang SC','Microsoft YaHei',sans-serif;color:#333333;">
+<tr><td style="background:{{.Color}};padding:16px 24px;color:#ffffff;font-size:18px;font-weight:600;">{{.Title}}</td></tr>
+{{if .Summary}}<tr><td style="padding:16px 24px 0 24px;font-size:14px;line-height:22px;color:#555555;">{{range .Summary}}<div>{{.}}</div>{{end}}</td></tr>{{end}}
+{{range .Cards}}<tr><td style="padding:16px 24px 0 24px;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="border:1px solid #e5e7eb;border-left:4px solid {{.Color}};border-radius:4px;">
+{{if .Title}}<tr><td colspan="2" style="padding:12px 16px 0 16px;font-size:15px;font-weight:600;">{{.Title}}</td></tr>{{end}}
+<tr><td colspan="2" style="padding:8px 16px;font-size:14px;line-height:22px;white-space:pre-wrap;">{{.Text}}</td></tr>
+{{range .Fields}}<tr><td style="padding:2px 16px;width:120px;font-size:13px;color:#888888;vertical-align:top;">{{.Name}}</td><td style="padding:2px 16px 2px 0;font-size:13px;">{{.Value}}</td></tr>{{end}}
+<tr><td colspan="2" style="height:10px;"></td></tr>
+</table>
+</td></tr>{{end}}
+{{if .More}}<tr><td style="padding:12px 24px 0 24px;font-size:13px;color:#888888;">{{.More}}</td></tr>{{end}}
+<tr><td style="padding:20px 24px;font-size:12px;color:#aaaaaa;">{{.Footer}}</td></tr>
+</table>
+</td></tr>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// smtpSession 测试 SMTP 服务器收到的一封邮件
+type smtpSession struct {
+	Auth       string
+	From       string
+	Recipients []string
+	Data       string
+}
+
+// newSMTPServer 创建只支持明文连接的测试 SMTP 服务器，extensions 为 EHLO 响应的扩展
+func newSMTPServer(t *testing.T, extensions ...string) (string, int, func() []smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听端口失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var (
+		mu       sync.Mutex
+		sessions []smtpSession
+	)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+				var session smtpSession
+				reply("220 test ESMTP")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimRight(line, "\r\n")
+					cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+					switch cmd {
+					case "EHLO":
+						lines := append([]string{"test"}, extensions...)
+						for i, ext := range lines {
+							if i == len(lines)-1 {
+								reply("250 " + ext)
+							} else {
+								reply("250-" + ext)
+							}
+						}
+					case "AUTH":
+						session.Auth = line
+						reply("235 ok")
+					case "MAIL":
+						session.From = line
+						reply("250 ok")
+					case "RCPT":
+						session.Recipients = append(session.Recipients, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+						reply("250 ok")
+					case "DATA":
+						reply("354 go")
+						var sb strings.Builder
+						for {
+							dataLine, err := r.ReadString('\n')
+							if err != nil {
+								return
+							}
+							if dataLine == ".\r\n" {
+								break
+							}
+							sb.WriteString(dataLine)
+						}
+						session.Data = sb.String()
+						mu.Lock()
+						sessions = append(sessions, session)
+						mu.Unlock()
+						reply("250 queued")
+					case "QUIT":
+						reply("221 bye")
+						return
+					default:
+						reply("250 ok")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, func() []smtpSession {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]smtpSession(nil), sessions...)
+	}
+}
+
+func TestSendNotificationEmail(t *testing.T) {
+	host, port, sessions := newSMTPServer(t, "AUTH PLAIN LOGIN")
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	channel := &models.NotificationChannelConfig{
+		Type:    "email",
+		Enabled: true,
+		Config: map[string]interface{}{
+			"smtpHost":  host,
+			"smtpPort":  port,
+			"security":  "none",
+			"fromEmail": "pika@example.com",
+			"password":  "secret",
+			"toEmail":   "ops@example.com; dev@example.com",
+			"cc":        []interface{}{"lead@example.com"},
+			"bcc":       "audit@example.com",
+			"subject":   "[{{alert.level}}] {{agent.name}} {{agent.ip}}",
+		},
+	}
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.1.2"}
+	record := &models.AlertRecord{ID: 7, AgentID: "agent-1", AlertType: "cpu", Level: "critical", Status: "firing", Message: "CPU usage 95%", Threshold: 80, ActualValue: 95, FiredAt: 1700000000000}
+
+	if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, true); err != nil {
+		t.Fatalf("发送邮件失败: %v", err)
+	}
+
+	got := sessions()
+	if len(got) != 1 {
+		t.Fatalf("期望发送 1 封邮件，实际 %d 封", len(got))
+	}
+	session := got[0]
+	wantAuth := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00pika@example.com\x00secret"))
+	if session.Auth != wantAuth {
+		t.Errorf("认证命令 = %q，期望 %q", session.Auth, wantAuth)
+	}
+	if strings.Join(session.Recipients, ",") != "ops@example.com,dev@example.com,lead@example.com,audit@example.com" {
+		t.Errorf("收件人不符合预期: %v", session.Recipients)
+	}
+	for _, want := range []string{
+		"Subject: [critical] web-1 10.0.*.*",
+		"To: ops@example.com, dev@example.com",
+		"Cc: lead@example.com",
+		"multipart/alternative",
+		"Content-Type: text/plain",
+		"Content-Type: text/html",
+	} {
+		if !strings.Contains(session.Data, want) {
+			t.Errorf("邮件内容缺少 %q:\n%s", want, session.Data)
+		}
+	}
+	if strings.Contains(session.Data, "audit@example.com") {
+		t.Errorf("密送地址不应出现在邮件头中")
+	}
+	if strings.Contains(session.Data, "10.0.1.2") {
+		t.Errorf("邮件中的 IP 应打码")
+	}
+}
+
+func TestSendGroupNotificationEmailDigest(t *testing.T) {
+	host, port, sessions := newSMTPServer(t)
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	channel := &models.NotificationChannelConfig{
+		Type:    "email",
+		Enabled: true,
+		Config: map[string]interface{}{
+			"smtpHost":  host,
+			"smtpPort":  port,
+			"security":  "none",
+			"fromEmail": "pika@example.com",
+			"toEmail":   "ops@example.com",
+			"subject":   "{{count}} alerts",
+		},
+	}
+	group := &AlertGroup{Key: "cpu", Status: "firing", Labels: map[string]string{models.AlertGroupByAlertType: "cpu"}}
+	for _, name := range []string{"web-1", "web-2"} {
+		group.Items = append(group.Items, AlertGroupItem{
+			Agent:  &models.Agent{ID: name, Name: name, IP: "10.0.0.1"},
+			Record: &models.AlertRecord{AgentID: name, AlertType: "cpu", Level: "warning", Status: "firing", Message: name + " cpu high", FiredAt: 1700000000000},
+		})
+	}
+
+	if err := notifier.SendGroupNotificationByConfig(context.Background(), channel, group, false); err != nil {
+		t.Fatalf("发送汇总邮件失败: %v", err)
+	}
+
+	got := sessions()
+	if len(got) != 1 {
+		t.Fatalf("汇总邮件应只发送 1 封，实际 %d 封", len(got))
+	}
+	data := got[0].Data
+	if got[0].Auth != "" {
+		t.Errorf("未配置密码时不应认证: %s", got[0].Auth)
+	}
+	if !strings.Contains(data, "Subject: 2 alerts") {
+		t.Errorf("主题不符合预期:\n%s", data)
+	}
+	if strings.Count(data, "web-1 cpu high") < 2 || strings.Count(data, "web-2 cpu high") < 2 {
+		t.Errorf("纯文本和 HTML 正文都应列出所有告警:\n%s", data)
+	}
+}
+
+func TestSendTestNotificationEmailSecurity(t *testing.T) {
+	host, port, _ := newSMTPServer(t, "AUTH PLAIN")
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	base := map[string]interface{}{
+		"smtpHost":  host,
+		"smtpPort":  port,
+		"fromEmail": "pika@example.com",
+		"password":  "secret",
+		"toEmail":   "ops@example.com",
+	}
+	withSecurity := func(security string) map[string]interface{} {
+		config := map[string]interface{}{"security": security}
+		for k, v := range base {
+			config[k] = v
+		}
+		return config
+	}
+
+	// 服务器不支持 STARTTLS 时，starttls 模式必须失败
+	err := notifier.SendTestNotification(context.Background(), "email", withSecurity("starttls"), "hello")
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("starttls 模式应因服务器不支持 STARTTLS 而失败，实际 %v", err)
+	}
+
+	// 默认模式下不通过未加密的连接发送密码
+	err = notifier.SendTestNotification(context.Background(), "email", withSecurity(""), "hello")
+	if err == nil || !strings.Contains(err.Error(), "未加密") {
+		t.Errorf("未加密的连接应拒绝发送密码，实际 %v", err)
+	}
+
+	if err := notifier.SendTestNotification(context.Background(), "email", withSecurity("tls13"), "hello"); err == nil {
+		t.Errorf("不支持的加密方式应校验失败")
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultExecTimeout exec 渠道脚本的默认超时时间
+	defaultExecTimeout = 30 * time.Second
+	// maxExecOutput 记录脚本输出的最大字节数，超出部分丢弃
+	maxExecOutput = 64 * 1024
+)
+
+// execChannel exec 渠道，在服务端执行本地脚本，告警以 JSON 格式写入脚本的标准输入
+//
+// 脚本只能位于服务端配置的 Notification.ExecScriptDir 目录下，未配置该目录时渠道不可用。
+type execChannel struct {
+	Script  string    `json:"script"`  // 脚本路径，相对路径基于脚本目录
+	Args    []string  `json:"args"`    // 脚本参数，可选
+	Timeout configInt `json:"timeout"` // 超时时间（秒），默认 30 秒
+}
+
+// execPayload 写入脚本标准输入的内容
+type execPayload struct {
+	Kind        string            `json:"kind"`   // 通知类型: alert, group, test
+	Status      string            `json:"status"` // 告警状态: firing, acknowledged, resolved, flapping
+	Message     string            `json:"message"`
+	GroupKey    string            `json:"groupKey,omitempty"`
+	GroupLabels map[string]string `json:"groupLabels,omitempty"`
+	Alerts      []execAlert       `json:"alerts"`
+}
+
+// execAlert 脚本收到的告警
+type execAlert struct {
+	ID             int64             `json:"id"`
+	StateID        string            `json:"stateId,omitempty"`
+	Type           string            `json:"type"`
+	Level          string            `json:"level"`
+	Status         string            `json:"status"`
+	Message        string            `json:"message"`
+	Threshold      float64           `json:"threshold"`
+	ActualValue    float64           `json:"actualValue"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	FiredAt        int64             `json:"firedAt"`
+	ResolvedAt     int64             `json:"resolvedAt,omitempty"`
+	AcknowledgedBy string            `json:"acknowledgedBy,omitempty"`
+	Assignee       string            `json:"assignee,omitempty"`
+	Agent          execAgent         `json:"agent"`
+}
+
+// execAgent 告警所属的探针
+type execAgent struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	IP       string `json:"ip"`
+}
+
+// newExecAlert 将告警记录转换为脚本收到的告警
+func newExecAlert(agent *models.Agent, record *models.AlertRecord, maskIP bool) execAlert {
+	displayIP := agent.IP
+	if maskIP {
+		displayIP = maskIPAddress(agent.IP)
+	}
+	return execAlert{
+		ID:             record.ID,
+		StateID:        record.StateID,
+		Type:           record.AlertType,
+		Level:          record.Level,
+		Status:         record.Status,
+		Message:        record.Message,
+		Threshold:      record.Threshold,
+		ActualValue:    record.ActualValue,
+		Labels:         record.Labels.Data(),
+		FiredAt:        record.FiredAt,
+		ResolvedAt:     record.ResolvedAt,
+		AcknowledgedBy: record.AcknowledgedBy,
+		Assignee:       record.Assignee,
+		Agent: execAgent{
+			ID:       agent.ID,
+			Name:     agent.Name,
+			Hostname: agent.Hostname,
+			IP:       displayIP,
+		},
+	}
+}
+
+func (c *execChannel) Validate() error {
+	if c.Script == "" {
+		return fmt.Errorf("exec 配置缺少 script")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("exec 配置的 timeout 不能小于 0")
+	}
+	return nil
+}
+
+func (c *execChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	record := notification.Record
+	return c.run(ctx, n, &execPayload{
+		Kind:    "alert",
+		Status:  record.Status,
+		Message: n.buildChannelMessage(notification.Channel, notification.Agent, record, notification.MaskIP),
+		Alerts:  []execAlert{newExecAlert(notification.Agent, record, notification.MaskIP)},
+	})
+}
+
+func (c *execChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	group := notification.Group
+	alerts := make([]execAlert, 0, len(group.Items))
+	for _, item := range group.Items {
+		alerts = append(alerts, newExecAlert(item.Agent, item.Record, notification.MaskIP))
+	}
+	return c.run(ctx, n, &execPayload{
+		Kind:        "group",
+		Status:      group.Status,
+		Message:     n.buildGroupMessage(group, notification.MaskIP),
+		GroupKey:    group.Key,
+		GroupLabels: group.Labels,
+		Alerts:      alerts,
+	})
+}
+
+func (c *execChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	agent, record := newTestAlert(message)
+	return c.run(ctx, n, &execPayload{
+		Kind:    "test",
+		Status:  record.Status,
+		Message: message,
+		Alerts:  []execAlert{newExecAlert(agent, record, false)},
+	})
+}
+
+// resolveScript 解析脚本的实际路径，脚本（包括符号链接指向的文件）必须位于脚本目录内
+func (c *execChannel) resolveScript(scriptDir string) (string, error) {
+	if scriptDir == "" {
+		return "", fmt.Errorf("未配置脚本目录，exec 渠道已禁用")
+	}
+	dir, err := filepath.Abs(scriptDir)
+	if err == nil {
+		dir, err = filepath.EvalSymlinks(dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("脚本目录不可用: %w", err)
+	}
+
+	script := c.Script
+	if !filepath.IsAbs(script) {
+		script = filepath.Join(dir, script)
+	}
+	script, err = filepath.EvalSymlinks(script)
+	if err != nil {
+		return "", fmt.Errorf("脚本不存在: %w", err)
+	}
+
+	rel, err := filepath.Rel(dir, script)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("脚本必须位于脚本目录内: %s", c.Script)
+	}
+	return script, nil
+}
+
+// run 执行脚本，脚本以非 0 状态码退出或超时视为发送失败
+func (c *execChannel) run(ctx context.Context, n *Notifier, payload *execPayload) error {
+	script, err := c.resolveScript(n.execScriptDir)
+	if err != nil {
+		return err
+	}
+
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	timeout := defaultExecTimeout
+	if c.Timeout > 0 {
+		timeout = time.Duration(c.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: maxExecOutput}
+	cmd := exec.CommandContext(ctx, script, c.Args...)
+	cmd.Dir = filepath.Dir(script)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// 脚本被终止后，其子进程可能仍持有输出管道，等待一段时间后不再读取
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	n.logger.Info("exec 渠道脚本执行完成",
+		zap.String("script", script),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("output", output.String()),
+		zap.Error(err),
+	)
+
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("脚本执行超时（%s）, 输出: %s", timeout, truncateText(output.String(), 500))
+	}
+	return fmt.Errorf("脚本执行失败: %w, 输出: %s", err, truncateText(output.String(), 500))
+}
+
+// limitedBuffer 只保留前 limit 字节的输出缓冲区
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.buf.Len(); remain < len(p) {
+		b.truncated = true
+		if remain > 0 {
+			b.buf.Write(p[:remain])
+		}
+		// 丢弃超出的部分，避免脚本因写入失败而退出
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	s := strings.TrimSpace(b.buf.String())
+	if b.truncated {
+		s += "..."
+	}
+	return s
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	defaultOpsgenieAPIURL     = "https://api.opsgenie.com"
+)
+
+// incidentSender 事件管理类渠道，这类渠道按告警生命周期创建、确认和关闭事件
+type incidentSender interface {
+	Validate() error
+	sendEvent(ctx context.Context, n *Notifier, agent *models.Agent, record *models.AlertRecord, displayIP, message string) error
+}
+
+// incidentChannel 将事件管理类渠道适配为 Channel
+type incidentChannel struct {
+	incidentSender
+}
+
+// Send 根据告警状态发送触发、确认或恢复事件，其他状态的通知不会创建事件
+func (c incidentChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	record := notification.Record
+	switch record.Status {
+	case "firing", "acknowledged", "resolved":
+	default:
+		n.logger.Debug("事件管理渠道忽略该状态的通知",
+			zap.String("channelType", notification.Channel.Type),
+			zap.String("status", record.Status),
+		)
+		return nil
+	}
+
+	agent := notification.Agent
+	displayIP := agent.IP
+	if notification.MaskIP {
+		displayIP = maskIPAddress(agent.IP)
+	}
+	message := n.buildChannelMessage(notification.Channel, agent, record, notification.MaskIP)
+	return c.sendEvent(ctx, n, agent, record, displayIP, message)
+}
+
+// SendGroup 按单条告警发送聚合通知中的每个告警，保持事件与告警一一对应
+func (c incidentChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	var errs []error
+	for _, item := range notification.Group.Items {
+		err := c.Send(ctx, n, &Notification{
+			Channel: notification.Channel,
+			Record:  item.Record,
+			Agent:   item.Agent,
+			MaskIP:  notification.MaskIP,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c incidentChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	return sendLifecycleTest(ctx, n, c, message)
+}
+
+// incidentChannelOf 事件管理类渠道的 ChannelFactory
+func incidentChannelOf[T any, P interface {
+	*T
+	incidentSender
+}]() ChannelFactory {
+	return typedChannel(func(c *T) Channel { return incidentChannel{P(c)} })
+}
+
+// incidentDedupKey 事件去重 key，同一告警状态的触发和恢复使用相同的 key，
+// 没有告警状态的记录（如防篡改告警）使用记录ID
+func incidentDedupKey(record *models.AlertRecord) string {
+	key := record.StateID
+	if key == "" {
+		key = "record:" + strconv.FormatInt(record.ID, 10)
+	}
+	key = "pika:" + key
+	// PagerDuty dedup_key 最长 255 字符，超长时使用摘要
+	if len(key) > 255 {
+		sum := sha256.Sum256([]byte(key))
+		key = "pika:" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// incidentSummary 事件标题
+func incidentSummary(agent *models.Agent, record *models.AlertRecord) string {
+	return fmt.Sprintf("[%s] %s", agent.Name, record.Message)
+}
+
+// incidentDetails 事件详情
+func incidentDetails(agent *models.Agent, record *models.AlertRecord, displayIP string) map[string]string {
+	details := map[string]string{
+		"agentId":     agent.ID,
+		"agentName":   agent.Name,
+		"hostname":    agent.Hostname,
+		"ip":          displayIP,
+		"alertType":   record.AlertType,
+		"level":       record.Level,
+		"threshold":   fmt.Sprintf("%.2f", record.Threshold),
+		"actualValue": fmt.Sprintf("%.2f", record.ActualValue),
+		"firedAt":     utils.FormatTimestamp(record.FiredAt),
+	}
+	for k, v := range record.Labels.Data() {
+		details["label."+k] = v
+	}
+	return details
+}
+
+// pagerDutySeverities 告警级别对应的 PagerDuty 严重程度
+var pagerDutySeverities = map[string]string{
+	"info":     "info",
+	"warning":  "warning",
+	"critical": "critical",
+}
+
+// pagerDutyEventActions 告警状态对应的 PagerDuty 事件动作
+var pagerDutyEventActions = map[string]string{
+	"firing":       "trigger",
+	"acknowledged": "acknowledge",
+	"resolved":     "resolve",
+}
+
+// pagerDutyChannel PagerDuty 渠道
+type pagerDutyChannel struct {
+	RoutingKey string `json:"routingKey"` // Events API v2 集成 key
+	APIURL     string `json:"apiUrl"`     // 事件接口地址，可选
+}
+
+func (c *pagerDutyChannel) Validate() error {
+	if c.RoutingKey == "" {
+		return fmt.Errorf("PagerDuty 配置缺少 routingKey")
+	}
+	return nil
+}
+
+// sendEvent 通过 Events API v2 发送 PagerDuty 事件
+func (c *pagerDutyChannel) sendEvent(ctx context.Context, n *Notifier, agent *models.Agent, record *models.AlertRecord, displayIP, message string) error {
+	eventsURL := c.APIURL
+	if eventsURL == "" {
+		eventsURL = defaultPagerDutyEventsURL
+	}
+
+	body := map[string]interface{}{
+		"routing_key":  c.RoutingKey,
+		"event_action": pagerDutyEventActions[record.Status],
+		"dedup_key":    incidentDedupKey(record),
+	}
+	if record.Status == "firing" {
+		severity, ok := pagerDutySeverities[record.Level]
+		if !ok {
+			severity = "warning"
+		}
+		source := agent.Hostname
+		if source == "" {
+			source = agent.ID
+		}
+		details := incidentDetails(agent, record, displayIP)
+		details["message"] = message
+		body["payload"] = map[string]interface{}{
+			"summary":        truncateText(incidentSummary(agent, record), 1024),
+			"source":         source,
+			"severity":       severity,
+			"timestamp":      time.UnixMilli(record.FiredAt).UTC().Format(time.RFC3339),
+			"component":      agent.Name,
+			"class":          record.AlertType,
+			"custom_details": details,
+		}
+		body["client"] = "Pika"
+	}
+
+	_, err := n.sendJSONRequest(ctx, eventsURL, body)
+	return err
+}
+
+// opsgeniePriorities 告警级别对应的 Opsgenie 优先级
+var opsgeniePriorities = map[string]string{
+	"info":     "P5",
+	"warning":  "P3",
+	"critical": "P1",
+}
+
+// opsgenieChannel Opsgenie 渠道
+type opsgenieChannel struct {
+	APIKey string `json:"apiKey"`
+	APIURL string `json:"apiUrl"` // 接口地址，EU 区域账号需要配置为 https://api.eu.opsgenie.com
+}
+
+func (c *opsgenieChannel) Validate() error {
+	if c.APIKey == "" {
+		return fmt.Errorf("Opsgenie 配置缺少 apiKey")
+	}
+	return nil
+}
+
+// sendEvent 通过 Alert API 创建、确认或关闭 Opsgenie 告警，使用 alias 关联同一告警
+func (c *opsgenieChannel) sendEvent(ctx context.Context, n *Notifier, agent *models.Agent, record *models.AlertRecord, displayIP, message string) error {
+	apiURL := serverOrDefault(c.APIURL, defaultOpsgenieAPIURL)
+	headers := map[string]string{"Authorization": "GenieKey " + c.APIKey}
+	alias := incidentDedupKey(record)
+
+	switch record.Status {
+	case "firing":
+		priority, ok := opsgeniePriorities[record.Level]
+		if !ok {
+			priority = "P3"
+		}
+		body := map[string]interface{}{
+			"message":     truncateText(incidentSummary(agent, record), 130),
+			"alias":       alias,
+			"description": truncateText(message, 15000),
+			"priority":    priority,
+			"source":      "Pika",
+			"entity":      agent.Name,
+			"tags":        []string{"pika", record.AlertType},
+			"details":     incidentDetails(agent, record, displayIP),
+		}
+		_, err := n.doJSONRequest(ctx, http.MethodPost, apiURL+"/v2/alerts", headers, body)
+		return err
+	case "acknowledged":
+		body := map[string]interface{}{
+			"source": "Pika",
+			"user":   record.AcknowledgedBy,
+			"note":   i18n.T("notify.chat.acknowledged", getAlertTypeMetadata(record.AlertType).Name),
+		}
+		_, err := n.doJSONRequest(ctx, http.MethodPost, opsgenieAlertActionURL(apiURL, alias, "acknowledge"), headers, body)
+		return err
+	default:
+		body := map[string]interface{}{
+			"source": "Pika",
+			"note":   i18n.T("notify.chat.resolved", getAlertTypeMetadata(record.AlertType).Name),
+		}
+		_, err := n.doJSONRequest(ctx, http.MethodPost, opsgenieAlertActionURL(apiURL, alias, "close"), headers, body)
+		return err
+	}
+}
+
+// opsgenieAlertActionURL 按 alias 操作 Opsgenie 告警的地址
+func opsgenieAlertActionURL(apiURL, alias, action string) string {
+	return fmt.Sprintf("%s/v2/alerts/%s/%s?identifierType=alias", apiURL, url.PathEscape(alias), action)
+}
+
+// sendLifecycleTest 向按告警生命周期处理的渠道发送测试告警，先触发再立即恢复，避免在对端留下未关闭的事件
+func sendLifecycleTest(ctx context.Context, n *Notifier, channel Channel, message string) error {
+	agent, record := newTestAlert(message)
+	record.StateID = "test:" + strconv.FormatInt(record.FiredAt, 10)
+	notification := &Notification{
+		Channel: &models.NotificationChannelConfig{Enabled: true},
+		Record:  record,
+		Agent:   agent,
+	}
+	if err := channel.Send(ctx, n, notification); err != nil {
+		return err
+	}
+
+	record.Status = "resolved"
+	record.ResolvedAt = time.Now().UnixMilli()
+	if err := channel.Send(ctx, n, notification); err != nil {
+		return fmt.Errorf("测试告警已触发，但恢复失败: %w", err)
+	}
+	return nil
+}
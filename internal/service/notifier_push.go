@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// pushoverAPIURL Pushover 消息接口地址
+var pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// pushMessage 推送类渠道的消息
+type pushMessage struct {
+	Title   string // 标题
+	Message string // 正文
+	Level   string // 告警级别，决定推送优先级: info, warning, critical
+}
+
+// buildPushMessage 根据告警记录构建推送消息，只有告警触发时使用告警级别，其余状态按 info 推送
+func (n *Notifier) buildPushMessage(channelConfig *models.NotificationChannelConfig, agent *models.Agent, record *models.AlertRecord, maskIP bool) *pushMessage {
+	metadata := getAlertTypeMetadata(record.AlertType)
+	msg := &pushMessage{
+		Message: n.buildChannelMessage(channelConfig, agent, record, maskIP),
+		Level:   "info",
+	}
+	switch record.Status {
+	case "firing":
+		msg.Title = getLevelIcon(record.Level) + " " + metadata.Name
+		msg.Level = record.Level
+	case "resolved":
+		msg.Title = i18n.T("notify.chat.resolved", metadata.Name)
+	case "acknowledged":
+		msg.Title = i18n.T("notify.chat.acknowledged", metadata.Name)
+	case "flapping":
+		msg.Title = i18n.T("notify.chat.flapping", metadata.Name)
+	default:
+		msg.Title = "ℹ️ " + metadata.Name
+	}
+	return msg
+}
+
+// buildGroupPushMessage 构建告警聚合推送消息，标题取聚合消息的首行
+func buildGroupPushMessage(group *AlertGroup, message string) *pushMessage {
+	title, body, _ := strings.Cut(message, "\n\n")
+	level := group.Items[0].Record.Level
+	if group.Status == "resolved" {
+		level = "info"
+	}
+	return &pushMessage{Title: title, Message: body, Level: level}
+}
+
+// pushSender 手机推送类渠道
+type pushSender interface {
+	Validate() error
+	sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error
+}
+
+// pushChannel 将推送类渠道适配为 Channel
+type pushChannel struct {
+	pushSender
+}
+
+func (c pushChannel) Send(ctx context.Context, n *Notifier, notification *Notification) error {
+	return c.sendPush(ctx, n, n.buildPushMessage(notification.Channel, notification.Agent, notification.Record, notification.MaskIP))
+}
+
+func (c pushChannel) SendGroup(ctx context.Context, n *Notifier, notification *GroupNotification) error {
+	message := n.buildGroupMessage(notification.Group, notification.MaskIP)
+	return c.sendPush(ctx, n, buildGroupPushMessage(notification.Group, message))
+}
+
+func (c pushChannel) Test(ctx context.Context, n *Notifier, message string) error {
+	return c.sendPush(ctx, n, &pushMessage{Title: i18n.T("notify.email_subject"), Message: message, Level: "info"})
+}
+
+// pushChannelOf 推送类渠道的 ChannelFactory
+func pushChannelOf[T any, P interface {
+	*T
+	pushSender
+}]() ChannelFactory {
+	return typedChannel(func(c *T) Channel { return pushChannel{P(c)} })
+}
+
+// serverOrDefault 服务地址，未配置时使用默认地址，并去掉末尾的斜杠
+func serverOrDefault(server, defaultServer string) string {
+	if server == "" {
+		server = defaultServer
+	}
+	return strings.TrimSuffix(server, "/")
+}
+
+// ntfyPriorities 告警级别对应的 ntfy 优先级（1-5）
+var ntfyPriorities = map[string]int{
+	"info":     3,
+	"warning":  4,
+	"critical": 5,
+}
+
+// ntfyTags 告警级别对应的 ntfy 标签，ntfy 会将其显示为 emoji
+var ntfyTags = map[string]string{
+	"info":     "information_source",
+	"warning":  "warning",
+	"critical": "rotating_light",
+}
+
+// ntfyChannel ntfy 渠道
+type ntfyChannel struct {
+	Server   string `json:"server"` // 服务地址，默认 https://ntfy.sh
+	Topic    string `json:"topic"`
+	Token    string `json:"token"`    // 访问令牌，可选
+	Username string `json:"username"` // 未配置 token 时使用的用户名，可选
+	Password string `json:"password"`
+}
+
+func (c *ntfyChannel) Validate() error {
+	if c.Topic == "" {
+		return fmt.Errorf("ntfy 配置缺少 topic")
+	}
+	return nil
+}
+
+func (c *ntfyChannel) sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error {
+	server := serverOrDefault(c.Server, "https://ntfy.sh")
+
+	headers := map[string]string{}
+	if c.Token != "" {
+		headers["Authorization"] = "Bearer " + c.Token
+	} else if c.Username != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password))
+	}
+
+	priority, ok := ntfyPriorities[msg.Level]
+	if !ok {
+		priority = 3
+	}
+	body := map[string]interface{}{
+		"topic":    c.Topic,
+		"title":    msg.Title,
+		"message":  msg.Message,
+		"priority": priority,
+	}
+	if tag, ok := ntfyTags[msg.Level]; ok {
+		body["tags"] = []string{tag}
+	}
+
+	// 发布 JSON 消息时需要发送到服务根路径
+	_, err := n.doJSONRequest(ctx, http.MethodPost, server+"/", headers, body)
+	return err
+}
+
+// gotifyPriorities 告警级别对应的 Gotify 优先级（0-10）
+var gotifyPriorities = map[string]int{
+	"info":     2,
+	"warning":  5,
+	"critical": 8,
+}
+
+// gotifyChannel Gotify 渠道
+type gotifyChannel struct {
+	Server string `json:"server"`
+	Token  string `json:"token"` // 应用 token
+}
+
+func (c *gotifyChannel) Validate() error {
+	if c.Server == "" {
+		return fmt.Errorf("Gotify 配置缺少 server")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("Gotify 配置缺少 token")
+	}
+	return nil
+}
+
+func (c *gotifyChannel) sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error {
+	priority, ok := gotifyPriorities[msg.Level]
+	if !ok {
+		priority = 2
+	}
+	body := map[string]interface{}{
+		"title":    msg.Title,
+		"message":  msg.Message,
+		"priority": priority,
+	}
+	headers := map[string]string{"X-Gotify-Key": c.Token}
+	_, err := n.doJSONRequest(ctx, http.MethodPost, strings.TrimSuffix(c.Server, "/")+"/message", headers, body)
+	return err
+}
+
+// barkLevels 告警级别对应的 Bark 中断级别，critical 会忽略静音和专注模式
+var barkLevels = map[string]string{
+	"info":     "active",
+	"warning":  "timeSensitive",
+	"critical": "critical",
+}
+
+// barkChannel Bark 渠道
+type barkChannel struct {
+	Server    string `json:"server"` // 服务地址，默认 https://api.day.app
+	DeviceKey string `json:"deviceKey"`
+	Sound     string `json:"sound"` // 提示音，可选
+}
+
+func (c *barkChannel) Validate() error {
+	if c.DeviceKey == "" {
+		return fmt.Errorf("Bark 配置缺少 deviceKey")
+	}
+	return nil
+}
+
+func (c *barkChannel) sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error {
+	server := serverOrDefault(c.Server, "https://api.day.app")
+
+	level, ok := barkLevels[msg.Level]
+	if !ok {
+		level = "active"
+	}
+	body := map[string]interface{}{
+		"device_key": c.DeviceKey,
+		"title":      msg.Title,
+		"body":       msg.Message,
+		"level":      level,
+		"group":      "Pika",
+	}
+	if c.Sound != "" {
+		body["sound"] = c.Sound
+	}
+	_, err := n.sendJSONRequest(ctx, server+"/push", body)
+	return err
+}
+
+// pushoverPriorities 告警级别对应的 Pushover 优先级，不使用需要回执的紧急级别（2）
+var pushoverPriorities = map[string]int{
+	"info":     -1,
+	"warning":  0,
+	"critical": 1,
+}
+
+// pushoverChannel Pushover 渠道
+type pushoverChannel struct {
+	Token  string `json:"token"`  // 应用 token
+	User   string `json:"user"`   // 用户 key
+	Device string `json:"device"` // 接收设备，可选
+}
+
+func (c *pushoverChannel) Validate() error {
+	if c.Token == "" {
+		return fmt.Errorf("Pushover 配置缺少 token")
+	}
+	if c.User == "" {
+		return fmt.Errorf("Pushover 配置缺少 user")
+	}
+	return nil
+}
+
+func (c *pushoverChannel) sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error {
+	body := map[string]interface{}{
+		"token":    c.Token,
+		"user":     c.User,
+		"title":    truncateText(msg.Title, 250),
+		"message":  truncateText(msg.Message, 1024),
+		"priority": pushoverPriorities[msg.Level],
+	}
+	if c.Device != "" {
+		body["device"] = c.Device
+	}
+	_, err := n.sendJSONRequest(ctx, pushoverAPIURL, body)
+	return err
+}
+
+// matrixChannel Matrix 渠道，向指定房间发送消息
+type matrixChannel struct {
+	Homeserver  string `json:"homeserver"`
+	AccessToken string `json:"accessToken"`
+	RoomID      string `json:"roomId"`
+}
+
+func (c *matrixChannel) Validate() error {
+	if c.Homeserver == "" {
+		return fmt.Errorf("Matrix 配置缺少 homeserver")
+	}
+	if c.AccessToken == "" {
+		return fmt.Errorf("Matrix 配置缺少 accessToken")
+	}
+	if c.RoomID == "" {
+		return fmt.Errorf("Matrix 配置缺少 roomId")
+	}
+	return nil
+}
+
+func (c *matrixChannel) sendPush(ctx context.Context, n *Notifier, msg *pushMessage) error {
+
+	// 事务 ID 用于服务端去重，每条消息唯一即可
+	txnID := fmt.Sprintf("pika-%d", time.Now().UnixNano())
+	sendURL := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(c.Homeserver, "/"), url.PathEscape(c.RoomID), txnID)
+
+	body := map[string]interface{}{
+		"msgtype":        "m.text",
+		"body":           msg.Title + "\n\n" + msg.Message,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": "<strong>" + html.EscapeString(msg.Title) + "</strong><br><br>" + strings.ReplaceAll(html.EscapeString(msg.Message), "\n", "<br>"),
+	}
+	headers := map[string]string{"Authorization": "Bearer " + c.AccessToken}
+	_, err := n.doJSONRequest(ctx, http.MethodPut, sendURL, headers, body)
+	return err
+}
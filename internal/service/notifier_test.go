@@ -0,0 +1,550 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// capturedRequest 测试服务器收到的请求
+type capturedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// newCaptureServer 创建记录请求内容的测试服务器，响应固定的 JSON
+func newCaptureServer(t *testing.T, response string) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("解析请求体失败: %v", err)
+		}
+		requests = append(requests, capturedRequest{
+			Method: r.Method,
+			Path:   r.URL.EscapedPath(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestSendTestNotificationPushChannels(t *testing.T) {
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		channel    string
+		config     func(serverURL string) map[string]interface{}
+		wantMethod string
+		wantPath   string
+		wantHeader map[string]string
+		wantBody   map[string]interface{}
+	}{
+		{
+			name:    "ntfy",
+			channel: "ntfy",
+			config: func(serverURL string) map[string]interface{} {
+				return map[string]interface{}{"server": serverURL, "topic": "alerts", "token": "tk_123"}
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/",
+			wantHeader: map[string]string{"Authorization": "Bearer tk_123"},
+			wantBody:   map[string]interface{}{"topic": "alerts", "message": "hello", "priority": float64(3)},
+		},
+		{
+			name:    "gotify",
+			channel: "gotify",
+			config: func(serverURL string) map[string]interface{} {
+				return map[string]interface{}{"server": serverURL + "/", "token": "app-token"}
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/message",
+			wantHeader: map[string]string{"X-Gotify-Key": "app-token"},
+			wantBody:   map[string]interface{}{"message": "hello", "priority": float64(2)},
+		},
+		{
+			name:    "bark",
+			channel: "bark",
+			config: func(serverURL string) map[string]interface{} {
+				return map[string]interface{}{"server": serverURL, "deviceKey": "device-1", "sound": "alarm"}
+			},
+			wantMethod: http.MethodPost,
+			wantPath:   "/push",
+			wantBody:   map[string]interface{}{"device_key": "device-1", "body": "hello", "level": "active", "sound": "alarm"},
+		},
+		{
+			name:    "matrix",
+			channel: "matrix",
+			config: func(serverURL string) map[string]interface{} {
+				return map[string]interface{}{"homeserver": serverURL, "accessToken": "syt_abc", "roomId": "!room:example.org"}
+			},
+			wantMethod: http.MethodPut,
+			wantPath:   "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/",
+			wantHeader: map[string]string{"Authorization": "Bearer syt_abc"},
+			wantBody:   map[string]interface{}{"msgtype": "m.text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newCaptureServer(t, `{}`)
+			if err := notifier.SendTestNotification(ctx, tt.channel, tt.config(server.URL), "hello"); err != nil {
+				t.Fatalf("发送测试通知失败: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("期望收到 1 个请求，实际 %d 个", len(*requests))
+			}
+			req := (*requests)[0]
+			if req.Method != tt.wantMethod {
+				t.Errorf("请求方法 = %s，期望 %s", req.Method, tt.wantMethod)
+			}
+			if !strings.HasPrefix(req.Path, tt.wantPath) {
+				t.Errorf("请求路径 = %s，期望前缀 %s", req.Path, tt.wantPath)
+			}
+			for k, v := range tt.wantHeader {
+				if got := req.Header.Get(k); got != v {
+					t.Errorf("请求头 %s = %q，期望 %q", k, got, v)
+				}
+			}
+			for k, v := range tt.wantBody {
+				if req.Body[k] != v {
+					t.Errorf("请求体 %s = %v，期望 %v", k, req.Body[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestSendTestNotificationPushover(t *testing.T) {
+	server, requests := newCaptureServer(t, `{"status":1}`)
+	defer func(origin string) { pushoverAPIURL = origin }(pushoverAPIURL)
+	pushoverAPIURL = server.URL + "/1/messages.json"
+
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	config := map[string]interface{}{"token": "app-token", "user": "user-key"}
+	if err := notifier.SendTestNotification(context.Background(), "pushover", config, "hello"); err != nil {
+		t.Fatalf("发送测试通知失败: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("期望收到 1 个请求，实际 %d 个", len(*requests))
+	}
+	body := (*requests)[0].Body
+	if body["token"] != "app-token" || body["user"] != "user-key" || body["message"] != "hello" {
+		t.Errorf("请求体不符合预期: %v", body)
+	}
+	if body["priority"] != float64(-1) {
+		t.Errorf("测试通知优先级 = %v，期望 -1", body["priority"])
+	}
+}
+
+func TestSendTestNotificationMissingConfig(t *testing.T) {
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	for _, channel := range []string{"ntfy", "gotify", "bark", "pushover", "matrix"} {
+		if err := notifier.SendTestNotification(context.Background(), channel, map[string]interface{}{}, "hello"); err == nil {
+			t.Errorf("%s 缺少必填配置时应返回错误", channel)
+		}
+	}
+}
+
+func TestSendNotificationPushPriorityByLevel(t *testing.T) {
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.0.1"}
+
+	tests := []struct {
+		level        string
+		status       string
+		wantPriority float64
+	}{
+		{level: "critical", status: "firing", wantPriority: 5},
+		{level: "warning", status: "firing", wantPriority: 4},
+		{level: "info", status: "firing", wantPriority: 3},
+		{level: "critical", status: "resolved", wantPriority: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level+"_"+tt.status, func(t *testing.T) {
+			server, requests := newCaptureServer(t, `{}`)
+			channel := &models.NotificationChannelConfig{
+				Type:    "ntfy",
+				Enabled: true,
+				Config:  map[string]interface{}{"server": server.URL, "topic": "alerts"},
+			}
+			record := &models.AlertRecord{
+				AgentID:   agent.ID,
+				AlertType: "cpu",
+				Message:   "CPU",
+				Level:     tt.level,
+				Status:    tt.status,
+			}
+			if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, false); err != nil {
+				t.Fatalf("发送通知失败: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("期望收到 1 个请求，实际 %d 个", len(*requests))
+			}
+			if got := (*requests)[0].Body["priority"]; got != tt.wantPriority {
+				t.Errorf("优先级 = %v，期望 %v", got, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestSendNotificationPagerDutyLifecycle(t *testing.T) {
+	server, requests := newCaptureServer(t, `{"status":"success"}`)
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.0.1"}
+	channel := &models.NotificationChannelConfig{
+		Type:    "pagerduty",
+		Enabled: true,
+		Config:  map[string]interface{}{"routingKey": "rk", "apiUrl": server.URL + "/v2/enqueue"},
+	}
+	record := &models.AlertRecord{
+		ID:        10,
+		StateID:   "agent-1:global:cpu",
+		AgentID:   agent.ID,
+		AlertType: "cpu",
+		Message:   "CPU",
+		Level:     "critical",
+		Status:    "firing",
+	}
+
+	for _, status := range []string{"firing", "acknowledged", "resolved", "flapping"} {
+		record.Status = status
+		if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, false); err != nil {
+			t.Fatalf("发送 %s 事件失败: %v", status, err)
+		}
+	}
+
+	// 抖动通知不产生事件
+	if len(*requests) != 3 {
+		t.Fatalf("期望收到 3 个请求，实际 %d 个", len(*requests))
+	}
+	wantActions := []string{"trigger", "acknowledge", "resolve"}
+	for i, req := range *requests {
+		if req.Path != "/v2/enqueue" {
+			t.Errorf("请求路径 = %s，期望 /v2/enqueue", req.Path)
+		}
+		if req.Body["event_action"] != wantActions[i] {
+			t.Errorf("第 %d 个事件动作 = %v，期望 %s", i+1, req.Body["event_action"], wantActions[i])
+		}
+		if req.Body["dedup_key"] != "pika:agent-1:global:cpu" {
+			t.Errorf("第 %d 个事件 dedup_key = %v，期望使用告警状态ID", i+1, req.Body["dedup_key"])
+		}
+	}
+	payload, ok := (*requests)[0].Body["payload"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("触发事件缺少 payload")
+	}
+	if payload["severity"] != "critical" {
+		t.Errorf("severity = %v，期望 critical", payload["severity"])
+	}
+	if _, ok := (*requests)[2].Body["payload"]; ok {
+		t.Errorf("恢复事件不应包含 payload")
+	}
+}
+
+func TestSendNotificationOpsgenieLifecycle(t *testing.T) {
+	server, requests := newCaptureServer(t, `{"result":"Request will be processed"}`)
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.0.1"}
+	channel := &models.NotificationChannelConfig{
+		Type:    "opsgenie",
+		Enabled: true,
+		Config:  map[string]interface{}{"apiKey": "key", "apiUrl": server.URL},
+	}
+	record := &models.AlertRecord{
+		ID:        11,
+		AgentID:   agent.ID,
+		AlertType: "tamper_event",
+		Message:   "tamper",
+		Level:     "warning",
+		Status:    "firing",
+	}
+
+	for _, status := range []string{"firing", "resolved"} {
+		record.Status = status
+		if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, false); err != nil {
+			t.Fatalf("发送 %s 事件失败: %v", status, err)
+		}
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("期望收到 2 个请求，实际 %d 个", len(*requests))
+	}
+	created := (*requests)[0]
+	if created.Path != "/v2/alerts" || created.Header.Get("Authorization") != "GenieKey key" {
+		t.Errorf("创建告警请求不符合预期: %s %s", created.Path, created.Header.Get("Authorization"))
+	}
+	// 没有告警状态的记录使用记录ID作为 alias
+	if created.Body["alias"] != "pika:record:11" || created.Body["priority"] != "P3" {
+		t.Errorf("创建告警请求体不符合预期: %v", created.Body)
+	}
+	if closed := (*requests)[1]; closed.Path != "/v2/alerts/pika:record:11/close" {
+		t.Errorf("关闭告警请求路径 = %s", closed.Path)
+	}
+}
+
+func TestSendNotificationAlertmanagerWebhook(t *testing.T) {
+	server, requests := newCaptureServer(t, `{}`)
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.0.1"}
+	channel := &models.NotificationChannelConfig{
+		Type:    "alertmanager",
+		Enabled: true,
+		Config:  map[string]interface{}{"url": server.URL + "/hook"},
+	}
+	record := &models.AlertRecord{
+		ID:         12,
+		AgentID:    agent.ID,
+		AlertType:  "cpu",
+		Message:    "CPU",
+		Level:      "warning",
+		Status:     "firing",
+		FiredAt:    1700000000000,
+		ResolvedAt: 1700000600000,
+	}
+
+	for _, status := range []string{"firing", "resolved"} {
+		record.Status = status
+		if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, false); err != nil {
+			t.Fatalf("发送 %s 告警失败: %v", status, err)
+		}
+		// 触发后修改探针名称和主机名，恢复通知仍应匹配原告警
+		agent.Name, agent.Hostname = "web-1-renamed", "web-1.example.com"
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("期望收到 2 个请求，实际 %d 个", len(*requests))
+	}
+	var fingerprints []interface{}
+	for i, status := range []string{"firing", "resolved"} {
+		body := (*requests)[i].Body
+		if body["version"] != "4" || body["status"] != status {
+			t.Errorf("消息 version/status 不符合预期: %v %v", body["version"], body["status"])
+		}
+		alerts, ok := body["alerts"].([]interface{})
+		if !ok || len(alerts) != 1 {
+			t.Fatalf("消息应包含 1 条告警: %v", body["alerts"])
+		}
+		alert := alerts[0].(map[string]interface{})
+		labels := alert["labels"].(map[string]interface{})
+		if labels["alertname"] != "cpu" || labels["severity"] != "warning" || labels["generator"] != "pika" || labels["agent_id"] != "agent-1" {
+			t.Errorf("告警标签不符合预期: %v", labels)
+		}
+		if _, ok := labels["agent_name"]; ok {
+			t.Errorf("可修改的探针名称不应作为标签: %v", labels)
+		}
+		if annotations := alert["annotations"].(map[string]interface{}); annotations["agent_name"] == nil {
+			t.Errorf("探针名称应放在注解中: %v", annotations)
+		}
+		if alert["startsAt"] != "2023-11-14T22:13:20Z" {
+			t.Errorf("startsAt = %v", alert["startsAt"])
+		}
+		fingerprints = append(fingerprints, alert["fingerprint"])
+	}
+	// 触发和恢复使用相同的指纹
+	if fingerprints[0] == "" || fingerprints[0] != fingerprints[1] {
+		t.Errorf("触发和恢复的指纹应一致: %v", fingerprints)
+	}
+	resolved := (*requests)[1].Body["alerts"].([]interface{})[0].(map[string]interface{})
+	if resolved["endsAt"] != "2023-11-14T22:23:20Z" {
+		t.Errorf("endsAt = %v", resolved["endsAt"])
+	}
+}
+
+func TestRefreshAlertmanagerAlertsAPI(t *testing.T) {
+	var (
+		paths  []string
+		posted [][]alertmanagerPostableAlert
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alerts []alertmanagerPostableAlert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Errorf("解析请求体失败: %v", err)
+		}
+		paths = append(paths, r.URL.Path)
+		posted = append(posted, alerts)
+	}))
+	t.Cleanup(server.Close)
+
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.0.1"}
+	record := &models.AlertRecord{ID: 12, AgentID: agent.ID, AlertType: "cpu", Level: "warning", Status: "firing", FiredAt: 1700000000000}
+	items := []AlertGroupItem{{Agent: agent, Record: record}}
+
+	webhook := &models.NotificationChannelConfig{Type: "alertmanager", Enabled: true, Config: map[string]interface{}{"url": server.URL}}
+	if err := notifier.RefreshAlertmanagerAlerts(context.Background(), webhook, items, false); err != nil {
+		t.Fatalf("webhook 模式不应重新推送: %v", err)
+	}
+	if len(posted) != 0 {
+		t.Fatalf("webhook 模式不应重新推送，实际推送 %d 次", len(posted))
+	}
+
+	api := &models.NotificationChannelConfig{Type: "alertmanager", Enabled: true, Config: map[string]interface{}{"url": server.URL, "mode": "api"}}
+	before := time.Now()
+	if err := notifier.RefreshAlertmanagerAlerts(context.Background(), api, items, false); err != nil {
+		t.Fatalf("重新推送告警失败: %v", err)
+	}
+	record.Status, record.ResolvedAt = "resolved", 1700000600000
+	if err := notifier.SendNotificationByConfig(context.Background(), api, record, agent, false); err != nil {
+		t.Fatalf("推送恢复告警失败: %v", err)
+	}
+
+	if len(posted) != 2 || paths[0] != "/api/v2/alerts" {
+		t.Fatalf("推送请求不符合预期: %v %v", paths, posted)
+	}
+	// 触发中的告警带有效期，由定期推送延长
+	endsAt, err := time.Parse(time.RFC3339, posted[0][0].EndsAt)
+	if err != nil || endsAt.Before(before.Add(alertmanagerAlertTTL).Truncate(time.Second)) || endsAt.After(time.Now().Add(alertmanagerAlertTTL)) {
+		t.Errorf("触发中告警的 endsAt 应为当前时间加有效期: %q", posted[0][0].EndsAt)
+	}
+	if posted[1][0].EndsAt != "2023-11-14T22:23:20Z" {
+		t.Errorf("恢复告警的 endsAt = %q", posted[1][0].EndsAt)
+	}
+}
+
+func TestValidateChannelConfigs(t *testing.T) {
+	valid := []models.NotificationChannelConfig{
+		{Type: "ntfy", Enabled: true, Config: map[string]interface{}{"topic": "alerts"}},
+		{Type: "email", Enabled: true, Config: map[string]interface{}{
+			"smtpHost": "smtp.example.com", "smtpPort": "465", "fromEmail": "a@example.com", "password": "x", "toEmail": "b@example.com",
+		}},
+		// 未启用的渠道不校验
+		{Type: "gotify", Enabled: false, Config: map[string]interface{}{}},
+	}
+	if err := ValidateChannelConfigs(valid); err != nil {
+		t.Fatalf("合法配置校验失败: %v", err)
+	}
+
+	invalid := map[string]models.NotificationChannelConfig{
+		"unknown":   {Type: "unknown", Enabled: true},
+		"webhook":   {Type: "webhook", Enabled: true, Config: map[string]interface{}{"url": "http://example.com", "bodyTemplate": "xml"}},
+		"smtpPort":  {Type: "email", Enabled: true, Config: map[string]interface{}{"smtpHost": "smtp.example.com", "smtpPort": "abc"}},
+		"exec":      {Type: "exec", Enabled: true, Config: map[string]interface{}{}},
+		"amBadMode": {Type: "alertmanager", Enabled: true, Config: map[string]interface{}{"url": "http://am", "mode": "grpc"}},
+	}
+	for name, channel := range invalid {
+		if err := ValidateChannelConfigs([]models.NotificationChannelConfig{channel}); err == nil {
+			t.Errorf("%s: 非法配置应校验失败", name)
+		}
+	}
+}
+
+// writeScript 在目录中写入可执行脚本
+func writeScript(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+content), 0o755); err != nil {
+		t.Fatalf("写入脚本失败: %v", err)
+	}
+	return path
+}
+
+func TestSendNotificationExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec 测试依赖 /bin/sh")
+	}
+	scriptDir := t.TempDir()
+	outFile := filepath.Join(t.TempDir(), "stdin.json")
+	writeScript(t, scriptDir, "notify.sh", "cat > \""+outFile+"\"\necho \"sent $1\"\n")
+
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{Notification: &config.NotificationConfig{ExecScriptDir: scriptDir}})
+	channel := &models.NotificationChannelConfig{
+		Type:    "exec",
+		Enabled: true,
+		Config:  map[string]interface{}{"script": "notify.sh", "args": []interface{}{"ok"}},
+	}
+	agent := &models.Agent{ID: "agent-1", Name: "web-1", Hostname: "web-1", IP: "10.0.1.2"}
+	record := &models.AlertRecord{ID: 7, StateID: "agent-1:global:cpu", AlertType: "cpu", Level: "critical", Status: "firing", Message: "CPU 过高", FiredAt: 1700000000000}
+
+	if err := notifier.SendNotificationByConfig(context.Background(), channel, record, agent, true); err != nil {
+		t.Fatalf("执行脚本失败: %v", err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("读取脚本输入失败: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("脚本输入不是 JSON: %v", err)
+	}
+	if payload["kind"] != "alert" || payload["status"] != "firing" {
+		t.Errorf("kind/status 不符合预期: %v %v", payload["kind"], payload["status"])
+	}
+	alerts := payload["alerts"].([]interface{})
+	alert := alerts[0].(map[string]interface{})
+	if alert["id"] != float64(7) || alert["level"] != "critical" || alert["stateId"] != "agent-1:global:cpu" {
+		t.Errorf("告警内容不符合预期: %v", alert)
+	}
+	if ip := alert["agent"].(map[string]interface{})["ip"]; ip != "10.0.*.*" {
+		t.Errorf("IP 应打码，实际 %v", ip)
+	}
+}
+
+func TestSendNotificationExecErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec 测试依赖 /bin/sh")
+	}
+	scriptDir := t.TempDir()
+	writeScript(t, scriptDir, "fail.sh", "echo boom >&2\nexit 3\n")
+	writeScript(t, scriptDir, "slow.sh", "sleep 5\n")
+	outside := writeScript(t, t.TempDir(), "outside.sh", "exit 0\n")
+	if err := os.Symlink(outside, filepath.Join(scriptDir, "link.sh")); err != nil {
+		t.Fatalf("创建符号链接失败: %v", err)
+	}
+	relOutside, err := filepath.Rel(scriptDir, outside)
+	if err != nil {
+		t.Fatalf("计算相对路径失败: %v", err)
+	}
+
+	notifier := NewNotifier(zap.NewNop(), &config.AppConfig{Notification: &config.NotificationConfig{ExecScriptDir: scriptDir}})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "非 0 退出", config: map[string]interface{}{"script": "fail.sh"}, wantErr: "boom"},
+		{name: "超时", config: map[string]interface{}{"script": "slow.sh", "timeout": 1}, wantErr: "脚本执行超时"},
+		{name: "目录外脚本", config: map[string]interface{}{"script": outside}, wantErr: "脚本必须位于脚本目录内"},
+		{name: "相对路径逃逸", config: map[string]interface{}{"script": relOutside}, wantErr: "脚本必须位于脚本目录内"},
+		{name: "符号链接逃逸", config: map[string]interface{}{"script": "link.sh"}, wantErr: "脚本必须位于脚本目录内"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := notifier.SendTestNotification(ctx, "exec", tt.config, "hello")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("期望包含 %q 的错误，实际 %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// 未配置脚本目录时禁用 exec 渠道
+	disabled := NewNotifier(zap.NewNop(), &config.AppConfig{})
+	err = disabled.SendTestNotification(ctx, "exec", map[string]interface{}{"script": "fail.sh"}, "hello")
+	if err == nil || !strings.Contains(err.Error(), "exec 渠道已禁用") {
+		t.Fatalf("未配置脚本目录时应禁用 exec 渠道，实际 %v", err)
+	}
+}
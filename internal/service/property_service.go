@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/repo"
 	"github.com/dushixiang/pika/web"
@@ -25,6 +26,8 @@ const (
 	PropertyIDAlertConfig = "alert_config"
 	// PropertyIDDNSProviders DNS 服务商配置的固定 ID
 	PropertyIDDNSProviders = "dns_providers"
+	// PropertyIDNotificationRoutes 通知路由规则的固定 ID
+	PropertyIDNotificationRoutes = "notification_routes"
 )
 
 type PropertyService struct {
@@ -99,6 +102,10 @@ func (s *PropertyService) Set(ctx context.Context, id string, name string, value
 	// 清空缓存中的该项，下次读取时会重新从数据库加载
 	s.cache.Delete(id)
 
+	if id == PropertyIDSystemConfig {
+		s.applyLocale(ctx)
+	}
+
 	return nil
 }
 
@@ -111,6 +118,16 @@ func (s *PropertyService) GetNotificationChannelConfigs(ctx context.Context) ([]
 	return allChannels, nil
 }
 
+// GetNotificationRoutes 获取通知路由规则
+func (s *PropertyService) GetNotificationRoutes(ctx context.Context) ([]models.NotificationRoute, error) {
+	var routes []models.NotificationRoute
+	err := s.GetValue(ctx, PropertyIDNotificationRoutes, &routes)
+	if err != nil {
+		return nil, fmt.Errorf("获取通知路由规则失败: %w", err)
+	}
+	return routes, nil
+}
+
 func (s *PropertyService) GetSystemConfig(ctx context.Context) (*models.SystemConfig, error) {
 	var systemConfig models.SystemConfig
 	err := s.GetValue(ctx, PropertyIDSystemConfig, &systemConfig)
@@ -229,6 +246,7 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 				LogoBase64:   web.DefaultLogoBase64(),
 				ICPCode:      "",
 				DefaultView:  "grid",
+				Locale:       i18n.LocaleZh,
 			},
 		},
 		{
@@ -260,9 +278,19 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 					ServiceDuration:      300, // 5分钟
 					AgentOfflineEnabled:  true,
 					AgentOfflineDuration: 300, // 5分钟
+					TamperEventEnabled:   true,
+					TamperAttrEnabled:    true,
+					TamperRateLimit:      300, // 5分钟
+					DDNSFailureEnabled:   true,
+					DDNSFailureThreshold: 3,
 				},
 			},
 		},
+		{
+			ID:    PropertyIDNotificationRoutes,
+			Name:  "通知路由规则",
+			Value: []models.NotificationRoute{},
+		},
 		{
 			ID:    PropertyIDDNSProviders,
 			Name:  "DNS 服务商配置",
@@ -278,9 +306,20 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 	}
 
 	s.logger.Info("默认配置初始化完成")
+	s.applyLocale(ctx)
 	return nil
 }
 
+// applyLocale 按系统配置设置服务端消息语言
+func (s *PropertyService) applyLocale(ctx context.Context) {
+	systemConfig, err := s.GetSystemConfig(ctx)
+	if err != nil {
+		s.logger.Warn("读取系统语言失败", zap.Error(err))
+		return
+	}
+	i18n.SetLocale(systemConfig.Locale)
+}
+
 // initializeProperty 初始化单个配置项
 func (s *PropertyService) initializeProperty(ctx context.Context, config defaultPropertyConfig) error {
 	// 检查配置是否已存在
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dushixiang/pika/internal/models"
@@ -16,16 +19,18 @@ import (
 )
 
 type TamperService struct {
-	logger     *zap.Logger
-	tamperRepo *repo.TamperRepo
-	wsManager  *websocket.Manager
+	logger       *zap.Logger
+	tamperRepo   *repo.TamperRepo
+	wsManager    *websocket.Manager
+	alertService *AlertService
 }
 
-func NewTamperService(logger *zap.Logger, tamperRepo *repo.TamperRepo, wsManager *websocket.Manager) *TamperService {
+func NewTamperService(logger *zap.Logger, tamperRepo *repo.TamperRepo, wsManager *websocket.Manager, alertService *AlertService) *TamperService {
 	return &TamperService{
-		logger:     logger,
-		tamperRepo: tamperRepo,
-		wsManager:  wsManager,
+		logger:       logger,
+		tamperRepo:   tamperRepo,
+		wsManager:    wsManager,
+		alertService: alertService,
 	}
 }
 
@@ -159,8 +164,8 @@ func (s *TamperService) DeleteConfig(agentID string) error {
 	return s.tamperRepo.DeleteConfig(agentID)
 }
 
-// CreateEvent 创建防篡改事件
-func (s *TamperService) CreateEvent(agentID, path, operation, details string, timestamp int64) error {
+// CreateEvent 创建防篡改事件并触发告警
+func (s *TamperService) CreateEvent(ctx context.Context, agentID, path, operation, details string, timestamp int64) error {
 	event := &models.TamperEvent{
 		ID:        uuid.New().String(),
 		AgentID:   agentID,
@@ -170,7 +175,17 @@ func (s *TamperService) CreateEvent(agentID, path, operation, details string, ti
 		Timestamp: timestamp,
 		CreatedAt: time.Now().UnixMilli(),
 	}
-	return s.tamperRepo.CreateEvent(event)
+	if err := s.tamperRepo.CreateEvent(event); err != nil {
+		return err
+	}
+
+	s.raiseAlert(ctx, agentID, &TamperAlert{
+		AlertType: AlertTypeTamperEvent,
+		Path:      path,
+		Operation: operation,
+		Details:   details,
+	})
+	return nil
 }
 
 // GetEventsByAgentID 获取探针的防篡改事件
@@ -189,8 +204,8 @@ func (s *TamperService) GetEventsByAgentID(agentID string, pageNum, pageSize int
 	return s.tamperRepo.GetEventsByAgentID(agentID, pageSize, offset)
 }
 
-// CreateAlert 创建防篡改告警
-func (s *TamperService) CreateAlert(agentID, path, details string, restored bool, timestamp int64) error {
+// CreateAlert 创建防篡改属性告警并触发告警
+func (s *TamperService) CreateAlert(ctx context.Context, agentID, path, details string, restored bool, timestamp int64) error {
 	alert := &models.TamperAlert{
 		ID:        uuid.New().String(),
 		AgentID:   agentID,
@@ -200,7 +215,52 @@ func (s *TamperService) CreateAlert(agentID, path, details string, restored bool
 		Timestamp: timestamp,
 		CreatedAt: time.Now().UnixMilli(),
 	}
-	return s.tamperRepo.CreateAlert(alert)
+	if err := s.tamperRepo.CreateAlert(alert); err != nil {
+		return err
+	}
+
+	s.raiseAlert(ctx, agentID, &TamperAlert{
+		AlertType: AlertTypeTamperAttr,
+		Path:      path,
+		Details:   details,
+		Restored:  restored,
+	})
+	return nil
+}
+
+// raiseAlert 交给告警服务处理，告警失败不影响事件记录
+func (s *TamperService) raiseAlert(ctx context.Context, agentID string, alert *TamperAlert) {
+	alert.Root = s.protectedRoot(agentID, alert.Path)
+	if err := s.alertService.HandleTamperAlert(ctx, agentID, alert); err != nil {
+		s.logger.Error("处理防篡改告警失败",
+			zap.String("agentId", agentID),
+			zap.String("path", alert.Path),
+			zap.Error(err))
+	}
+}
+
+// protectedRoot 查找路径所属的受保护目录，找不到时返回路径所在目录
+func (s *TamperService) protectedRoot(agentID, path string) string {
+	root := filepath.Dir(path)
+	config, err := s.GetConfigByAgentID(agentID)
+	if err != nil || config == nil {
+		return root
+	}
+
+	matched := ""
+	for _, p := range config.Paths {
+		p = filepath.Clean(p)
+		if path != p && !strings.HasPrefix(path, p+string(filepath.Separator)) {
+			continue
+		}
+		if len(p) > len(matched) {
+			matched = p
+		}
+	}
+	if matched == "" {
+		return root
+	}
+	return matched
 }
 
 // GetAlertsByAgentID 获取探针的防篡改告警
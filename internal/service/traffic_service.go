@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/repo"
 	"go.uber.org/zap"
@@ -104,8 +106,9 @@ func (s *TrafficService) sendTrafficAlert(ctx context.Context, agent *models.Age
 		AgentID:   agent.ID,
 		AgentName: agent.Name,
 		AlertType: "traffic",
-		Message: fmt.Sprintf("流量使用已达到%d%%，当前使用%.2f%%（%s/%s）",
-			threshold, actualPercent,
+		Message: i18n.T("alert.msg.traffic",
+			strconv.Itoa(threshold),
+			fmt.Sprintf("%.2f", actualPercent),
 			formatBytes(agent.TrafficUsed),
 			formatBytes(agent.TrafficLimit)),
 		Threshold:   float64(threshold),
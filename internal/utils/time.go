@@ -1,8 +1,10 @@
 package utils
 
 import (
-	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/dushixiang/pika/internal/i18n"
 )
 
 // FormatTimestamp 格式化时间戳（毫秒）为字符串
@@ -21,17 +23,17 @@ func FormatDuration(durationMs int64) string {
 
 	durationSec := durationMs / 1000
 	if durationSec < 60 {
-		return fmt.Sprintf("%d秒", durationSec)
+		return i18n.T("duration.seconds", strconv.FormatInt(durationSec, 10))
 	}
 
 	if durationSec < 3600 {
 		minutes := durationSec / 60
 		seconds := durationSec % 60
-		return fmt.Sprintf("%d分%d秒", minutes, seconds)
+		return i18n.T("duration.minutes", strconv.FormatInt(minutes, 10), strconv.FormatInt(seconds, 10))
 	}
 
 	hours := durationSec / 3600
 	minutes := (durationSec % 3600) / 60
 	seconds := durationSec % 60
-	return fmt.Sprintf("%d时%d分%d秒", hours, minutes, seconds)
+	return i18n.T("duration.hours", strconv.FormatInt(hours, 10), strconv.FormatInt(minutes, 10), strconv.FormatInt(seconds, 10))
 }
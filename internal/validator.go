@@ -6,32 +6,34 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/go-playground/locales/en"
-	"github.com/go-playground/locales/zh"
-	ut "github.com/go-playground/universal-translator"
+	"github.com/dushixiang/pika/internal/i18n"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
 	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
 	"github.com/labstack/echo/v4"
 )
 
 type CustomValidator struct {
 	Validator *validator.Validate
-	trans     ut.Translator
 }
 
 func (cv *CustomValidator) TransInit() error {
-	zhT := zh.New() //chinese
-	enT := en.New() //english
-	uni := ut.New(enT, zhT, enT)
+	uni := i18n.UniversalTranslator()
 
-	trans, ok := uni.GetTranslator("zh")
+	zhTrans, ok := uni.GetTranslator(i18n.LocaleZh)
 	if !ok {
 		return fmt.Errorf("uni.GetTranslator zh failed")
 	}
-	cv.trans = trans
+	enTrans, ok := uni.GetTranslator(i18n.LocaleEn)
+	if !ok {
+		return fmt.Errorf("uni.GetTranslator en failed")
+	}
 	//register translate
-	// 注册翻译器
-	return zhTranslations.RegisterDefaultTranslations(cv.Validator, trans)
+	// 注册翻译器，校验错误按当前语言输出
+	if err := zhTranslations.RegisterDefaultTranslations(cv.Validator, zhTrans); err != nil {
+		return err
+	}
+	return enTranslations.RegisterDefaultTranslations(cv.Validator, enTrans)
 }
 
 func (cv *CustomValidator) Validate(i interface{}) error {
@@ -41,7 +43,7 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 		if !ok {
 			return err
 		}
-		translate := errs.Translate(cv.trans)
+		translate := errs.Translate(i18n.Translator())
 		var messages []string
 		for _, msg := range translate {
 			messages = append(messages, msg)
@@ -35,6 +35,7 @@ type ResultData struct {
 type Result struct {
 	Metric map[string]string `json:"metric"`
 	Values [][]interface{}   `json:"values"` // [[timestamp, value], ...]
+	Value  []interface{}     `json:"value"`  // [timestamp, value]，即时查询(vector)结果
 }
 
 // DataPoint 数据点
@@ -261,6 +262,43 @@ func ConvertToDataPoints(result *QueryResult) []DataPoint {
 	return points
 }
 
+// ConvertToInstantPoints 将即时查询(vector)结果转换为数据点列表，每个时间序列一个数据点
+func ConvertToInstantPoints(result *QueryResult) []DataPoint {
+	if result == nil || len(result.Data.Result) == 0 {
+		return []DataPoint{}
+	}
+
+	var points []DataPoint
+	for _, r := range result.Data.Result {
+		if len(r.Value) < 2 {
+			continue
+		}
+
+		timestamp, ok := r.Value[0].(float64)
+		if !ok {
+			continue
+		}
+
+		valueStr, ok := r.Value[1].(string)
+		if !ok {
+			continue
+		}
+
+		var value float64
+		if _, err := fmt.Sscanf(valueStr, "%f", &value); err != nil {
+			continue
+		}
+
+		points = append(points, DataPoint{
+			Timestamp: int64(timestamp * 1000),
+			Value:     value,
+			Labels:    r.Metric,
+		})
+	}
+
+	return points
+}
+
 // GetLabelValues 获取指定 label 的所有值
 func (c *VMClient) GetLabelValues(ctx context.Context, labelName string, match []string) ([]string, error) {
 	reqCtx, cancel := context.WithTimeout(ctx, c.queryTimeout)
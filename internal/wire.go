@@ -30,6 +30,10 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		service.NewGitHubOAuthService,
 		service.NewApiKeyService,
 		service.NewAlertService,
+		service.NewAlertRuleSetService,
+		service.NewAlertExpressionRuleService,
+		service.NewAlertSilenceService,
+		service.NewMaintenanceService,
 		service.NewPropertyService,
 		service.NewMonitorService,
 		service.NewTamperService,
@@ -37,6 +41,7 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		service.NewMetricService,
 		service.NewGeoIPService,
 		service.NewDDNSService,
+		service.NewNotificationDeliveryService,
 
 		service.NewNotifier,
 		// WebSocket Manager
@@ -50,6 +55,10 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		// Handlers
 		handler.NewAgentHandler,
 		handler.NewAlertHandler,
+		handler.NewAlertRuleSetHandler,
+		handler.NewAlertExpressionRuleHandler,
+		handler.NewAlertSilenceHandler,
+		handler.NewMaintenanceWindowHandler,
 		handler.NewPropertyHandler,
 		handler.NewMonitorHandler,
 		handler.NewApiKeyHandler,
@@ -57,6 +66,7 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		handler.NewTamperHandler,
 		handler.NewDNSProviderHandler,
 		handler.NewDDNSHandler,
+		handler.NewNotificationDeliveryHandler,
 
 		// App Components
 		wire.Struct(new(AppComponents), "*"),
@@ -66,24 +76,31 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 
 // AppComponents 应用组件
 type AppComponents struct {
-	AccountHandler     *handler.AccountHandler
-	AgentHandler       *handler.AgentHandler
-	ApiKeyHandler      *handler.ApiKeyHandler
-	AlertHandler       *handler.AlertHandler
-	PropertyHandler    *handler.PropertyHandler
-	MonitorHandler     *handler.MonitorHandler
-	TamperHandler      *handler.TamperHandler
-	DNSProviderHandler *handler.DNSProviderHandler
-	DDNSHandler        *handler.DDNSHandler
-
-	AgentService    *service.AgentService
-	MetricService   *service.MetricService
-	AlertService    *service.AlertService
-	PropertyService *service.PropertyService
-	MonitorService  *service.MonitorService
-	ApiKeyService   *service.ApiKeyService
-	TamperService   *service.TamperService
-	DDNSService     *service.DDNSService
+	AccountHandler              *handler.AccountHandler
+	AgentHandler                *handler.AgentHandler
+	ApiKeyHandler               *handler.ApiKeyHandler
+	AlertHandler                *handler.AlertHandler
+	AlertRuleSetHandler         *handler.AlertRuleSetHandler
+	AlertExpressionRuleHandler  *handler.AlertExpressionRuleHandler
+	AlertSilenceHandler         *handler.AlertSilenceHandler
+	MaintenanceWindowHandler    *handler.MaintenanceWindowHandler
+	PropertyHandler             *handler.PropertyHandler
+	MonitorHandler              *handler.MonitorHandler
+	TamperHandler               *handler.TamperHandler
+	DNSProviderHandler          *handler.DNSProviderHandler
+	DDNSHandler                 *handler.DDNSHandler
+	NotificationDeliveryHandler *handler.NotificationDeliveryHandler
+
+	AgentService                *service.AgentService
+	MetricService               *service.MetricService
+	AlertService                *service.AlertService
+	PropertyService             *service.PropertyService
+	MonitorService              *service.MonitorService
+	ApiKeyService               *service.ApiKeyService
+	TamperService               *service.TamperService
+	DDNSService                 *service.DDNSService
+	MaintenanceService          *service.MaintenanceService
+	NotificationDeliveryService *service.NotificationDeliveryService
 
 	WSManager *websocket.Manager
 	VMClient  *vmclient.VMClient
@@ -37,43 +37,60 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		return nil, err
 	}
 	agentService := service.NewAgentService(logger, db, apiKeyService, metricService, geoIPService)
+	maintenanceService := service.NewMaintenanceService(logger, db)
 	manager := websocket.NewManager(logger)
-	monitorService := service.NewMonitorService(logger, db, metricService, manager)
+	monitorService := service.NewMonitorService(logger, db, metricService, maintenanceService, manager)
 	tamperRepo := repo.NewTamperRepo(db)
-	tamperService := service.NewTamperService(logger, tamperRepo, manager)
+	alertRuleSetService := service.NewAlertRuleSetService(logger, db)
+	alertExpressionRuleService := service.NewAlertExpressionRuleService(logger, db, vmClient)
+	alertSilenceService := service.NewAlertSilenceService(logger, db)
+	notifier := service.NewNotifier(logger, cfg)
+	notificationDeliveryService := service.NewNotificationDeliveryService(logger, db, propertyService, notifier)
+	alertService := service.NewAlertService(logger, db, propertyService, monitorService, alertRuleSetService, alertExpressionRuleService, alertSilenceService, maintenanceService, notificationDeliveryService, vmClient)
+	tamperService := service.NewTamperService(logger, tamperRepo, manager, alertService)
 	ddnsConfigRepo := repo.NewDDNSConfigRepo(db)
 	ddnsRecordRepo := repo.NewDDNSRecordRepo(db)
-	ddnsService := service.NewDDNSService(logger, ddnsConfigRepo, ddnsRecordRepo, propertyService, manager)
+	ddnsService := service.NewDDNSService(logger, ddnsConfigRepo, ddnsRecordRepo, propertyService, manager, alertService)
 	agentHandler := handler.NewAgentHandler(logger, agentService, metricService, monitorService, tamperService, ddnsService, manager)
 	apiKeyHandler := handler.NewApiKeyHandler(logger, apiKeyService)
-	notifier := service.NewNotifier(logger)
-	alertService := service.NewAlertService(logger, db, propertyService, monitorService, notifier)
 	alertHandler := handler.NewAlertHandler(logger, alertService)
-	propertyHandler := handler.NewPropertyHandler(logger, propertyService, notifier)
+	alertRuleSetHandler := handler.NewAlertRuleSetHandler(logger, alertRuleSetService)
+	alertExpressionRuleHandler := handler.NewAlertExpressionRuleHandler(logger, alertExpressionRuleService, alertService)
+	alertSilenceHandler := handler.NewAlertSilenceHandler(logger, alertSilenceService)
+	maintenanceWindowHandler := handler.NewMaintenanceWindowHandler(logger, maintenanceService)
+	propertyHandler := handler.NewPropertyHandler(logger, propertyService, agentService, notifier)
 	monitorHandler := handler.NewMonitorHandler(logger, monitorService, metricService, agentService)
 	tamperHandler := handler.NewTamperHandler(logger, tamperService)
 	dnsProviderHandler := handler.NewDNSProviderHandler(logger, propertyService)
 	ddnsHandler := handler.NewDDNSHandler(logger, ddnsService)
+	notificationDeliveryHandler := handler.NewNotificationDeliveryHandler(logger, notificationDeliveryService)
 	appComponents := &AppComponents{
-		AccountHandler:     accountHandler,
-		AgentHandler:       agentHandler,
-		ApiKeyHandler:      apiKeyHandler,
-		AlertHandler:       alertHandler,
-		PropertyHandler:    propertyHandler,
-		MonitorHandler:     monitorHandler,
-		TamperHandler:      tamperHandler,
-		DNSProviderHandler: dnsProviderHandler,
-		DDNSHandler:        ddnsHandler,
-		AgentService:       agentService,
-		MetricService:      metricService,
-		AlertService:       alertService,
-		PropertyService:    propertyService,
-		MonitorService:     monitorService,
-		ApiKeyService:      apiKeyService,
-		TamperService:      tamperService,
-		DDNSService:        ddnsService,
-		WSManager:          manager,
-		VMClient:           vmClient,
+		AccountHandler:              accountHandler,
+		AgentHandler:                agentHandler,
+		ApiKeyHandler:               apiKeyHandler,
+		AlertHandler:                alertHandler,
+		AlertRuleSetHandler:         alertRuleSetHandler,
+		AlertExpressionRuleHandler:  alertExpressionRuleHandler,
+		AlertSilenceHandler:         alertSilenceHandler,
+		MaintenanceWindowHandler:    maintenanceWindowHandler,
+		PropertyHandler:             propertyHandler,
+		MonitorHandler:              monitorHandler,
+		TamperHandler:               tamperHandler,
+		DNSProviderHandler:          dnsProviderHandler,
+		DDNSHandler:                 ddnsHandler,
+		NotificationDeliveryHandler: notificationDeliveryHandler,
+		AgentService:                agentService,
+		MetricService:               metricService,
+		AlertService:                alertService,
+		PropertyService:             propertyService,
+		MonitorService:              monitorService,
+		ApiKeyService:               apiKeyService,
+		TamperService:               tamperService,
+		DDNSService:                 ddnsService,
+		MaintenanceService:          maintenanceService,
+		NotificationDeliveryService: notificationDeliveryService,
+		WSManager:                   manager,
+		VMClient:                    vmClient,
 	}
 	return appComponents, nil
 }
@@ -82,24 +99,31 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 
 // AppComponents 应用组件
 type AppComponents struct {
-	AccountHandler     *handler.AccountHandler
-	AgentHandler       *handler.AgentHandler
-	ApiKeyHandler      *handler.ApiKeyHandler
-	AlertHandler       *handler.AlertHandler
-	PropertyHandler    *handler.PropertyHandler
-	MonitorHandler     *handler.MonitorHandler
-	TamperHandler      *handler.TamperHandler
-	DNSProviderHandler *handler.DNSProviderHandler
-	DDNSHandler        *handler.DDNSHandler
-
-	AgentService    *service.AgentService
-	MetricService   *service.MetricService
-	AlertService    *service.AlertService
-	PropertyService *service.PropertyService
-	MonitorService  *service.MonitorService
-	ApiKeyService   *service.ApiKeyService
-	TamperService   *service.TamperService
-	DDNSService     *service.DDNSService
+	AccountHandler              *handler.AccountHandler
+	AgentHandler                *handler.AgentHandler
+	ApiKeyHandler               *handler.ApiKeyHandler
+	AlertHandler                *handler.AlertHandler
+	AlertRuleSetHandler         *handler.AlertRuleSetHandler
+	AlertExpressionRuleHandler  *handler.AlertExpressionRuleHandler
+	AlertSilenceHandler         *handler.AlertSilenceHandler
+	MaintenanceWindowHandler    *handler.MaintenanceWindowHandler
+	PropertyHandler             *handler.PropertyHandler
+	MonitorHandler              *handler.MonitorHandler
+	TamperHandler               *handler.TamperHandler
+	DNSProviderHandler          *handler.DNSProviderHandler
+	DDNSHandler                 *handler.DDNSHandler
+	NotificationDeliveryHandler *handler.NotificationDeliveryHandler
+
+	AgentService                *service.AgentService
+	MetricService               *service.MetricService
+	AlertService                *service.AlertService
+	PropertyService             *service.PropertyService
+	MonitorService              *service.MonitorService
+	ApiKeyService               *service.ApiKeyService
+	TamperService               *service.TamperService
+	DDNSService                 *service.DDNSService
+	MaintenanceService          *service.MaintenanceService
+	NotificationDeliveryService *service.NotificationDeliveryService
 
 	WSManager *websocket.Manager
 	VMClient  *vmclient.VMClient
@@ -8,6 +8,7 @@ import (
 	"github.com/dushixiang/pika/internal/protocol"
 
 	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
 )
 
 // CPUCollector CPU 监控采集器
@@ -53,6 +54,9 @@ func (c *CPUCollector) init() {
 func (c *CPUCollector) Collect() (*protocol.CPUData, error) {
 	c.init()
 
+	// 采样前后的 CPU 时间，用于计算 steal 和 iowait 占比
+	before, beforeErr := cpu.Times(false)
+
 	// 获取 CPU 总体使用率
 	percentages, err := cpu.Percent(time.Second, false)
 	if err != nil {
@@ -64,10 +68,42 @@ func (c *CPUCollector) Collect() (*protocol.CPUData, error) {
 		cpuPercent = percentages[0]
 	}
 
-	return &protocol.CPUData{
+	cpuData := &protocol.CPUData{
 		LogicalCores:  c.logicalCores,
 		PhysicalCores: c.physicalCores,
 		ModelName:     c.modelName,
 		UsagePercent:  cpuPercent,
-	}, nil
+	}
+
+	after, afterErr := cpu.Times(false)
+	if beforeErr == nil && afterErr == nil && len(before) > 0 && len(after) > 0 {
+		cpuData.StealPercent, cpuData.IOWaitPercent = stealAndIOWaitPercent(before[0], after[0])
+	}
+
+	// 获取系统负载
+	if avg, err := load.Avg(); err == nil {
+		cpuData.Load = &protocol.LoadData{
+			Load1:  avg.Load1,
+			Load5:  avg.Load5,
+			Load15: avg.Load15,
+		}
+	}
+
+	return cpuData, nil
+}
+
+// stealAndIOWaitPercent 根据两次采样的 CPU 时间计算 steal 和 iowait 占比
+func stealAndIOWaitPercent(before, after cpu.TimesStat) (steal, iowait float64) {
+	total := cpuTotalTime(after) - cpuTotalTime(before)
+	if total <= 0 {
+		return 0, 0
+	}
+	steal = (after.Steal - before.Steal) / total * 100
+	iowait = (after.Iowait - before.Iowait) / total * 100
+	return max(steal, 0), max(iowait, 0)
+}
+
+// cpuTotalTime CPU 总时间（guest 时间已包含在 user 和 nice 中）
+func cpuTotalTime(t cpu.TimesStat) float64 {
+	return t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
 }
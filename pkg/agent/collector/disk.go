@@ -52,6 +52,11 @@ func (d *DiskCollector) Collect() ([]protocol.DiskData, error) {
 			Used:         usage.Used,
 			Free:         usage.Free,
 			UsagePercent: usage.UsedPercent,
+
+			InodesTotal:        usage.InodesTotal,
+			InodesUsed:         usage.InodesUsed,
+			InodesFree:         usage.InodesFree,
+			InodesUsagePercent: usage.InodesUsedPercent,
 		}
 
 		diskDataList = append(diskDataList, diskData)
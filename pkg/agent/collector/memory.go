@@ -44,6 +44,7 @@ func (m *MemoryCollector) Collect() (*protocol.MemoryData, error) {
 		memData.SwapTotal = swapStat.Total
 		memData.SwapUsed = swapStat.Used
 		memData.SwapFree = swapStat.Free
+		memData.SwapUsagePercent = swapStat.UsedPercent
 	}
 
 	return memData, nil